@@ -0,0 +1,169 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+// newExecCmd builds `dogssh exec [--tag T] [--query Q] -- <command>`.
+func newExecCmd(serverService ports.ServerService) *cobra.Command {
+	var (
+		tags        []string
+		query       string
+		concurrency int
+		timeout     time.Duration
+		jsonPath    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "exec [flags] -- <command>",
+		Short: "Run a non-interactive command on many servers",
+		Example: `  dogssh exec --tag prod -- uptime
+  dogssh exec --query web --json results.json -- df -h /`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			servers, err := selectServers(serverService, query, tags)
+			if err != nil {
+				return err
+			}
+			if len(servers) == 0 {
+				return fmt.Errorf("no servers match the given filters")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			command := strings.Join(args, " ")
+			opts := domain.ExecOptions{Concurrency: concurrency, Timeout: timeout}
+			results := serverService.RunCommand(ctx, aliasesOf(servers), command, opts, nil)
+
+			printExecResults(cmd, results)
+
+			if jsonPath != "" {
+				if err := writeJSON(jsonPath, results); err != nil {
+					return err
+				}
+			}
+
+			if failed := countFailed(results); failed > 0 {
+				return fmt.Errorf("%d of %d servers failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&tags, "tag", "t", nil, "only servers with this tag (repeatable)")
+	cmd.Flags().StringVarP(&query, "query", "q", "", "only servers matching this search query, e.g. 'tag:prod !user:root'")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", 10, "maximum number of hosts contacted at once")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "per-host timeout")
+	cmd.Flags().StringVar(&jsonPath, "json", "", "also write results as JSON to this file")
+	return cmd
+}
+
+// selectServers lists servers matching query that carry every tag in tags.
+func selectServers(serverService ports.ServerService, query string, tags []string) ([]domain.Server, error) {
+	servers, err := serverService.ListServers(query)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]domain.Server, 0, len(servers))
+	for _, server := range servers {
+		if hasAllTags(server, tags) {
+			selected = append(selected, server)
+		}
+	}
+	return selected, nil
+}
+
+func hasAllTags(server domain.Server, tags []string) bool {
+	for _, want := range tags {
+		found := false
+		for _, tag := range server.Tags {
+			if strings.EqualFold(tag, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func aliasesOf(servers []domain.Server) []string {
+	aliases := make([]string, 0, len(servers))
+	for _, server := range servers {
+		aliases = append(aliases, server.Alias)
+	}
+	return aliases
+}
+
+func printExecResults(cmd *cobra.Command, results []domain.ExecResult) {
+	out := cmd.OutOrStdout()
+	for _, r := range results {
+		status := fmt.Sprintf("exit %d", r.ExitCode)
+		if r.Error != "" {
+			status = r.Error
+		}
+		_, _ = fmt.Fprintf(out, "==> %s (%s, %s)\n", r.Alias, status, r.Duration.Round(time.Millisecond))
+		if r.Stdout != "" {
+			_, _ = fmt.Fprint(out, ensureNewline(r.Stdout))
+		}
+		if r.Stderr != "" {
+			_, _ = fmt.Fprint(cmd.ErrOrStderr(), ensureNewline(r.Stderr))
+		}
+	}
+	_, _ = fmt.Fprintf(out, "\n%d ok, %d failed\n", len(results)-countFailed(results), countFailed(results))
+}
+
+func countFailed(results []domain.ExecResult) int {
+	failed := 0
+	for _, r := range results {
+		if r.Failed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+func ensureNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
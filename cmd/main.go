@@ -15,16 +15,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/ChengzeHsiao/dogssh/internal/adapters/data/settings_file"
 	"github.com/ChengzeHsiao/dogssh/internal/adapters/data/ssh_config_file"
 	"github.com/ChengzeHsiao/dogssh/internal/logger"
 
 	"github.com/ChengzeHsiao/dogssh/internal/adapters/ui"
 	"github.com/ChengzeHsiao/dogssh/internal/core/services"
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 )
 
 var (
@@ -33,7 +36,28 @@ var (
 )
 
 func main() {
-	log, err := logger.New("DOGSSH")
+	home, err := os.UserHomeDir()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	sshConfigFile := filepath.Join(home, ".ssh", "config")
+	metaDataFile := filepath.Join(home, ".dogssh", "metadata.json")
+	settingsFile := filepath.Join(home, ".dogssh", "config.yaml")
+	tunnelStateFile := filepath.Join(home, ".dogssh", "tunnels.json")
+	rotationDir := filepath.Join(home, ".dogssh", "rotations")
+	viewsFile := filepath.Join(home, ".dogssh", "views.json")
+	historyFile := filepath.Join(home, ".dogssh", "command_history")
+	stateFile := filepath.Join(home, ".dogssh", "state.json")
+
+	// Settings are read before the logger exists because they configure it.
+	settings, settingsErr := settings_file.NewRepository(zap.NewNop().Sugar(), settingsFile).Load()
+
+	log, err := logger.New("DOGSSH", string(settings.Log.Level), services.ExpandHome(settings.Log.Path))
+	if err != nil {
+		settingsErr = errors.Join(settingsErr, fmt.Errorf("log: %w", err))
+		log, err = logger.New("DOGSSH", string(settings.Log.Level))
+	}
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -42,18 +66,32 @@ func main() {
 	//nolint:errcheck // log.Sync may return an error which is safe to ignore here
 	defer log.Sync()
 
-	home, err := os.UserHomeDir()
+	if settingsErr != nil {
+		log.Warnw("invalid settings, using defaults for them", "error", settingsErr)
+	}
+
+	executable, err := os.Executable()
 	if err != nil {
-		log.Errorw("failed to get user home directory", "error", err)
-		//nolint:gocritic // exitAfterDefer: ensure immediate exit on unrecoverable error
-		os.Exit(1)
+		log.Warnw("failed to resolve executable path", "error", err)
+		executable = ui.AppName
 	}
-	sshConfigFile := filepath.Join(home, ".ssh", "config")
-	metaDataFile := filepath.Join(home, ".dogssh", "metadata.json")
 
 	serverRepo := ssh_config_file.NewRepository(log, sshConfigFile, metaDataFile)
 	serverService := services.NewServerService(log, serverRepo)
-	tui := ui.NewTUI(log, serverService, version, gitCommit)
+	tmuxService := services.NewTmuxService(log, executable)
+	tunnelService := services.NewTunnelService(log, serverRepo, tunnelStateFile)
+	fileService := services.NewFileService(log, serverRepo)
+	transferService := services.NewTransferService(log, fileService)
+	keyService := services.NewKeyService(log, serverRepo, filepath.Join(home, ".ssh"))
+	rotationService := services.NewRotationService(log, serverRepo, keyService, rotationDir)
+	knownHostsService := services.NewKnownHostsService(log, filepath.Join(home, ".ssh", "known_hosts"))
+	agentService := services.NewAgentService(log, os.Getenv("SSH_AUTH_SOCK"))
+	certService := services.NewCertificateService(log, serverRepo, settings.Certificates)
+	viewService := services.NewViewService(log, viewsFile)
+	bulkEditService := services.NewBulkEditService(log, serverRepo)
+	commandHistory := services.NewCommandHistory(log, historyFile)
+	uiState := services.NewUIStateService(log, stateFile)
+	tui := ui.NewTUI(log, serverService, tmuxService, tunnelService, fileService, transferService, keyService, rotationService, knownHostsService, agentService, certService, viewService, bulkEditService, commandHistory, uiState, settings, settingsErr, version, gitCommit)
 
 	rootCmd := &cobra.Command{
 		Use:   ui.AppName,
@@ -63,6 +101,24 @@ func main() {
 		},
 	}
 	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+
+	connectCmd := &cobra.Command{
+		Use:   "connect <alias>",
+		Short: "Open an SSH session to a server from your SSH config",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// A failed renewal is reported but ssh may still get in with other credentials.
+			if _, err := certService.RenewIfNeeded(args[0]); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "%s: certificate renewal failed: %v\n", ui.AppName, err)
+			}
+			return serverService.SSH(args[0])
+		},
+	}
+	rootCmd.AddCommand(connectCmd)
+	rootCmd.AddCommand(newExecCmd(serverService))
+	rootCmd.AddCommand(newPushCmd(serverService, transferService))
+	rootCmd.AddCommand(newPullCmd(serverService, transferService))
 
 	if err := rootCmd.Execute(); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,152 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+// transferFlags are shared by push and pull.
+type transferFlags struct {
+	tags        []string
+	query       string
+	concurrency int
+	retries     int
+	noVerify    bool
+	jsonPath    string
+}
+
+func (f *transferFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&f.tags, "tag", "t", nil, "only servers with this tag (repeatable)")
+	cmd.Flags().StringVarP(&f.query, "query", "q", "", "only servers matching this search query")
+	cmd.Flags().IntVarP(&f.concurrency, "concurrency", "c", 4, "maximum number of hosts copied to at once")
+	cmd.Flags().IntVar(&f.retries, "retries", 2, "how often a failed host is retried")
+	cmd.Flags().BoolVar(&f.noVerify, "no-verify", false, "skip the SHA-256 comparison after copying")
+	cmd.Flags().StringVar(&f.jsonPath, "json", "", "also write the summary as JSON to this file")
+}
+
+// newPushCmd builds `dogssh push [--tag T] <local-path> <remote-dir>`.
+func newPushCmd(serverService ports.ServerService, transferService ports.TransferService) *cobra.Command {
+	var flags transferFlags
+	cmd := &cobra.Command{
+		Use:     "push [flags] <local-path> <remote-dir>",
+		Short:   "Copy a local file or directory to many servers",
+		Example: `  dogssh push --tag web ./release.tar.gz /opt/releases`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(args[0]); err != nil {
+				return err
+			}
+			return runTransfers(cmd, serverService, transferService, flags, func(aliases []string) []domain.TransferJob {
+				return domain.PushJobs(aliases, args[0], args[1])
+			})
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+// newPullCmd builds `dogssh pull [--tag T] <remote-path> <local-dir>`.
+func newPullCmd(serverService ports.ServerService, transferService ports.TransferService) *cobra.Command {
+	var flags transferFlags
+	cmd := &cobra.Command{
+		Use:   "pull [flags] <remote-path> <local-dir>",
+		Short: "Copy a remote file or directory from many servers into per-host directories",
+		Example: `  dogssh pull --tag web /var/log/nginx ./logs
+  # creates ./logs/<alias>/nginx for every server`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTransfers(cmd, serverService, transferService, flags, func(aliases []string) []domain.TransferJob {
+				return domain.PullJobs(aliases, args[0], args[1])
+			})
+		},
+	}
+	flags.register(cmd)
+	return cmd
+}
+
+func runTransfers(cmd *cobra.Command, serverService ports.ServerService, transferService ports.TransferService, flags transferFlags, plan func([]string) []domain.TransferJob) error {
+	servers, err := selectServers(serverService, flags.query, flags.tags)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no servers match the given filters")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	opts := domain.TransferOptions{Concurrency: flags.concurrency, Retries: flags.retries, Verify: !flags.noVerify}
+	errOut := cmd.ErrOrStderr()
+	jobs := transferService.Run(ctx, plan(aliasesOf(servers)), opts, func(job domain.TransferJob) {
+		switch {
+		case job.State == domain.TransferQueued && job.Error != "":
+			_, _ = fmt.Fprintf(errOut, "%s: attempt %d failed, retrying: %s\n", job.Alias, job.Attempts, job.Error)
+		case job.Finished():
+			_, _ = fmt.Fprintf(errOut, "%s: %s\n", job.Alias, job.State)
+		}
+	})
+
+	printTransferSummary(cmd, jobs)
+
+	if flags.jsonPath != "" {
+		if err := writeJSON(flags.jsonPath, jobs); err != nil {
+			return err
+		}
+	}
+
+	if failed := countFailedTransfers(jobs); failed > 0 {
+		return fmt.Errorf("%d of %d servers failed", failed, len(jobs))
+	}
+	return nil
+}
+
+func printTransferSummary(cmd *cobra.Command, jobs []domain.TransferJob) {
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprintln(out)
+	for _, job := range jobs {
+		verified := "not verified"
+		if job.Files >= 0 {
+			verified = fmt.Sprintf("%d files verified", job.Files)
+		}
+		status := fmt.Sprintf("ok, %d bytes, %s", job.Bytes, verified)
+		if job.State == domain.TransferFailed {
+			status = "FAILED: " + job.Error
+		}
+		_, _ = fmt.Fprintf(out, "==> %s %s → %s (%s, %d attempts, %s)\n",
+			job.Alias, job.Source, job.Dest, status, job.Attempts, job.Duration.Round(time.Millisecond))
+	}
+	failed := countFailedTransfers(jobs)
+	_, _ = fmt.Fprintf(out, "\n%d ok, %d failed\n", len(jobs)-failed, failed)
+}
+
+func countFailedTransfers(jobs []domain.TransferJob) int {
+	failed := 0
+	for _, job := range jobs {
+		if job.State == domain.TransferFailed {
+			failed++
+		}
+	}
+	return failed
+}
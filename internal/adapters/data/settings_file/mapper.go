@@ -0,0 +1,209 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings_file
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+)
+
+// settingsFile is the YAML schema of config.yaml.
+type settingsFile struct {
+	Form         formSection         `yaml:"form,omitempty"`
+	List         listSection         `yaml:"list,omitempty"`
+	Log          logSection          `yaml:"log,omitempty"`
+	Connection   connectionSection   `yaml:"connection,omitempty"`
+	Certificates certificatesSection `yaml:"certificates,omitempty"`
+	Views        viewsSection        `yaml:"views,omitempty"`
+	Keys         keysSection         `yaml:"keys,omitempty"`
+	Theme        themeSection        `yaml:"theme,omitempty"`
+}
+
+type formSection struct {
+	// User, Port and Key prefill the add server form.
+	User string `yaml:"user,omitempty"`
+	Port int    `yaml:"port,omitempty"`
+	Key  string `yaml:"key,omitempty"`
+}
+
+type listSection struct {
+	// Sort is a sort field optionally followed by asc or desc, e.g. "lastseen desc".
+	Sort string `yaml:"sort,omitempty"`
+	// Columns lists the fields shown for each server, in order.
+	Columns []string `yaml:"columns,omitempty"`
+	// Widths caps the width of columns by name, e.g. notes: 30.
+	Widths map[string]int `yaml:"widths,omitempty"`
+}
+
+type logSection struct {
+	// Path is the log file; ~ expands to the home directory.
+	Path string `yaml:"path,omitempty"`
+	// Level is debug, info, warn or error.
+	Level string `yaml:"level,omitempty"`
+}
+
+type connectionSection struct {
+	// Tmux selects how connections open inside tmux: off, window, hsplit or vsplit.
+	Tmux string `yaml:"tmux,omitempty"`
+}
+
+type certificatesSection struct {
+	// RenewCommand runs before connecting when a server's certificate has expired.
+	RenewCommand string `yaml:"renew_command,omitempty"`
+	// WarnBefore is a duration such as 1h; certificates expiring sooner are flagged.
+	WarnBefore string `yaml:"warn_before,omitempty"`
+}
+
+type viewsSection struct {
+	// Startup names the saved view applied at startup.
+	Startup string `yaml:"startup,omitempty"`
+}
+
+type keysSection struct {
+	// Preset selects the base bindings: default, vim or emacs.
+	Preset string `yaml:"preset,omitempty"`
+	// Bindings maps action names to one or more keys, e.g. ping: [P].
+	Bindings map[string][]string `yaml:"bindings,omitempty"`
+}
+
+type themeSection struct {
+	// Preset selects the base colors: dark, light, high-contrast or monochrome.
+	Preset string `yaml:"preset,omitempty"`
+	// Colors overrides single colors by name, e.g. error: "#FF0000".
+	Colors map[string]string `yaml:"colors,omitempty"`
+}
+
+// toDomain overlays the values present in the file onto defaults. Invalid values keep
+// their default and are reported together in the returned error.
+func (f settingsFile) toDomain(defaults domain.Settings) (domain.Settings, error) {
+	settings := defaults
+	var errs []error
+
+	if f.Form.User != "" {
+		if strings.ContainsAny(f.Form.User, " \t@") {
+			errs = append(errs, fmt.Errorf("form.user: invalid user %q", f.Form.User))
+		} else {
+			settings.Form.User = f.Form.User
+		}
+	}
+	if f.Form.Port != 0 {
+		if f.Form.Port < 1 || f.Form.Port > 65535 {
+			errs = append(errs, fmt.Errorf("form.port: %d is out of range (want 1-65535)", f.Form.Port))
+		} else {
+			settings.Form.Port = f.Form.Port
+		}
+	}
+	if f.Form.Key != "" {
+		settings.Form.IdentityFile = f.Form.Key
+	}
+
+	if f.List.Sort != "" {
+		order, err := domain.ParseSortOrder(f.List.Sort)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list.sort: %w", err))
+		} else {
+			settings.List.Sort = order
+		}
+	}
+	if len(f.List.Columns) > 0 {
+		columns, err := domain.ParseListColumns(f.List.Columns)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("list.columns: %w", err))
+		} else {
+			settings.List.Columns = columns
+		}
+	}
+
+	names := make([]string, 0, len(f.List.Widths))
+	for name := range f.List.Widths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		width := f.List.Widths[name]
+		column, err := domain.ParseListColumn(name)
+		switch {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("list.widths: %w", err))
+		case width < 1:
+			errs = append(errs, fmt.Errorf("list.widths.%s: width must be at least 1", name))
+		default:
+			if settings.List.Widths == nil {
+				settings.List.Widths = make(map[domain.ListColumn]int)
+			}
+			settings.List.Widths[column] = width
+		}
+	}
+
+	if f.Log.Path != "" {
+		settings.Log.Path = f.Log.Path
+	}
+	if f.Log.Level != "" {
+		level := domain.LogLevel(strings.ToLower(f.Log.Level))
+		if !level.Valid() {
+			errs = append(errs, fmt.Errorf("log.level: unknown level %q (want debug, info, warn or error)", f.Log.Level))
+		} else {
+			settings.Log.Level = level
+		}
+	}
+
+	if f.Connection.Tmux != "" {
+		mode := domain.TmuxMode(f.Connection.Tmux)
+		if !mode.Valid() {
+			errs = append(errs, fmt.Errorf("connection.tmux: unknown mode %q (want off, window, hsplit or vsplit)", f.Connection.Tmux))
+		} else {
+			settings.Connection.TmuxMode = mode
+		}
+	}
+
+	settings.Certificates.RenewCommand = f.Certificates.RenewCommand
+	if f.Certificates.WarnBefore != "" {
+		warnBefore, err := time.ParseDuration(f.Certificates.WarnBefore)
+		if err != nil || warnBefore < 0 {
+			errs = append(errs, fmt.Errorf("certificates.warn_before: invalid duration %q (want e.g. 1h or 30m)", f.Certificates.WarnBefore))
+		} else {
+			settings.Certificates.WarnBefore = warnBefore
+		}
+	}
+
+	settings.Views.Startup = f.Views.Startup
+
+	if f.Keys.Preset != "" {
+		preset := domain.KeymapPreset(f.Keys.Preset)
+		if !preset.Valid() {
+			errs = append(errs, fmt.Errorf("keys.preset: unknown preset %q (want default, vim or emacs)", f.Keys.Preset))
+		} else {
+			settings.Keys.Preset = preset
+		}
+	}
+	settings.Keys.Bindings = f.Keys.Bindings
+
+	if f.Theme.Preset != "" {
+		preset := domain.ThemePreset(f.Theme.Preset)
+		if !preset.Valid() {
+			errs = append(errs, fmt.Errorf("theme.preset: unknown preset %q (want dark, light, high-contrast or monochrome)", f.Theme.Preset))
+		} else {
+			settings.Theme.Preset = preset
+		}
+	}
+	settings.Theme.Colors = f.Theme.Colors
+
+	return settings, errors.Join(errs...)
+}
@@ -0,0 +1,83 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings_file
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Repository implements SettingsRepository backed by a YAML file.
+type Repository struct {
+	filePath string
+	logger   *zap.SugaredLogger
+}
+
+// NewRepository creates a settings repository for the given config.yaml path.
+func NewRepository(logger *zap.SugaredLogger, filePath string) ports.SettingsRepository {
+	return &Repository{filePath: filePath, logger: logger}
+}
+
+// Load reads the settings file. A missing file yields the default settings. Unknown
+// keys and invalid values are reported in the error; the remaining settings still apply.
+func (r *Repository) Load() (domain.Settings, error) {
+	settings := domain.DefaultSettings()
+
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return settings, fmt.Errorf("read settings '%s': %w", r.filePath, err)
+	}
+	if len(data) == 0 {
+		return settings, nil
+	}
+
+	var file settingsFile
+	var schemaErrs []error
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		// A type error still decodes everything it can.
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return settings, fmt.Errorf("parse settings YAML '%s': %w", r.filePath, err)
+		}
+		for _, msg := range typeErr.Errors {
+			schemaErrs = append(schemaErrs, errors.New(schemaMessage(msg)))
+		}
+	}
+
+	settings, err = file.toDomain(settings)
+	return settings, errors.Join(append(schemaErrs, err)...)
+}
+
+// schemaMessage rewords the yaml decoder's unknown field errors, which name Go types.
+func schemaMessage(msg string) string {
+	if field, _, found := strings.Cut(msg, " not found in type "); found {
+		return strings.Replace(field, ": field ", ": unknown setting ", 1)
+	}
+	return msg
+}
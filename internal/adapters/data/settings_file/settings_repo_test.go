@@ -0,0 +1,177 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings_file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func loadSettings(t *testing.T, content string) (domain.Settings, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return NewRepository(zap.NewNop().Sugar(), path).Load()
+}
+
+func TestLoadMissingFileUsesDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	settings, err := NewRepository(zap.NewNop().Sugar(), path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(settings, domain.DefaultSettings()) {
+		t.Fatalf("Load() = %+v, want defaults", settings)
+	}
+
+	settings, err = loadSettings(t, "")
+	if err != nil || !reflect.DeepEqual(settings, domain.DefaultSettings()) {
+		t.Fatalf("Load() of an empty file = %+v, %v; want defaults", settings, err)
+	}
+}
+
+func TestLoadValidSettings(t *testing.T) {
+	settings, err := loadSettings(t, `
+form:
+  user: deploy
+  port: 2222
+list:
+  sort: lastseen desc
+  columns: [alias, notes]
+  widths:
+    notes: 30
+log:
+  level: WARN
+connection:
+  tmux: hsplit
+certificates:
+  warn_before: 30m
+keys:
+  preset: vim
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := domain.DefaultSettings()
+	want.Form.User = "deploy"
+	want.Form.Port = 2222
+	want.List.Sort = domain.SortOrder{Field: domain.SortFieldLastSeen, Descending: true}
+	want.List.Columns = []domain.ListColumn{domain.ColumnAlias, domain.ColumnNotes}
+	want.List.Widths = map[domain.ListColumn]int{domain.ColumnNotes: 30}
+	want.Log.Level = domain.LogLevelWarn
+	want.Connection.TmuxMode = domain.TmuxModeHSplit
+	want.Certificates.WarnBefore = 30 * time.Minute
+	want.Keys.Preset = domain.KeymapVim
+	if !reflect.DeepEqual(settings, want) {
+		t.Fatalf("Load() = %+v\nwant %+v", settings, want)
+	}
+}
+
+func TestLoadReportsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errs    []string
+		check   func(domain.Settings) bool
+	}{
+		{
+			name:    "unknown key",
+			content: "connection:\n  tmux: vsplit\n  tmux_mode: window\n",
+			errs:    []string{"unknown setting tmux_mode"},
+			check:   func(s domain.Settings) bool { return s.Connection.TmuxMode == domain.TmuxModeVSplit },
+		},
+		{
+			name:    "type error",
+			content: "form:\n  port: twenty-two\n  user: deploy\n",
+			errs:    []string{"twenty-two"},
+			check:   func(s domain.Settings) bool { return s.Form.User == "deploy" && s.Form.Port == 22 },
+		},
+		{
+			name:    "tmux mode",
+			content: "connection:\n  tmux: tabs\nlog:\n  level: info\n",
+			errs:    []string{`connection.tmux: unknown mode "tabs"`},
+			check: func(s domain.Settings) bool {
+				return s.Connection.TmuxMode == domain.TmuxModeWindow && s.Log.Level == domain.LogLevelInfo
+			},
+		},
+		{
+			name:    "log level",
+			content: "log:\n  level: verbose\nconnection:\n  tmux: off\n",
+			errs:    []string{`log.level: unknown level "verbose"`},
+			check: func(s domain.Settings) bool {
+				return s.Log.Level == domain.LogLevelDebug && s.Connection.TmuxMode == domain.TmuxModeOff
+			},
+		},
+		{
+			name:    "sort",
+			content: "list:\n  sort: size\n  columns: [alias]\n",
+			errs:    []string{"list.sort:"},
+			check: func(s domain.Settings) bool {
+				return s.List.Sort.Field == domain.SortFieldAlias && reflect.DeepEqual(s.List.Columns, []domain.ListColumn{domain.ColumnAlias})
+			},
+		},
+		{
+			name:    "column",
+			content: "list:\n  columns: [alias, colour]\n  sort: host\n",
+			errs:    []string{"list.columns:"},
+			check: func(s domain.Settings) bool {
+				return reflect.DeepEqual(s.List.Columns, domain.DefaultListColumns) && s.List.Sort.Field == domain.SortFieldHost
+			},
+		},
+		{
+			name:    "widths",
+			content: "list:\n  widths:\n    notes: 0\n    colour: 5\n    host: 20\n",
+			errs:    []string{"list.widths.notes: width must be at least 1", "list.widths:"},
+			check: func(s domain.Settings) bool {
+				return reflect.DeepEqual(s.List.Widths, map[domain.ListColumn]int{domain.ColumnHost: 20})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := loadSettings(t, tt.content)
+			if err == nil {
+				t.Fatal("Load() succeeded, want an error")
+			}
+			for _, want := range tt.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error %q does not mention %q", err, want)
+				}
+			}
+			if !tt.check(settings) {
+				t.Errorf("Load() = %+v; valid values not applied", settings)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsMalformedYAML(t *testing.T) {
+	settings, err := loadSettings(t, "form: [unclosed\n")
+	if err == nil || !strings.Contains(err.Error(), "parse settings YAML") {
+		t.Fatalf("Load() error = %v, want a parse error", err)
+	}
+	if !reflect.DeepEqual(settings, domain.DefaultSettings()) {
+		t.Fatalf("Load() = %+v, want defaults", settings)
+	}
+}
@@ -22,6 +22,8 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
 )
 
 // createBackup creates a timestamped backup of the current config file
@@ -151,3 +153,68 @@ func (r *Repository) createOriginalBackupIfNeeded() error {
 	r.logger.Infof("Created original backup: %s", originalBackupPath)
 	return nil
 }
+
+// ListBackups returns the timestamped backups of the config file, newest first.
+func (r *Repository) ListBackups() ([]domain.ConfigBackup, error) {
+	configDir := filepath.Dir(r.configPath)
+	backupFiles, err := r.findBackupFiles(configDir)
+	if err != nil {
+		if r.fileSystem.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	sort.Slice(backupFiles, func(i, j int) bool {
+		return backupFiles[i].ModTime().After(backupFiles[j].ModTime())
+	})
+	backups := make([]domain.ConfigBackup, 0, len(backupFiles))
+	for _, info := range backupFiles {
+		backups = append(backups, domain.ConfigBackup{
+			Path:      filepath.Join(configDir, info.Name()),
+			CreatedAt: info.ModTime(),
+			Size:      info.Size(),
+		})
+	}
+	return backups, nil
+}
+
+// RestoreBackup replaces the config file with one of its backups. The current file is
+// backed up first, so a restore can itself be undone.
+func (r *Repository) RestoreBackup(path string) error {
+	backups, err := r.ListBackups()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, backup := range backups {
+		found = found || backup.Path == path
+	}
+	if !found {
+		return fmt.Errorf("'%s' is not a backup of %s", path, r.configPath)
+	}
+
+	tempFile, err := r.createTempFile(filepath.Dir(r.configPath))
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if removeErr := r.fileSystem.Remove(tempFile); removeErr != nil && !r.fileSystem.IsNotExist(removeErr) {
+			r.logger.Warnf("failed to remove temporary file %s: %v", tempFile, removeErr)
+		}
+	}()
+
+	// Copy before backing up: the new backup may rotate the restored one away.
+	if err := r.copyFile(path, tempFile); err != nil {
+		return fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if err := r.createBackup(); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	if err := r.fileSystem.Rename(tempFile, r.configPath); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+
+	r.logger.Infof("Restored SSH config from backup: %s", path)
+	return nil
+}
@@ -16,6 +16,7 @@ package ssh_config_file
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
@@ -28,43 +29,10 @@ const (
 	BackupSuffix       = "dogssh.backup"
 	SSHConfigPerms     = 0o600
 	OriginalBackupName = "config.original.backup"
-)
-
-// filterServers filters servers based on the query string.
-func (r *Repository) filterServers(servers []domain.Server, query string) []domain.Server {
-	query = strings.ToLower(query)
-	filtered := make([]domain.Server, 0)
-
-	for _, server := range servers {
-		if r.matchesQuery(server, query) {
-			filtered = append(filtered, server)
-		}
-	}
-
-	return filtered
-}
-
-// matchesQuery checks if any field of the server matches the query string.
-func (r *Repository) matchesQuery(server domain.Server, query string) bool {
-	fields := []string{
-		strings.ToLower(server.Host),
-		strings.ToLower(server.User),
-	}
-	for _, tag := range server.Tags {
-		fields = append(fields, strings.ToLower(tag))
-	}
-	for _, alias := range server.Aliases {
-		fields = append(fields, strings.ToLower(alias))
-	}
 
-	for _, field := range fields {
-		if strings.Contains(field, query) {
-			return true
-		}
-	}
-
-	return false
-}
+	// passwordPlaceholder stands in for a stored password in listed servers.
+	passwordPlaceholder = "****"
+)
 
 // serverExists checks if a server with the given alias already exists in the config.
 func (r *Repository) serverExists(cfg *ssh_config.Config, alias string) bool {
@@ -109,6 +77,7 @@ func (r *Repository) createHostFromServer(server domain.Server) *ssh_config.Host
 	for _, identityFile := range server.IdentityFiles {
 		r.addKVNodeIfNotEmpty(host, "IdentityFile", identityFile)
 	}
+	r.addKVNodeIfNotEmpty(host, "CertificateFile", server.CertificateFile)
 
 	return host
 }
@@ -147,7 +116,7 @@ func (r *Repository) updateHostNodes(host *ssh_config.Host, newServer domain.Ser
 		for _, node := range nodes {
 			if kv, ok := node.(*ssh_config.KV); ok {
 				if strings.EqualFold(kv.Key, key) {
-					continue // skip existing IdentityFile
+					continue // skip existing entries of key
 				}
 			}
 			filtered = append(filtered, node)
@@ -159,6 +128,10 @@ func (r *Repository) updateHostNodes(host *ssh_config.Host, newServer domain.Ser
 	for _, identityFile := range newServer.IdentityFiles {
 		r.addKVNodeIfNotEmpty(host, "IdentityFile", identityFile)
 	}
+
+	// CertificateFile follows the same rule so clearing it in the form removes it.
+	host.Nodes = removeKey(host.Nodes, "CertificateFile")
+	r.addKVNodeIfNotEmpty(host, "CertificateFile", newServer.CertificateFile)
 }
 
 // updateOrAddKVNode updates an existing key-value node or adds a new one if it doesn't exist.
@@ -187,10 +160,11 @@ func (r *Repository) updateOrAddKVNode(host *ssh_config.Host, key, newValue stri
 // Reference: https://www.ssh.com/academy/ssh/config
 func (r *Repository) getProperKeyCase(key string) string {
 	keyMap := map[string]string{
-		"hostname":     "HostName",
-		"user":         "User",
-		"port":         "Port",
-		"identityfile": "IdentityFile",
+		"hostname":        "HostName",
+		"user":            "User",
+		"port":            "Port",
+		"identityfile":    "IdentityFile",
+		"certificatefile": "CertificateFile",
 	}
 
 	if properCase, exists := keyMap[strings.ToLower(key)]; exists {
@@ -208,3 +182,11 @@ func (r *Repository) removeHostByAlias(hosts []*ssh_config.Host, alias string) [
 	}
 	return hosts
 }
+
+// sameHostSettings reports whether a and b differ only in metadata kept by dogssh,
+// so that applying b does not need to touch the SSH config.
+func sameHostSettings(a, b domain.Server) bool {
+	return a.Alias == b.Alias && a.Host == b.Host && a.User == b.User && a.Port == b.Port &&
+		a.CertificateFile == b.CertificateFile && (b.Password == "" || b.Password == passwordPlaceholder) &&
+		slices.Equal(a.IdentityFiles, b.IdentityFiles)
+}
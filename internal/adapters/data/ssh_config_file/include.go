@@ -0,0 +1,127 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// maxIncludeDepth matches the nesting limit ssh applies to Include directives.
+const maxIncludeDepth = 5
+
+// includedServers returns the hosts of the files pulled in by Include directives of cfg.
+// These hosts are listed with their SourceFile but are not edited by dogssh. Aliases
+// already in seen are skipped, since ssh uses the first definition of a host.
+func (r *Repository) includedServers(cfg *ssh_config.Config, seen map[string]bool, depth int) []domain.Server {
+	if depth > maxIncludeDepth {
+		return nil
+	}
+	var servers []domain.Server
+	for _, path := range r.includePaths(cfg) {
+		included, err := r.loadIncludedConfig(path)
+		if err != nil {
+			r.logger.Warnf("Failed to read included SSH config %s: %v", path, err)
+			continue
+		}
+		for _, server := range r.toDomainServer(included) {
+			if seen[server.Alias] {
+				continue
+			}
+			seen[server.Alias] = true
+			server.SourceFile = path
+			servers = append(servers, server)
+		}
+		servers = append(servers, r.includedServers(included, seen, depth+1)...)
+	}
+	return servers
+}
+
+// includedSource returns the included file that defines alias, or "" when none does.
+func (r *Repository) includedSource(cfg *ssh_config.Config, alias string) string {
+	seen := make(map[string]bool)
+	for _, server := range r.toDomainServer(cfg) {
+		seen[server.Alias] = true
+	}
+	for _, server := range r.includedServers(cfg, seen, 1) {
+		if server.Alias == alias {
+			return server.SourceFile
+		}
+	}
+	return ""
+}
+
+// includePaths expands the Include directives of cfg into file paths, in order.
+// Relative paths are resolved against the directory of the main config file.
+func (r *Repository) includePaths(cfg *ssh_config.Config) []string {
+	var paths []string
+	for _, host := range cfg.Hosts {
+		for _, node := range host.Nodes {
+			include, ok := node.(*ssh_config.Include)
+			if !ok {
+				continue
+			}
+			for _, pattern := range includeDirectives(include.String()) {
+				matches, err := filepath.Glob(r.resolveIncludePath(pattern))
+				if err != nil {
+					r.logger.Warnf("Invalid Include pattern %q: %v", pattern, err)
+					continue
+				}
+				paths = append(paths, matches...)
+			}
+		}
+	}
+	return paths
+}
+
+func (r *Repository) resolveIncludePath(pattern string) string {
+	if pattern == "~" || strings.HasPrefix(pattern, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(pattern, "~"))
+		}
+	}
+	if filepath.IsAbs(pattern) {
+		return pattern
+	}
+	return filepath.Join(filepath.Dir(r.configPath), pattern)
+}
+
+func (r *Repository) loadIncludedConfig(path string) (*ssh_config.Config, error) {
+	file, err := r.fileSystem.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			r.logger.Warnf("failed to close included config %s: %v", path, cerr)
+		}
+	}()
+	return ssh_config.Decode(file)
+}
+
+// includeDirectives extracts the file patterns from the text of an Include line.
+func includeDirectives(line string) []string {
+	line = strings.TrimSpace(line)
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line[len("Include"):])
+	line = strings.TrimSpace(strings.TrimPrefix(line, "="))
+	return strings.Fields(line)
+}
@@ -77,6 +77,10 @@ func (r *Repository) mapKVToServer(server *domain.Server, kvNode *ssh_config.KV)
 		}
 	case "identityfile":
 		server.IdentityFiles = append(server.IdentityFiles, kvNode.Value)
+	case "certificatefile":
+		server.CertificateFile = kvNode.Value
+	case "proxyjump":
+		server.ProxyJump = kvNode.Value
 	}
 }
 
@@ -88,12 +92,14 @@ func (r *Repository) mergeMetadata(servers []domain.Server, metadata map[string]
 		// Check if password exists for this server
 		if hasPassword, err := r.HasPassword(server.Alias); err == nil && hasPassword {
 			// Set a placeholder to indicate password exists (don't load the actual hash)
-			servers[i].Password = "****"
+			servers[i].Password = passwordPlaceholder
 		}
 
 		if meta, exists := metadata[server.Alias]; exists {
 			servers[i].Tags = meta.Tags
+			servers[i].Notes = meta.Notes
 			servers[i].SSHCount = meta.SSHCount
+			servers[i].Tunnels = toDomainTunnels(meta.Tunnels)
 
 			if meta.LastSeen != "" {
 				if lastSeen, err := time.Parse(time.RFC3339, meta.LastSeen); err == nil {
@@ -110,3 +116,24 @@ func (r *Repository) mergeMetadata(servers []domain.Server, metadata map[string]
 	}
 	return servers
 }
+
+// toDomainTunnels converts stored tunnel definitions to domain tunnels.
+func toDomainTunnels(stored []TunnelMetadata) []domain.Tunnel {
+	if len(stored) == 0 {
+		return nil
+	}
+	tunnels := make([]domain.Tunnel, 0, len(stored))
+	for _, t := range stored {
+		tunnels = append(tunnels, domain.Tunnel{
+			Name:        t.Name,
+			Type:        domain.TunnelType(t.Type),
+			BindAddress: t.BindAddress,
+			ListenPort:  t.ListenPort,
+			TargetHost:  t.TargetHost,
+			TargetPort:  t.TargetPort,
+			AutoRestart: t.AutoRestart,
+			Persistent:  t.Persistent,
+		})
+	}
+	return tunnels
+}
@@ -26,10 +26,23 @@ import (
 )
 
 type ServerMetadata struct {
-	Tags     []string `json:"tags,omitempty"`
-	LastSeen string   `json:"last_seen,omitempty"`
-	PinnedAt string   `json:"pinned_at,omitempty"`
-	SSHCount int      `json:"ssh_count,omitempty"`
+	Tags     []string         `json:"tags,omitempty"`
+	Notes    string           `json:"notes,omitempty"`
+	LastSeen string           `json:"last_seen,omitempty"`
+	PinnedAt string           `json:"pinned_at,omitempty"`
+	SSHCount int              `json:"ssh_count,omitempty"`
+	Tunnels  []TunnelMetadata `json:"tunnels,omitempty"`
+}
+
+type TunnelMetadata struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	BindAddress string `json:"bind_address,omitempty"`
+	ListenPort  int    `json:"listen_port"`
+	TargetHost  string `json:"target_host,omitempty"`
+	TargetPort  int    `json:"target_port,omitempty"`
+	AutoRestart bool   `json:"auto_restart,omitempty"`
+	Persistent  bool   `json:"persistent,omitempty"`
 }
 
 type metadataManager struct {
@@ -85,67 +98,80 @@ func (m *metadataManager) saveAll(metadata map[string]ServerMetadata) error {
 }
 
 func (m *metadataManager) updateServer(server domain.Server, oldAlias string) error {
+	return m.updateServers([]domain.ServerUpdate{{Old: domain.Server{Alias: oldAlias}, New: server}})
+}
+
+// updateServers merges several edited servers into the metadata with one write.
+func (m *metadataManager) updateServers(updates []domain.ServerUpdate) error {
 	metadata, err := m.loadAll()
 	if err != nil {
-		m.logger.Errorw("failed to load metadata in updateServer", "path", m.filePath, "alias", server.Alias, "old_alias", oldAlias, "error", err)
+		m.logger.Errorw("failed to load metadata in updateServers", "path", m.filePath, "count", len(updates), "error", err)
 		return fmt.Errorf("load metadata: %w", err)
 	}
 
-	if oldAlias != server.Alias {
-		oldMeta, ok := metadata[oldAlias]
-		if ok {
-			metadata[server.Alias] = oldMeta
+	for _, update := range updates {
+		server, oldAlias := update.New, update.Old.Alias
+		if oldAlias != server.Alias {
+			oldMeta, ok := metadata[oldAlias]
+			if ok {
+				metadata[server.Alias] = oldMeta
+			}
+			delete(metadata, oldAlias)
 		}
-		delete(metadata, oldAlias)
-	}
 
-	existing := metadata[server.Alias]
-	merged := existing
+		existing := metadata[server.Alias]
+		merged := existing
 
-	merged.Tags = server.Tags
+		merged.Tags = server.Tags
+		merged.Notes = server.Notes
 
-	if !server.LastSeen.IsZero() {
-		merged.LastSeen = server.LastSeen.Format(time.RFC3339)
-	}
+		if !server.LastSeen.IsZero() {
+			merged.LastSeen = server.LastSeen.Format(time.RFC3339)
+		}
 
-	if !server.PinnedAt.IsZero() {
-		merged.PinnedAt = server.PinnedAt.Format(time.RFC3339)
-	}
+		if !server.PinnedAt.IsZero() {
+			merged.PinnedAt = server.PinnedAt.Format(time.RFC3339)
+		}
 
-	if server.SSHCount > 0 {
-		merged.SSHCount = server.SSHCount
-	}
+		if server.SSHCount > 0 {
+			merged.SSHCount = server.SSHCount
+		}
 
-	metadata[server.Alias] = merged
+		metadata[server.Alias] = merged
+	}
 	return m.saveAll(metadata)
 }
 
-func (m *metadataManager) deleteServer(alias string) error {
+func (m *metadataManager) deleteServers(aliases []string) error {
 	metadata, err := m.loadAll()
 	if err != nil {
-		m.logger.Errorw("failed to load metadata in deleteServer", "path", m.filePath, "alias", alias, "error", err)
+		m.logger.Errorw("failed to load metadata in deleteServers", "path", m.filePath, "aliases", aliases, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
 	}
 
-	delete(metadata, alias)
+	for _, alias := range aliases {
+		delete(metadata, alias)
+	}
 	return m.saveAll(metadata)
 }
 
-func (m *metadataManager) setPinned(alias string, pinned bool) error {
+func (m *metadataManager) setPinned(aliases []string, pinned bool) error {
 	metadata, err := m.loadAll()
 	if err != nil {
-		m.logger.Errorw("failed to load metadata in setPinned", "path", m.filePath, "alias", alias, "pinned", pinned, "error", err)
+		m.logger.Errorw("failed to load metadata in setPinned", "path", m.filePath, "aliases", aliases, "pinned", pinned, "error", err)
 		return fmt.Errorf("load metadata: %w", err)
 	}
 
-	meta := metadata[alias]
-	if pinned {
-		meta.PinnedAt = time.Now().Format(time.RFC3339)
-	} else {
-		meta.PinnedAt = ""
+	now := time.Now().Format(time.RFC3339)
+	for _, alias := range aliases {
+		meta := metadata[alias]
+		if pinned {
+			meta.PinnedAt = now
+		} else {
+			meta.PinnedAt = ""
+		}
+		metadata[alias] = meta
 	}
-
-	metadata[alias] = meta
 	return m.saveAll(metadata)
 }
 
@@ -164,6 +190,32 @@ func (m *metadataManager) recordSSH(alias string) error {
 	return m.saveAll(metadata)
 }
 
+func (m *metadataManager) setTunnels(alias string, tunnels []domain.Tunnel) error {
+	metadata, err := m.loadAll()
+	if err != nil {
+		m.logger.Errorw("failed to load metadata in setTunnels", "path", m.filePath, "alias", alias, "error", err)
+		return fmt.Errorf("load metadata: %w", err)
+	}
+
+	meta := metadata[alias]
+	meta.Tunnels = make([]TunnelMetadata, 0, len(tunnels))
+	for _, t := range tunnels {
+		meta.Tunnels = append(meta.Tunnels, TunnelMetadata{
+			Name:        t.Name,
+			Type:        string(t.Type),
+			BindAddress: t.BindAddress,
+			ListenPort:  t.ListenPort,
+			TargetHost:  t.TargetHost,
+			TargetPort:  t.TargetPort,
+			AutoRestart: t.AutoRestart,
+			Persistent:  t.Persistent,
+		})
+	}
+
+	metadata[alias] = meta
+	return m.saveAll(metadata)
+}
+
 func (m *metadataManager) ensureDirectory() error {
 	dir := filepath.Dir(m.filePath)
 	if err := os.MkdirAll(dir, 0o750); err != nil {
@@ -0,0 +1,103 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kevinburke/ssh_config"
+)
+
+// HostOptions returns the values of option (an ssh_config keyword such as HostName or
+// ProxyJump) on each host of the SSH config, keyed by alias. Every host of the main file
+// has an entry, empty when the option is not set; hosts from included files have none.
+func (r *Repository) HostOptions(option string) (map[string][]string, error) {
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	options := make(map[string][]string)
+	for _, server := range r.toDomainServer(cfg) {
+		host := r.findHostByAlias(cfg, server.Alias)
+		if host == nil {
+			continue
+		}
+		values := []string{}
+		for _, kv := range optionNodes(host, option) {
+			values = append(values, kv.Value)
+		}
+		options[server.Alias] = values
+	}
+	return options, nil
+}
+
+// SetHostOptions replaces the values of option on several hosts, writing and backing up
+// the SSH config once. Values are updated in place when their number is unchanged, so
+// comments and ordering survive; an empty list removes the option.
+func (r *Repository) SetHostOptions(option string, values map[string][]string) error {
+	cfg, err := r.loadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	for alias, newValues := range values {
+		host := r.findHostByAlias(cfg, alias)
+		if host == nil {
+			return fmt.Errorf("server with alias '%s' not found", alias)
+		}
+
+		existing := optionNodes(host, option)
+		if len(existing) == len(newValues) {
+			for i, kv := range existing {
+				kv.Value = newValues[i]
+			}
+			continue
+		}
+
+		key := r.getProperKeyCase(option)
+		if len(existing) > 0 {
+			key = existing[0].Key
+		}
+		nodes := make([]ssh_config.Node, 0, len(host.Nodes)+len(newValues))
+		for _, node := range host.Nodes {
+			if kv, ok := node.(*ssh_config.KV); ok && strings.EqualFold(kv.Key, option) {
+				continue
+			}
+			nodes = append(nodes, node)
+		}
+		host.Nodes = nodes
+		for _, value := range newValues {
+			r.addKVNodeIfNotEmpty(host, key, value)
+		}
+	}
+
+	if err := r.saveConfig(cfg); err != nil {
+		r.logger.Warnf("Failed to save config while setting %s: %v", option, err)
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+func optionNodes(host *ssh_config.Host, option string) []*ssh_config.KV {
+	var nodes []*ssh_config.KV
+	for _, node := range host.Nodes {
+		if kv, ok := node.(*ssh_config.KV); ok && strings.EqualFold(kv.Key, option) {
+			nodes = append(nodes, kv)
+		}
+	}
+	return nodes
+}
@@ -62,26 +62,28 @@ func NewRepositoryWithFS(logger *zap.SugaredLogger, configPath string, metaDataP
 	}
 }
 
-// ListServers returns all servers matching the query pattern.
-// Empty query returns all servers.
-func (r *Repository) ListServers(query string) ([]domain.Server, error) {
+// ListServers returns all servers with their metadata merged in.
+// Searching is done by the server service.
+func (r *Repository) ListServers() ([]domain.Server, error) {
 	cfg, err := r.loadConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
 	servers := r.toDomainServer(cfg)
+	seen := make(map[string]bool, len(servers))
+	for i := range servers {
+		servers[i].SourceFile = r.configPath
+		seen[servers[i].Alias] = true
+	}
+	servers = append(servers, r.includedServers(cfg, seen, 1)...)
+
 	metadata, err := r.metadataManager.loadAll()
 	if err != nil {
 		r.logger.Warnf("Failed to load metadata: %v", err)
 		metadata = make(map[string]ServerMetadata)
 	}
-	servers = r.mergeMetadata(servers, metadata)
-	if query == "" {
-		return servers, nil
-	}
-
-	return r.filterServers(servers, query), nil
+	return r.mergeMetadata(servers, metadata), nil
 }
 
 // AddServer adds a new server to the SSH config.
@@ -116,84 +118,130 @@ func (r *Repository) AddServer(server domain.Server) error {
 
 // UpdateServer updates an existing server in the SSH config.
 func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server) error {
+	return r.UpdateServers([]domain.ServerUpdate{{Old: server, New: newServer}})
+}
+
+// UpdateServers applies several edits at once. The SSH config is written (and backed up)
+// a single time, and only when a host setting changed; nothing is written if any edit fails.
+func (r *Repository) UpdateServers(updates []domain.ServerUpdate) error {
 	cfg, err := r.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	host := r.findHostByAlias(cfg, server.Alias)
-	if host == nil {
-		return fmt.Errorf("server with alias '%s' not found", server.Alias)
-	}
-
-	if server.Alias != newServer.Alias {
-		if r.serverExists(cfg, newServer.Alias) {
-			return fmt.Errorf("server with alias '%s' already exists", newServer.Alias)
+	changed := false
+	for _, update := range updates {
+		server, newServer := update.Old, update.New
+		host := r.findHostByAlias(cfg, server.Alias)
+		if host == nil {
+			source := r.includedSource(cfg, server.Alias)
+			if source == "" {
+				return fmt.Errorf("server with alias '%s' not found", server.Alias)
+			}
+			// Tags and other metadata live outside the SSH config, so they can still be changed.
+			if !sameHostSettings(server, newServer) {
+				return fmt.Errorf("server '%s' is defined in included file %s; edit it there", server.Alias, source)
+			}
+			continue
+		}
+		if sameHostSettings(server, newServer) {
+			continue
 		}
 
-		newPatterns := make([]*ssh_config.Pattern, 0, len(host.Patterns))
-		for _, pattern := range host.Patterns {
-			if pattern.Str == server.Alias {
-				newPatterns = append(newPatterns, &ssh_config.Pattern{Str: newServer.Alias})
-			} else {
-				newPatterns = append(newPatterns, pattern)
+		if server.Alias != newServer.Alias {
+			if r.serverExists(cfg, newServer.Alias) {
+				return fmt.Errorf("server with alias '%s' already exists", newServer.Alias)
 			}
-		}
 
-		host.Patterns = newPatterns
+			newPatterns := make([]*ssh_config.Pattern, 0, len(host.Patterns))
+			for _, pattern := range host.Patterns {
+				if pattern.Str == server.Alias {
+					newPatterns = append(newPatterns, &ssh_config.Pattern{Str: newServer.Alias})
+				} else {
+					newPatterns = append(newPatterns, pattern)
+				}
+			}
 
-	}
+			host.Patterns = newPatterns
 
-	r.updateHostNodes(host, newServer)
+		}
 
-	if err := r.saveConfig(cfg); err != nil {
-		r.logger.Warnf("Failed to save config while updating server: %v", err)
-		return fmt.Errorf("failed to save config: %w", err)
+		r.updateHostNodes(host, newServer)
+		changed = true
+	}
+
+	if changed {
+		if err := r.saveConfig(cfg); err != nil {
+			r.logger.Warnf("Failed to save config while updating servers: %v", err)
+			return fmt.Errorf("failed to save config: %w", err)
+		}
 	}
 
-	// Update password (if a new password is provided)
-	if newServer.Password != "" {
-		if err := r.passwordManager.UpdateServerPassword(newServer, newServer.Password); err != nil {
-			r.logger.Errorw("failed to update password while updating server", "alias", newServer.Alias, "error", err)
-			// Note: We log the error but don't prevent server update, as password storage is an additional feature
+	// Update passwords (if a new password is provided)
+	for _, update := range updates {
+		if password := update.New.Password; password != "" && password != passwordPlaceholder {
+			if err := r.passwordManager.UpdateServerPassword(update.New, password); err != nil {
+				r.logger.Errorw("failed to update password while updating server", "alias", update.New.Alias, "error", err)
+				// Note: We log the error but don't prevent server update, as password storage is an additional feature
+			}
 		}
 	}
 
-	// Update metadata; pass old alias to allow inline migration
-	return r.metadataManager.updateServer(newServer, server.Alias)
+	// Update metadata; pass old aliases to allow inline migration
+	return r.metadataManager.updateServers(updates)
 }
 
 // DeleteServer removes a server from the SSH config.
 func (r *Repository) DeleteServer(server domain.Server) error {
+	return r.DeleteServers([]domain.Server{server})
+}
+
+// DeleteServers removes several servers with a single write (and backup) of the SSH config.
+// Nothing is removed if any of them cannot be.
+func (r *Repository) DeleteServers(servers []domain.Server) error {
 	cfg, err := r.loadConfig()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	initialCount := len(cfg.Hosts)
-	cfg.Hosts = r.removeHostByAlias(cfg.Hosts, server.Alias)
+	aliases := make([]string, 0, len(servers))
+	for _, server := range servers {
+		initialCount := len(cfg.Hosts)
+		cfg.Hosts = r.removeHostByAlias(cfg.Hosts, server.Alias)
 
-	if len(cfg.Hosts) == initialCount {
-		return fmt.Errorf("server with alias '%s' not found", server.Alias)
+		if len(cfg.Hosts) == initialCount {
+			if source := r.includedSource(cfg, server.Alias); source != "" {
+				return fmt.Errorf("server '%s' is defined in included file %s; delete it there", server.Alias, source)
+			}
+			return fmt.Errorf("server with alias '%s' not found", server.Alias)
+		}
+		aliases = append(aliases, server.Alias)
 	}
 
 	if err := r.saveConfig(cfg); err != nil {
-		r.logger.Warnf("Failed to save config while deleting server: %v", err)
+		r.logger.Warnf("Failed to save config while deleting servers: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	// Delete password
-	if err := r.passwordManager.DeleteServerPassword(server.Alias); err != nil {
-		r.logger.Warnw("failed to delete password while deleting server", "alias", server.Alias, "error", err)
-		// Note: We log the warning but don't prevent server deletion, as password storage is an additional feature
+	// Delete passwords
+	for _, alias := range aliases {
+		if err := r.passwordManager.DeleteServerPassword(alias); err != nil {
+			r.logger.Warnw("failed to delete password while deleting server", "alias", alias, "error", err)
+			// Note: We log the warning but don't prevent server deletion, as password storage is an additional feature
+		}
 	}
 
-	return r.metadataManager.deleteServer(server.Alias)
+	return r.metadataManager.deleteServers(aliases)
 }
 
 // SetPinned sets or unsets the pinned status of a server.
 func (r *Repository) SetPinned(alias string, pinned bool) error {
-	return r.metadataManager.setPinned(alias, pinned)
+	return r.metadataManager.setPinned([]string{alias}, pinned)
+}
+
+// SetPinnedMany sets or unsets the pinned status of several servers at once.
+func (r *Repository) SetPinnedMany(aliases []string, pinned bool) error {
+	return r.metadataManager.setPinned(aliases, pinned)
 }
 
 // RecordSSH increments the SSH access count and updates the last seen timestamp for a server.
@@ -201,6 +249,11 @@ func (r *Repository) RecordSSH(alias string) error {
 	return r.metadataManager.recordSSH(alias)
 }
 
+// SetTunnels replaces the tunnel definitions stored for a server.
+func (r *Repository) SetTunnels(alias string, tunnels []domain.Tunnel) error {
+	return r.metadataManager.setTunnels(alias, tunnels)
+}
+
 // HasPassword checks if a password is stored for the given server alias.
 func (r *Repository) HasPassword(alias string) (bool, error) {
 	_, err := r.passwordManager.GetServerPassword(alias)
@@ -223,3 +276,8 @@ func (r *Repository) GetDecryptedPassword(alias string) (string, error) {
 	}
 	return r.passwordManager.DecryptPassword(encryptedPassword)
 }
+
+// DeletePassword removes the stored password of a server, if any.
+func (r *Repository) DeletePassword(alias string) error {
+	return r.passwordManager.DeleteServerPassword(alias)
+}
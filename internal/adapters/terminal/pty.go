@@ -0,0 +1,76 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/creack/pty"
+)
+
+// Command returns a Starter that runs name with args in a new pseudo-terminal.
+func Command(name string, args ...string) Starter {
+	return func(cols, rows int) (Process, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+
+		f, err := pty.StartWithSize(cmd, winsize(cols, rows))
+		if err != nil {
+			return nil, err
+		}
+		return &ptyProcess{cmd: cmd, pty: f, exited: make(chan struct{})}, nil
+	}
+}
+
+// ptyProcess is a command running on the slave side of a pseudo-terminal.
+type ptyProcess struct {
+	cmd *exec.Cmd
+	pty *os.File
+	// exited is closed once Wait has reaped the process, so Close never kills a
+	// reaped PID or reads ProcessState while Wait writes it.
+	exited chan struct{}
+}
+
+func (p *ptyProcess) Read(b []byte) (int, error) {
+	return p.pty.Read(b)
+}
+
+func (p *ptyProcess) Write(b []byte) (int, error) {
+	return p.pty.Write(b)
+}
+
+func (p *ptyProcess) Resize(cols, rows int) error {
+	return pty.Setsize(p.pty, winsize(cols, rows))
+}
+
+func (p *ptyProcess) Wait() error {
+	defer close(p.exited)
+	return p.cmd.Wait()
+}
+
+func (p *ptyProcess) Close() error {
+	select {
+	case <-p.exited:
+	default:
+		_ = p.cmd.Process.Kill()
+	}
+	return p.pty.Close()
+}
+
+func winsize(cols, rows int) *pty.Winsize {
+	//nolint:gosec // G115: terminal dimensions always fit in uint16
+	return &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)}
+}
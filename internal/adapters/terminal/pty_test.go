@@ -0,0 +1,49 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestPtyCloseWhileWaiting(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	for _, args := range [][]string{{"sleep", "10"}, {"true"}} {
+		proc, err := Command(args[0], args[1:]...)(80, 24)
+		if err != nil {
+			t.Skipf("no pseudo-terminal: %v", err)
+		}
+		waited := make(chan error, 1)
+		go func() { waited <- proc.Wait() }()
+		if args[0] == "true" {
+			// Let Wait reap the process before closing.
+			<-waited
+			waited <- nil
+		}
+
+		if err := proc.Close(); err != nil {
+			t.Fatalf("%v: Close() = %v", args, err)
+		}
+		select {
+		case <-waited:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%v: Close did not end the process", args)
+		}
+	}
+}
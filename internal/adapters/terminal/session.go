@@ -0,0 +1,176 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hinshun/vt10x"
+)
+
+// Process is a program attached to a terminal. Reads return its output and
+// writes deliver keyboard input.
+type Process interface {
+	io.ReadWriter
+	// Resize informs the process about a new terminal size.
+	Resize(cols, rows int) error
+	// Wait blocks until the process exits and returns its exit error.
+	Wait() error
+	// Close releases the terminal and terminates the process if still running.
+	Close() error
+}
+
+// Starter starts a Process sized cols x rows.
+type Starter func(cols, rows int) (Process, error)
+
+// Session runs a Process and feeds its output into a VT100/xterm emulator.
+type Session struct {
+	title string
+	start Starter
+	term  vt10x.Terminal
+	proc  Process
+
+	mu      sync.Mutex
+	exited  bool
+	exitErr error
+	done    chan struct{}
+
+	onUpdate func()
+	onExit   func(error)
+}
+
+// NewSession creates a session that will run the process returned by start.
+func NewSession(title string, start Starter) *Session {
+	return &Session{
+		title: title,
+		start: start,
+		done:  make(chan struct{}),
+	}
+}
+
+// OnUpdate registers a callback invoked after new output has been rendered.
+// It runs on the session's reader goroutine.
+func (s *Session) OnUpdate(fn func()) *Session {
+	s.onUpdate = fn
+	return s
+}
+
+// OnExit registers a callback invoked once the process has exited.
+// It runs on the session's reader goroutine.
+func (s *Session) OnExit(fn func(error)) *Session {
+	s.onExit = fn
+	return s
+}
+
+// Start launches the process and begins rendering its output.
+func (s *Session) Start(cols, rows int) error {
+	if s.proc != nil {
+		return errors.New("session already started")
+	}
+	proc, err := s.start(cols, rows)
+	if err != nil {
+		return fmt.Errorf("start %s: %w", s.title, err)
+	}
+	s.proc = proc
+	// Replies to terminal queries (cursor position, device attributes) go back to the process.
+	s.term = vt10x.New(vt10x.WithWriter(proc), vt10x.WithSize(cols, rows))
+
+	go s.readLoop()
+	return nil
+}
+
+func (s *Session) readLoop() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := s.proc.Read(buf)
+		if n > 0 {
+			_, _ = s.term.Write(buf[:n])
+			if s.onUpdate != nil {
+				s.onUpdate()
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	waitErr := s.proc.Wait()
+	s.mu.Lock()
+	s.exited = true
+	s.exitErr = waitErr
+	s.mu.Unlock()
+	close(s.done)
+
+	if s.onExit != nil {
+		s.onExit(waitErr)
+	}
+}
+
+// Title returns the session title, usually the server alias.
+func (s *Session) Title() string {
+	return s.title
+}
+
+// View exposes the emulator screen for rendering. It is nil before Start.
+func (s *Session) View() vt10x.View {
+	return s.term
+}
+
+// Write forwards input bytes to the process.
+func (s *Session) Write(p []byte) (int, error) {
+	if s.proc == nil || s.Exited() {
+		return 0, io.ErrClosedPipe
+	}
+	return s.proc.Write(p)
+}
+
+// Resize updates both the emulator and the process to cols x rows.
+func (s *Session) Resize(cols, rows int) error {
+	if s.proc == nil || cols <= 0 || rows <= 0 {
+		return nil
+	}
+	s.term.Resize(cols, rows)
+	return s.proc.Resize(cols, rows)
+}
+
+// Exited reports whether the process has terminated.
+func (s *Session) Exited() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+// Err returns the process exit error once it has exited.
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exitErr
+}
+
+// Done is closed when the process has exited.
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close terminates the process.
+func (s *Session) Close() error {
+	if s.proc == nil {
+		return nil
+	}
+	return s.proc.Close()
+}
@@ -0,0 +1,177 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package terminal
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeShell is an in-memory Process that upper-cases every input line.
+type fakeShell struct {
+	outR *io.PipeReader
+	outW *io.PipeWriter
+	inR  *io.PipeReader
+	inW  *io.PipeWriter
+
+	mu   sync.Mutex
+	size [2]int
+	done chan struct{}
+}
+
+func newFakeShell(cols, rows int) *fakeShell {
+	outR, outW := io.Pipe()
+	inR, inW := io.Pipe()
+	sh := &fakeShell{outR: outR, outW: outW, inR: inR, inW: inW, size: [2]int{cols, rows}, done: make(chan struct{})}
+	go sh.run()
+	return sh
+}
+
+func (f *fakeShell) run() {
+	defer close(f.done)
+	_, _ = io.WriteString(f.outW, "$ ")
+	scanner := bufio.NewScanner(f.inR)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "exit" {
+			break
+		}
+		_, _ = io.WriteString(f.outW, strings.ToUpper(line)+"\r\n$ ")
+	}
+	_ = f.outW.Close()
+}
+
+func (f *fakeShell) Read(p []byte) (int, error)  { return f.outR.Read(p) }
+func (f *fakeShell) Write(p []byte) (int, error) { return f.inW.Write(p) }
+func (f *fakeShell) Wait() error                 { <-f.done; return nil }
+func (f *fakeShell) Close() error                { return f.inW.Close() }
+
+func (f *fakeShell) Resize(cols, rows int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.size = [2]int{cols, rows}
+	return nil
+}
+
+func (f *fakeShell) Size() [2]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.size
+}
+
+func startFake(t *testing.T) (*Session, *fakeShell, chan struct{}) {
+	t.Helper()
+	var shell *fakeShell
+	updates := make(chan struct{}, 64)
+	session := NewSession("fake", func(cols, rows int) (Process, error) {
+		shell = newFakeShell(cols, rows)
+		return shell, nil
+	}).OnUpdate(func() {
+		select {
+		case updates <- struct{}{}:
+		default:
+		}
+	})
+	if err := session.Start(40, 10); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	return session, shell, updates
+}
+
+func waitForScreen(t *testing.T, s *Session, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		// String takes the emulator lock itself.
+		if strings.Contains(s.View().String(), want) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("screen never contained %q", want)
+}
+
+func TestSessionRendersOutputAndForwardsInput(t *testing.T) {
+	session, _, updates := startFake(t)
+	defer func() { _ = session.Close() }()
+
+	waitForScreen(t, session, "$")
+	select {
+	case <-updates:
+	default:
+		t.Fatal("OnUpdate was not called after output")
+	}
+
+	if _, err := session.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	waitForScreen(t, session, "HELLO")
+}
+
+func TestSessionResizePropagates(t *testing.T) {
+	session, shell, _ := startFake(t)
+	defer func() { _ = session.Close() }()
+
+	if err := session.Resize(100, 30); err != nil {
+		t.Fatalf("Resize failed: %v", err)
+	}
+	if got := shell.Size(); got != [2]int{100, 30} {
+		t.Fatalf("process size = %v, want [100 30]", got)
+	}
+	if cols, rows := session.View().Size(); cols != 100 || rows != 30 {
+		t.Fatalf("emulator size = %dx%d, want 100x30", cols, rows)
+	}
+}
+
+func TestSessionExit(t *testing.T) {
+	exited := make(chan error, 1)
+	session := NewSession("fake", func(cols, rows int) (Process, error) {
+		return newFakeShell(cols, rows), nil
+	}).OnExit(func(err error) { exited <- err })
+	if err := session.Start(40, 10); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	_, _ = session.Write([]byte("exit\n"))
+
+	select {
+	case err := <-exited:
+		if err != nil {
+			t.Fatalf("unexpected exit error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("session did not exit")
+	}
+	if !session.Exited() {
+		t.Fatal("Exited() = false after exit")
+	}
+	if _, err := session.Write([]byte("x")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("Write after exit = %v, want ErrClosedPipe", err)
+	}
+}
+
+func TestSessionStartError(t *testing.T) {
+	session := NewSession("broken", func(cols, rows int) (Process, error) {
+		return nil, errors.New("no pty")
+	})
+	if err := session.Start(80, 24); err == nil {
+		t.Fatal("expected start error")
+	}
+}
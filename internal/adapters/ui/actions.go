@@ -0,0 +1,308 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Sections of the help overlay, in display order.
+const (
+	sectionConnect    = "Connect"
+	sectionServers    = "Servers"
+	sectionTools      = "Tools"
+	sectionViews      = "Views & batch"
+	sectionNavigation = "Navigation"
+	sectionGeneral    = "General"
+)
+
+var actionSections = []string{sectionConnect, sectionServers, sectionTools, sectionViews, sectionNavigation, sectionGeneral}
+
+// action is a command of the server list that keys can be bound to. The hint bar, status
+// bar, details panel and help overlay are all generated from the registered actions.
+type action struct {
+	name    string
+	section string
+	// help describes the action in the help overlay and the details panel.
+	help string
+	// hint is the short label in the hint bar; empty leaves the action out.
+	hint string
+	// status is the short label in the status bar; empty leaves the action out.
+	status string
+	// groupHelp describes the action on a group of the tree view; empty when it does not apply.
+	groupHelp string
+	// hidden leaves the action out of the details panel.
+	hidden bool
+	keys   []string
+	run    func()
+}
+
+// buildActions registers the actions of the server list in display order.
+func (t *tui) buildActions() {
+	t.actions = []action{
+		{name: "connect", section: sectionConnect, help: "SSH connect", hint: "SSH", status: "SSH", groupHelp: "Collapse or expand", keys: []string{"Enter"}, run: t.handleServerConnect},
+		{name: "copy", section: sectionConnect, help: "Copy SSH command", hint: "Copy SSH", status: "Copy SSH", keys: []string{"c"}, run: t.handleCopyCommand},
+		{name: "cluster", section: sectionConnect, help: "Cluster SSH (tmux)", hint: "Cluster", groupHelp: "Cluster SSH (tmux)", keys: []string{"C"}, run: t.handleClusterConnect},
+		{name: "tab_open", section: sectionConnect, help: "Open in tab", keys: []string{"T"}, run: t.handleSessionOpen},
+		{name: "tabs", section: sectionConnect, help: "Show tabs", keys: []string{"W"}, run: t.handleSessionsShow},
+		{name: "exec", section: sectionTools, help: "Run command", groupHelp: "Run command", keys: []string{"x"}, run: t.handleRunCommand},
+		{name: "tunnels", section: sectionTools, help: "Tunnels", keys: []string{"f"}, run: t.handleTunnelsShow},
+		{name: "files", section: sectionTools, help: "Browse files", keys: []string{"b"}, run: t.handleFileBrowser},
+		{name: "transfers", section: sectionTools, help: "Transfers (push/pull)", groupHelp: "Transfers (push/pull)", keys: []string{"u"}, run: t.handleTransfersShow},
+		{name: "remote_edit", section: sectionTools, help: "Edit remote file", keys: []string{"E"}, run: t.handleRemoteEdit},
+		{name: "keys", section: sectionTools, help: "SSH keys", keys: []string{"K"}, run: t.handleKeysShow},
+		{name: "key_install", section: sectionTools, help: "Install public key", groupHelp: "Install public key", keys: []string{"I"}, run: t.handleKeyInstall},
+		{name: "known_hosts", section: sectionTools, help: "Known hosts", keys: []string{"H"}, run: t.handleKnownHostsShow},
+		{name: "verify_host_key", section: sectionTools, help: "Verify host key", keys: []string{"V"}, run: t.handleHostKeyVerify},
+		{name: "agent", section: sectionTools, help: "ssh-agent", keys: []string{"A"}, run: t.handleAgentShow},
+		{name: "views", section: sectionViews, help: "Saved views", keys: []string{"v"}, run: t.handleViewsToggle},
+		{name: "group_mode", section: sectionViews, help: "Group by tag/domain/file", groupHelp: "Change grouping", keys: []string{"G"}, run: t.handleGroupModeToggle},
+		{name: "mark", section: sectionViews, help: "Mark for batch", keys: []string{"Space"}, run: t.handleMarkToggle},
+		{name: "mark_all", section: sectionViews, help: "Mark all listed", keys: []string{"M"}, run: t.handleMarkAll},
+		{name: "clear_marks", section: sectionViews, help: "Clear marks", keys: []string{"Esc"}, run: t.handleMarksClear},
+		{name: "bulk_edit", section: sectionViews, help: "Bulk find & replace", keys: []string{"R"}, run: t.handleBulkEdit},
+		{name: "ping", section: sectionServers, help: "Ping server", hint: "Ping", status: "Ping", groupHelp: "Ping all", keys: []string{"g"}, run: t.handlePingSelected},
+		{name: "refresh", section: sectionServers, help: "Refresh list", hint: "Refresh", keys: []string{"r"}, run: t.handleRefreshBackground},
+		{name: "add", section: sectionServers, help: "Add new server", hint: "Add", status: "Add", keys: []string{"a"}, run: t.handleServerAdd},
+		{name: "edit", section: sectionServers, help: "Edit entry", hint: "Edit", status: "Edit", keys: []string{"e"}, run: t.handleServerEdit},
+		{name: "tags", section: sectionServers, help: "Edit tags", hint: "Tags", keys: []string{"t"}, run: t.handleTagsEdit},
+		{name: "delete", section: sectionServers, help: "Delete entry", hint: "Delete", status: "Delete", keys: []string{"d"}, run: t.handleServerDelete},
+		{name: "pin", section: sectionServers, help: "Pin/Unpin", hint: "Pin/Unpin", status: "Pin/Unpin", keys: []string{"p"}, run: t.handleServerPin},
+		{name: "sort", section: sectionServers, help: "Change sort field", hint: "Sort", keys: []string{"s"}, run: t.handleSortToggle},
+		{name: "sort_reverse", section: sectionServers, help: "Reverse sort order", keys: []string{"S"}, run: t.handleSortReverse},
+		{name: "down", section: sectionNavigation, help: "Next entry", hidden: true, keys: []string{"Down", "j"}, run: t.handleNavigateDown},
+		{name: "up", section: sectionNavigation, help: "Previous entry", hidden: true, keys: []string{"Up", "k"}, run: t.handleNavigateUp},
+		{name: "page_down", section: sectionNavigation, help: "Page down", hidden: true, keys: []string{"PgDn"}, run: func() { t.handleNavigatePage(1) }},
+		{name: "page_up", section: sectionNavigation, help: "Page up", hidden: true, keys: []string{"PgUp"}, run: func() { t.handleNavigatePage(-1) }},
+		{name: "first", section: sectionNavigation, help: "First entry", hidden: true, keys: []string{"Home"}, run: func() { t.handleNavigateTo(0) }},
+		{name: "last", section: sectionNavigation, help: "Last entry", hidden: true, keys: []string{"End"}, run: func() { t.handleNavigateTo(t.serverList.GetItemCount() - 1) }},
+		{name: "collapse", section: sectionNavigation, help: "Collapse group", hidden: true, groupHelp: "Collapse", keys: []string{"Left"}, run: func() { t.handleGroupCollapse(true) }},
+		{name: "expand", section: sectionNavigation, help: "Expand group", hidden: true, groupHelp: "Expand", keys: []string{"Right"}, run: func() { t.handleGroupCollapse(false) }},
+		{name: "command", section: sectionGeneral, help: "Command line", status: "Command", keys: []string{":"}, run: t.handleCommandShow},
+		{name: "search", section: sectionGeneral, help: "Search", status: "Search", keys: []string{"/"}, run: t.handleSearchToggle},
+		{name: "help", section: sectionGeneral, help: "Show key bindings", status: "Help", keys: []string{"?"}, run: t.handleHelpShow},
+		{name: "quit", section: sectionGeneral, help: "Quit", status: "Quit", keys: []string{"q"}, run: t.handleQuit},
+	}
+	for i := 0; i <= 9; i++ {
+		index := i
+		help := "Show saved view " + strconv.Itoa(i)
+		if i == 0 {
+			help = "Show all servers"
+		}
+		t.actions = append(t.actions, action{name: "view_" + strconv.Itoa(i), section: sectionViews, help: help, hidden: true, keys: []string{strconv.Itoa(i)}, run: func() { t.applyView(index) }})
+	}
+}
+
+// loadKeymap resolves the key bindings from the built-in actions, the configured preset
+// and the user's bindings. Problems are logged and returned for the status bar.
+func (t *tui) loadKeymap() []error {
+	base := make(map[string][]string, len(t.actions))
+	for _, a := range t.actions {
+		base[a.name] = a.keys
+	}
+	keymap, errs := domain.ResolveKeymap(base, keymapPresets[t.settings.Keys.Preset], t.settings.Keys.Bindings)
+	for _, err := range errs {
+		t.logger.Warnw("invalid key binding", "error", err)
+	}
+
+	t.keymap = keymap
+	t.keyActions = make(map[string]action)
+	for _, a := range t.actions {
+		for _, key := range keymap[a.name] {
+			t.keyActions[key] = a
+		}
+	}
+	return errs
+}
+
+// keymapPresets holds the bindings each preset changes from the defaults.
+var keymapPresets = map[domain.KeymapPreset]map[string][]string{
+	domain.KeymapVim: {
+		"first":      {"g", "Home"},
+		"last":       {"G", "End"},
+		"page_down":  {"Ctrl+D", "PgDn"},
+		"page_up":    {"Ctrl+U", "PgUp"},
+		"collapse":   {"h", "Left"},
+		"expand":     {"l", "Right"},
+		"group_mode": {"z"},
+		"ping":       {"P"},
+	},
+	domain.KeymapEmacs: {
+		"down":        {"Ctrl+N", "Down"},
+		"up":          {"Ctrl+P", "Up"},
+		"page_down":   {"Ctrl+V", "PgDn"},
+		"page_up":     {"Alt+v", "PgUp"},
+		"first":       {"Alt+<", "Home"},
+		"last":        {"Alt+>", "End"},
+		"collapse":    {"Ctrl+B", "Left"},
+		"expand":      {"Ctrl+F", "Right"},
+		"search":      {"Ctrl+S", "/"},
+		"clear_marks": {"Ctrl+G", "Esc"},
+	},
+}
+
+// eventKeyName returns the name of a key event in the form used by the keymap.
+func eventKeyName(event *tcell.EventKey) string {
+	mods := event.Modifiers()
+	var name string
+	switch key := event.Key(); {
+	case key == tcell.KeyRune:
+		name = string(event.Rune())
+		if event.Rune() == ' ' {
+			name = "Space"
+		}
+	// Ctrl+H, Ctrl+I and Ctrl+M arrive as these keys, which is why NormalizeKey rejects them.
+	case key == tcell.KeyEnter:
+		name = "Enter"
+	case key == tcell.KeyTab:
+		name = "Tab"
+	case key == tcell.KeyEscape:
+		name = "Esc"
+	case key == tcell.KeyBackspace || key == tcell.KeyBackspace2:
+		name = "Backspace"
+	case key >= tcell.KeyCtrlA && key <= tcell.KeyCtrlZ:
+		name = string(rune('A' + key - tcell.KeyCtrlA))
+		mods |= tcell.ModCtrl
+	default:
+		name = tcell.KeyNames[key]
+	}
+	if mods&tcell.ModAlt != 0 {
+		name = "Alt+" + name
+	}
+	if mods&tcell.ModCtrl != 0 {
+		name = "Ctrl+" + name
+	}
+	return name
+}
+
+// keyLabel returns how a key is displayed.
+func keyLabel(key string) string {
+	switch key {
+	case "Up":
+		return "↑"
+	case "Down":
+		return "↓"
+	case "Left":
+		return "←"
+	case "Right":
+		return "→"
+	}
+	return tview.Escape(key)
+}
+
+// actionKeys returns the labels of the keys bound to an action joined by sep, or "" when it is unbound.
+func (t *tui) actionKeys(name, sep string) string {
+	keys := t.keymap[name]
+	labels := make([]string, 0, len(keys))
+	for _, key := range keys {
+		labels = append(labels, keyLabel(key))
+	}
+	return strings.Join(labels, sep)
+}
+
+// hintText is the text of the hint bar shown above the server list.
+func (t *tui) hintText() string {
+	parts := []string{"Press [::b]" + t.actionKeys("search", "/") + "[-:-:b] to search…"}
+	if keys := t.firstKeys("up", "down"); keys != "" {
+		parts = append(parts, keys+" Navigate")
+	}
+	for _, a := range t.actions {
+		if key := t.firstKey(a.name); a.hint != "" && key != "" {
+			parts = append(parts, key+" "+a.hint)
+		}
+	}
+	return "[" + theme.Subtle + "]" + strings.Join(parts, "  •  ") + "[-]"
+}
+
+// defaultStatusText is the text the status bar returns to after a temporary message.
+func (t *tui) defaultStatusText() string {
+	parts := []string{}
+	if keys := t.firstKeys("up", "down"); keys != "" {
+		parts = append(parts, "["+theme.Strong+"]"+keys+"[-] Navigate")
+	}
+	for _, a := range t.actions {
+		if key := t.firstKey(a.name); a.status != "" && key != "" {
+			parts = append(parts, "["+theme.Strong+"]"+key+"[-] "+a.status)
+		}
+	}
+	return strings.Join(parts, "  • ")
+}
+
+// firstKey returns the label of the first key bound to an action.
+func (t *tui) firstKey(name string) string {
+	if keys := t.keymap[name]; len(keys) > 0 {
+		return keyLabel(keys[0])
+	}
+	return ""
+}
+
+// firstKeys joins the first keys of several actions, e.g. "↑↓".
+func (t *tui) firstKeys(names ...string) string {
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(t.firstKey(name))
+	}
+	return b.String()
+}
+
+// commandsText lists the bound actions for the details panel; group selects the actions that apply to a group.
+func (t *tui) commandsText(group bool) string {
+	var b strings.Builder
+	for _, a := range t.actions {
+		help := a.help
+		if group {
+			help = a.groupHelp
+		} else if a.hidden {
+			continue
+		}
+		if keys := t.actionKeys(a.name, "/"); help != "" && keys != "" {
+			fmt.Fprintf(&b, "\n  %s: %s", keys, help)
+		}
+	}
+	return b.String()
+}
+
+// helpText lists every action by section for the help overlay.
+func (t *tui) helpText() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Keymap preset: ["+theme.Strong+"]%s[-]\n", t.settings.Keys.Preset)
+	for _, section := range actionSections {
+		fmt.Fprintf(&b, "\n[::b]%s[-]\n", section)
+		for _, a := range t.actions {
+			if a.section != section {
+				continue
+			}
+			keys := t.actionKeys(a.name, ", ")
+			if keys == "" {
+				keys = "[" + theme.Dim + "](unbound)[-]"
+			}
+			fmt.Fprintf(&b, "  ["+theme.Warning+"]%-16s[-] %-28s ["+theme.Dim+"]%s[-]\n", a.name, a.help, keys)
+		}
+	}
+
+	b.WriteString("\n[::b]Commands[-] (completions appear as you type; every action above is also a command)\n")
+	for _, c := range t.commands {
+		if c.usage != "" {
+			fmt.Fprintf(&b, "  ["+theme.Warning+"]:%-15s[-] %-28s ["+theme.Dim+"]%s[-]\n", c.name, tview.Escape(c.usage), c.help)
+		}
+	}
+	return b.String()
+}
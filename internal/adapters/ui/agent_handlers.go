@@ -0,0 +1,188 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+func (t *tui) handleAgentShow() {
+	view := NewAgentView()
+	view.OnClose(func() {
+		t.agentView = nil
+		t.returnToMain()
+		t.refreshServerList()
+	}).OnAdd(func() {
+		t.showAgentAddForm()
+	}).OnRemove(func(identity domain.AgentIdentity) {
+		if err := t.agentService.Remove(identity); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Remove from agent: %v", err), theme.Error)
+		}
+		t.refreshAgent()
+	}).OnReload(func() {
+		t.refreshAgent()
+	})
+
+	t.agentView = view
+	t.refreshAgent()
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+}
+
+func (t *tui) refreshAgent() {
+	if t.agentView == nil {
+		return
+	}
+	identities, err := t.agentService.List()
+	if err != nil {
+		t.agentView.SetError(err)
+		return
+	}
+	t.agentView.SetIdentities(identities)
+}
+
+// showAgentAddForm loads one of the user's keys into the agent.
+func (t *tui) showAgentAddForm() {
+	keys, err := t.keyService.List()
+	if err != nil {
+		t.logger.Errorw("failed to list keys", "error", err)
+	}
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		names = append(names, filepath.Base(key.Path))
+	}
+
+	title := "Add Key to ssh-agent"
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	pathField := tview.NewInputField().SetLabel("Path:").SetFieldWidth(50)
+	if len(keys) > 0 {
+		pathField.SetText(keys[0].Path)
+		form.AddDropDown("Key:", names, 0, func(option string, index int) {
+			if index >= 0 && index < len(keys) {
+				pathField.SetText(keys[index].Path)
+			}
+		})
+	}
+	form.AddFormItem(pathField)
+	passField := tview.NewInputField().SetLabel("Passphrase:").SetFieldWidth(40).SetMaskCharacter('*')
+	lifetimeField := tview.NewInputField().SetLabel("Lifetime:").SetFieldWidth(30).
+		SetPlaceholder("e.g. 8h; empty = until removed")
+	form.AddFormItem(passField).AddFormItem(lifetimeField)
+
+	back := func() {
+		t.app.SetRoot(t.agentView, true)
+		t.app.SetFocus(t.agentView)
+	}
+	form.AddButton("Add", func() {
+		lifetime, err := parseLifetime(lifetimeField.GetText())
+		if err == nil {
+			err = t.agentService.Add(strings.TrimSpace(pathField.GetText()), passField.GetText(), lifetime)
+		}
+		if err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%v[-]", title, err))
+			return
+		}
+		back()
+		t.refreshAgent()
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// offerAgentKey runs connect right away unless one of the server's keys is
+// passphrase-protected and not loaded in the agent; then it first offers to add it.
+func (t *tui) offerAgentKey(server domain.Server, connect func()) {
+	statuses, err := t.agentService.KeyStatus(server)
+	if err != nil {
+		if !errors.Is(err, domain.ErrAgentUnavailable) {
+			t.logger.Warnw("failed to query ssh-agent", "error", err)
+		}
+		connect()
+		return
+	}
+	var locked *domain.AgentKeyStatus
+	for i := range statuses {
+		if statuses[i].Encrypted && !statuses[i].Loaded {
+			locked = &statuses[i]
+			break
+		}
+	}
+	if locked == nil {
+		connect()
+		return
+	}
+
+	title := "Unlock " + filepath.Base(locked.Path)
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	form.AddTextView("", fmt.Sprintf("%s is passphrase-protected and not loaded in ssh-agent.\nAdd it so %s and later connections don't ask again.", locked.Path, server.Alias), 70, 2, false, false)
+	passField := tview.NewInputField().SetLabel("Passphrase:").SetFieldWidth(40).SetMaskCharacter('*')
+	lifetimeField := tview.NewInputField().SetLabel("Lifetime:").SetFieldWidth(30).
+		SetPlaceholder("e.g. 8h; empty = until removed")
+	form.AddFormItem(passField).AddFormItem(lifetimeField)
+
+	back := func() {
+		t.returnToMain()
+	}
+	form.AddButton("Add and connect", func() {
+		lifetime, err := parseLifetime(lifetimeField.GetText())
+		if err == nil {
+			err = t.agentService.Add(locked.Path, passField.GetText(), lifetime)
+		}
+		if err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%v[-]", title, err))
+			return
+		}
+		back()
+		connect()
+	})
+	form.AddButton("Connect without", func() {
+		back()
+		connect()
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(passField)
+}
+
+// parseLifetime reads an agent key lifetime; empty means no limit.
+func parseLifetime(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	lifetime, err := time.ParseDuration(text)
+	if err != nil || lifetime < time.Second {
+		return 0, fmt.Errorf("lifetime must be a duration like 30m or 8h")
+	}
+	return lifetime, nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// AgentView lists the identities loaded in ssh-agent.
+type AgentView struct {
+	*tview.Flex
+	identities []domain.AgentIdentity
+	table      *tview.Table
+	footer     *tview.TextView
+	onAdd      func()
+	onRemove   func(domain.AgentIdentity)
+	onReload   func()
+	onClose    func()
+}
+
+func NewAgentView() *AgentView {
+	view := &AgentView{
+		Flex:   tview.NewFlex(),
+		table:  tview.NewTable(),
+		footer: tview.NewTextView(),
+	}
+	view.build()
+	return view
+}
+
+func (v *AgentView) build() {
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetTitle(" ssh-agent ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+	v.footer.SetText(" [" + theme.Strong + "]a[-] Add key  • [" + theme.Strong + "]d[-] Remove  • [" + theme.Strong + "]r[-] Reload  • [" + theme.Strong + "]Esc[-] Back")
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.Flex.SetInputCapture(v.handleKeys)
+	v.render()
+}
+
+func (v *AgentView) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+		if v.onClose != nil {
+			v.onClose()
+		}
+		return nil
+	case event.Rune() == 'a':
+		if v.onAdd != nil {
+			v.onAdd()
+		}
+		return nil
+	case event.Rune() == 'd':
+		if identity, ok := v.Selected(); ok && v.onRemove != nil {
+			v.onRemove(identity)
+		}
+		return nil
+	case event.Rune() == 'r':
+		if v.onReload != nil {
+			v.onReload()
+		}
+		return nil
+	}
+	return event
+}
+
+// Selected returns the highlighted identity.
+func (v *AgentView) Selected() (domain.AgentIdentity, bool) {
+	row, _ := v.table.GetSelection()
+	if row < 1 || row > len(v.identities) {
+		return domain.AgentIdentity{}, false
+	}
+	return v.identities[row-1], true
+}
+
+// SetIdentities replaces the listed identities.
+func (v *AgentView) SetIdentities(identities []domain.AgentIdentity) {
+	v.identities = identities
+	v.render()
+}
+
+// SetError shows why the agent could not be queried.
+func (v *AgentView) SetError(err error) {
+	v.identities = nil
+	v.render()
+	v.table.SetCell(1, 0, tview.NewTableCell(err.Error()).
+		SetTextColor(theme.color(theme.Error)).
+		SetSelectable(false))
+}
+
+func (v *AgentView) render() {
+	v.table.Clear()
+	for col, header := range []string{"Type", "Fingerprint", "Comment"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, identity := range v.identities {
+		row := i + 1
+		v.table.SetCell(row, 0, tview.NewTableCell(identity.Type))
+		v.table.SetCell(row, 1, tview.NewTableCell(identity.Fingerprint))
+		v.table.SetCell(row, 2, tview.NewTableCell(tview.Escape(identity.Comment)).SetExpansion(1))
+	}
+
+	v.table.SetTitle(fmt.Sprintf(" ssh-agent (%d) ", len(v.identities)))
+	if len(v.identities) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(min(max(row, 1), len(v.identities)), 0)
+	}
+}
+
+func (v *AgentView) OnAdd(fn func()) *AgentView {
+	v.onAdd = fn
+	return v
+}
+
+func (v *AgentView) OnRemove(fn func(domain.AgentIdentity)) *AgentView {
+	v.onRemove = fn
+	return v
+}
+
+func (v *AgentView) OnReload(fn func()) *AgentView {
+	v.onReload = fn
+	return v
+}
+
+func (v *AgentView) OnClose(fn func()) *AgentView {
+	v.onClose = fn
+	return v
+}
@@ -0,0 +1,217 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/atotto/clipboard"
+	"github.com/rivo/tview"
+)
+
+// batchListLimit caps how many aliases a batch confirmation spells out.
+const batchListLimit = 40
+
+// markedServers returns the marked servers in list order, including marked servers
+// hidden by the current search.
+func (t *tui) markedServers() []domain.Server {
+	marked := t.serverList.Marked()
+	if len(marked) == 0 {
+		return nil
+	}
+	servers, err := t.serverService.ListServers("")
+	if err != nil {
+		t.logger.Errorw("failed to list servers for batch", "error", err)
+		return nil
+	}
+	wanted := make(map[string]bool, len(marked))
+	for _, alias := range marked {
+		wanted[alias] = true
+	}
+	result := make([]domain.Server, 0, len(marked))
+	for _, server := range servers {
+		if wanted[server.Alias] {
+			result = append(result, server)
+		}
+	}
+	return result
+}
+
+func (t *tui) handleMarkToggle() {
+	t.serverList.ToggleMark()
+	t.updateListTitle()
+}
+
+// handleMarkAll marks every listed server, or unmarks them all when they already are.
+func (t *tui) handleMarkAll() {
+	servers := t.serverList.Servers()
+	marked := !t.serverList.AllMarked()
+	t.serverList.SetMarked(servers, marked)
+	t.updateListTitle()
+	if marked {
+		t.showStatusTemp(fmt.Sprintf("Marked %d listed servers", len(servers)))
+	} else {
+		t.showStatusTemp(fmt.Sprintf("Unmarked %d listed servers", len(servers)))
+	}
+}
+
+func (t *tui) handleMarksClear() {
+	if len(t.serverList.Marked()) == 0 {
+		return
+	}
+	t.serverList.ClearMarks()
+	t.updateListTitle()
+	t.showStatusTemp("Marks cleared")
+}
+
+// batchAliases lists the aliases of servers for a confirmation, eliding long lists.
+func batchAliases(servers []domain.Server) string {
+	aliases := make([]string, 0, min(len(servers), batchListLimit))
+	for i, server := range servers {
+		if i == batchListLimit {
+			aliases = append(aliases, fmt.Sprintf("… and %d more", len(servers)-batchListLimit))
+			break
+		}
+		aliases = append(aliases, server.Alias)
+	}
+	return strings.Join(aliases, ", ")
+}
+
+// showBatchConfirm asks once before running action on every server listed in the prompt.
+func (t *tui) showBatchConfirm(prompt string, servers []domain.Server, action func()) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("%s\n\n%s", prompt, batchAliases(servers))).
+		AddButtons([]string{"Cancel", "Confirm"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.returnToMain()
+			if buttonIndex == 1 {
+				action()
+			}
+		})
+	t.app.SetRoot(modal, true)
+}
+
+func (t *tui) handleBatchDelete(servers []domain.Server) {
+	prompt := fmt.Sprintf("Delete %d servers?\n\nThe SSH config is written once, with one backup. This action cannot be undone.", len(servers))
+	t.showBatchConfirm(prompt, servers, func() {
+		if err := t.serverService.DeleteServers(servers); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Delete failed: %v", err), theme.Error)
+			return
+		}
+		t.serverList.SetMarked(servers, false)
+		t.refreshServerList()
+		t.updateListTitle()
+		t.showStatusTemp(fmt.Sprintf("Deleted %d servers", len(servers)))
+	})
+}
+
+// handleBatchPin pins every marked server, or unpins them when all are pinned already.
+func (t *tui) handleBatchPin(servers []domain.Server) {
+	pinned := false
+	for _, server := range servers {
+		if server.PinnedAt.IsZero() {
+			pinned = true
+			break
+		}
+	}
+	verb := "Unpin"
+	if pinned {
+		verb = "Pin"
+	}
+	aliases := make([]string, 0, len(servers))
+	for _, server := range servers {
+		aliases = append(aliases, server.Alias)
+	}
+	t.showBatchConfirm(fmt.Sprintf("%s %d servers?", verb, len(servers)), servers, func() {
+		if err := t.serverService.SetPinnedMany(aliases, pinned); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("%s failed: %v", verb, err), theme.Error)
+			return
+		}
+		t.refreshServerList()
+		t.showStatusTemp(fmt.Sprintf("%sned %d servers", verb, len(servers)))
+	})
+}
+
+// showBatchTagsForm adds and removes tags on every marked server in one write.
+func (t *tui) showBatchTagsForm(servers []domain.Server) {
+	title := fmt.Sprintf("Edit Tags: %d servers", len(servers))
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+
+	form.AddInputField("Add tags (comma):", "", 40, nil, nil)
+	form.AddInputField("Remove tags (comma):", "", 40, nil, nil)
+	form.AddTextView("Servers:", batchAliases(servers), 60, 4, false, true)
+
+	form.AddButton("Apply", func() {
+		add := splitTags(form.GetFormItem(0).(*tview.InputField).GetText())
+		remove := splitTags(form.GetFormItem(1).(*tview.InputField).GetText())
+		if len(add) == 0 && len(remove) == 0 {
+			form.SetTitle(title + " — [" + theme.Error + "::b]enter tags to add or remove[-]")
+			return
+		}
+
+		updates := make([]domain.ServerUpdate, 0, len(servers))
+		for _, server := range servers {
+			updated := server
+			updated.Tags = domain.EditTags(server.Tags, add, remove)
+			updates = append(updates, domain.ServerUpdate{Old: server, New: updated})
+		}
+		if err := t.serverService.UpdateServers(updates); err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%s[-]", title, tview.Escape(err.Error())))
+			return
+		}
+		t.returnToMain()
+		t.refreshServerList()
+		t.showStatusTemp(fmt.Sprintf("Updated tags on %d servers", len(servers)))
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// handleBatchCopy copies the SSH commands of every marked server, one per line.
+func (t *tui) handleBatchCopy(servers []domain.Server) {
+	commands := make([]string, 0, len(servers))
+	for _, server := range servers {
+		commands = append(commands, BuildSSHCommand(server))
+	}
+	if err := clipboard.WriteAll(strings.Join(commands, "\n")); err != nil {
+		t.showStatusTemp("Failed to copy to clipboard")
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Copied %d SSH commands", len(commands)))
+}
+
+// markedGroup wraps the marked servers so group actions can run on them.
+func markedGroup(servers []domain.Server) domain.ServerGroup {
+	return domain.ServerGroup{Name: fmt.Sprintf("%d marked", len(servers)), Servers: servers}
+}
+
+// splitTags parses a comma separated tag list, dropping empty entries.
+func splitTags(text string) []string {
+	var tags []string
+	for _, part := range strings.Split(text, ",") {
+		if tag := strings.TrimSpace(part); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
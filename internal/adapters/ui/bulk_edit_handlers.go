@@ -0,0 +1,242 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// bulkEditFields are the options offered by the bulk edit form; the last entry lets
+// the user type any other ssh_config keyword.
+var bulkEditFields = []string{"HostName", "User", "Port", "IdentityFile", "Other option"}
+
+const (
+	bulkScopeSelected = iota
+	bulkScopeTag
+	bulkScopeQuery
+	bulkScopeListed
+)
+
+// bulkEditRequest is what the bulk edit form collected, kept so the form can be reopened.
+type bulkEditRequest struct {
+	edit      domain.BulkEdit
+	scope     int
+	scopeText string
+}
+
+func (t *tui) handleBulkEdit() {
+	scope := bulkScopeSelected
+	if len(t.serverList.Marked()) == 0 {
+		if _, ok := t.serverList.GetSelectedGroup(); !ok {
+			scope = bulkScopeListed
+		}
+	}
+	t.showBulkEditForm(bulkEditRequest{edit: domain.BulkEdit{Option: "HostName"}, scope: scope})
+}
+
+// showBulkEditForm asks for the option, scope and replacement of a bulk edit.
+func (t *tui) showBulkEditForm(req bulkEditRequest) {
+	title := "Bulk Edit"
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+
+	field, other := len(bulkEditFields)-1, req.edit.Option
+	for i, name := range bulkEditFields[:len(bulkEditFields)-1] {
+		if strings.EqualFold(name, req.edit.Option) {
+			field, other = i, ""
+		}
+	}
+	scopes := []string{t.selectedScopeLabel(), "Servers with tag", "Servers matching query", fmt.Sprintf("All listed servers (%d)", len(t.serverList.Servers()))}
+
+	form.AddDropDown("Field:", bulkEditFields, field, nil)
+	form.AddInputField("Other option:", other, 20, nil, nil)
+	form.AddDropDown("Scope:", scopes, req.scope, nil)
+	form.AddInputField("Tag or query:", req.scopeText, 40, nil, nil)
+	form.AddInputField("Find:", req.edit.Find, 40, nil, nil)
+	form.AddInputField("Replace with:", req.edit.Replace, 40, nil, nil)
+	form.AddCheckbox("Regular expression:", req.edit.Regex, nil)
+
+	form.AddButton("Preview", func() {
+		fieldIdx, option := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		if fieldIdx == len(bulkEditFields)-1 {
+			option = strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		}
+		scope, _ := form.GetFormItem(2).(*tview.DropDown).GetCurrentOption()
+		req := bulkEditRequest{
+			edit: domain.BulkEdit{
+				Option:  option,
+				Find:    form.GetFormItem(4).(*tview.InputField).GetText(),
+				Replace: form.GetFormItem(5).(*tview.InputField).GetText(),
+				Regex:   form.GetFormItem(6).(*tview.Checkbox).IsChecked(),
+			},
+			scope:     scope,
+			scopeText: strings.TrimSpace(form.GetFormItem(3).(*tview.InputField).GetText()),
+		}
+
+		servers, err := t.bulkEditScope(req)
+		if err == nil && len(servers) == 0 {
+			err = fmt.Errorf("no servers in scope")
+		}
+		var preview domain.BulkPreview
+		if err == nil {
+			preview, err = t.bulkEditService.Preview(servers, req.edit)
+		}
+		if err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%s[-]", title, tview.Escape(err.Error())))
+			return
+		}
+		t.showBulkEditPreview(req, preview)
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// bulkEditScope returns the servers a bulk edit applies to.
+func (t *tui) bulkEditScope(req bulkEditRequest) ([]domain.Server, error) {
+	switch req.scope {
+	case bulkScopeSelected:
+		return t.selectedServers(), nil
+	case bulkScopeTag:
+		if req.scopeText == "" {
+			return nil, fmt.Errorf("enter a tag")
+		}
+		servers, err := t.serverService.ListServers("")
+		if err != nil {
+			return nil, err
+		}
+		var tagged []domain.Server
+		for _, server := range servers {
+			if hasTag(server, req.scopeText) {
+				tagged = append(tagged, server)
+			}
+		}
+		return tagged, nil
+	case bulkScopeQuery:
+		if req.scopeText == "" {
+			return nil, fmt.Errorf("enter a query")
+		}
+		return t.serverService.ListServers(req.scopeText)
+	default:
+		return t.serverList.Servers(), nil
+	}
+}
+
+// showBulkEditPreview shows the before and after values and applies them on confirmation.
+func (t *tui) showBulkEditPreview(req bulkEditRequest, preview domain.BulkPreview) {
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(renderBulkPreview(preview))
+	text.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Bulk edit %s: %d changes ", tview.Escape(preview.Edit.Option), len(preview.Changes))).
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	footer := tview.NewTextView().SetDynamicColors(true)
+	footer.SetBackgroundColor(theme.Bar)
+	switch {
+	case len(preview.Changes) == 0:
+		footer.SetText(" Nothing to change  • [" + theme.Strong + "]e[-] Edit  • [" + theme.Strong + "]n/Esc[-] Cancel")
+	case !preview.Valid():
+		footer.SetText(" [" + theme.Error + "]Fix the invalid values first[-]  • [" + theme.Strong + "]e[-] Edit  • [" + theme.Strong + "]n/Esc[-] Cancel")
+	default:
+		footer.SetText(fmt.Sprintf(" ["+theme.Strong+"]y[-] Apply to %d servers (one backup)  • ["+theme.Strong+"]e[-] Edit  • ["+theme.Strong+"]n/Esc[-] Cancel", len(preview.Changes)))
+	}
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Rune() == 'y':
+			if len(preview.Changes) == 0 || !preview.Valid() {
+				return nil
+			}
+			if err := t.bulkEditService.Apply(preview); err != nil {
+				footer.SetText(fmt.Sprintf(" ["+theme.Error+"]%s[-]  • ["+theme.Strong+"]e[-] Edit  • ["+theme.Strong+"]n/Esc[-] Cancel", tview.Escape(err.Error())))
+				return nil
+			}
+			t.returnToMain()
+			t.refreshServerList()
+			t.showStatusTemp(fmt.Sprintf("Updated %s on %d servers", preview.Edit.Option, len(preview.Changes)))
+			return nil
+		case event.Rune() == 'e':
+			t.showBulkEditForm(req)
+			return nil
+		case event.Rune() == 'n' || event.Key() == tcell.KeyEsc:
+			t.returnToMain()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+}
+
+// renderBulkPreview lists each change as before → after lines, with a summary on top.
+func renderBulkPreview(preview domain.BulkPreview) string {
+	var b strings.Builder
+	edit := preview.Edit
+	mode := "text"
+	if edit.Regex {
+		mode = "regex"
+	}
+	fmt.Fprintf(&b, "[::b]%s[-:-:-]: replace %s ["+theme.Warning+"]%s[-] with ["+theme.Warning+"]%s[-]\n",
+		tview.Escape(edit.Option), mode, tview.Escape(edit.Find), tview.Escape(edit.Replace))
+	fmt.Fprintf(&b, "["+theme.Dim+"]%d changes, %d unchanged", len(preview.Changes), preview.Unchanged)
+	if len(preview.Skipped) > 0 {
+		fmt.Fprintf(&b, ", %d skipped (defined in included files: %s)", len(preview.Skipped), tview.Escape(strings.Join(preview.Skipped, ", ")))
+	}
+	b.WriteString("[-]\n\n")
+
+	width := 0
+	for _, change := range preview.Changes {
+		width = max(width, len(change.Alias))
+	}
+	for _, change := range preview.Changes {
+		lines := max(len(change.Before), len(change.After))
+		for i := 0; i < lines; i++ {
+			alias := ""
+			if i == 0 {
+				alias = change.Alias
+			}
+			before, after := "", "["+theme.Dim+"](removed)[-]"
+			if i < len(change.Before) {
+				before = tview.Escape(change.Before[i])
+			}
+			if i < len(change.After) {
+				after = "[" + theme.Success + "]" + tview.Escape(change.After[i]) + "[-]"
+			}
+			fmt.Fprintf(&b, "["+theme.Strong+"::b]%-*s[-:-:-]  ["+theme.Error+"]%s[-]  →  %s\n", width, tview.Escape(alias), before, after)
+		}
+		if change.Error != "" {
+			fmt.Fprintf(&b, "%*s  ["+theme.Error+"]⚠ %s[-]\n", width, "", tview.Escape(change.Error))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+)
+
+// renewCertificate runs the configured renew command on the terminal when the
+// certificate of alias has expired. It must be called while the TUI is suspended.
+func (t *tui) renewCertificate(alias string) error {
+	renewed, err := t.certService.RenewIfNeeded(alias)
+	if err != nil {
+		t.logger.Warnw("certificate renewal failed", "alias", alias, "error", err)
+		_, _ = fmt.Fprintf(os.Stderr, "%s: certificate renewal failed: %v\n", AppName, err)
+		return err
+	}
+	if renewed {
+		t.logger.Infow("certificate renewed", "alias", alias)
+	}
+	return nil
+}
+
+// certificateWarning explains why server's certificate will not be accepted, or
+// returns "" when it is usable or a renew command will take care of it.
+func (t *tui) certificateWarning(server domain.Server) string {
+	status := t.certService.Status(server)
+	switch {
+	case status == "" || status == domain.CertificateValid || status == domain.CertificateExpiring:
+		return ""
+	case status.NeedsRenewal() && t.certService.CanRenew():
+		return ""
+	case status.NeedsRenewal():
+		return fmt.Sprintf("Certificate of %s is %s — set certificates.renew_command to renew it automatically", server.Alias, status)
+	default:
+		return fmt.Sprintf("Certificate of %s is %s", server.Alias, status)
+	}
+}
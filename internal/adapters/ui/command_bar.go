@@ -0,0 +1,76 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// CommandBar is the ":" command line shown in place of the status bar.
+type CommandBar struct {
+	*tview.InputField
+	onSubmit func(string)
+	onCancel func()
+}
+
+func NewCommandBar() *CommandBar {
+	bar := &CommandBar{
+		InputField: tview.NewInputField(),
+	}
+	bar.build()
+	return bar
+}
+
+func (c *CommandBar) build() {
+	c.InputField.SetLabel(":").
+		SetLabelColor(theme.Title).
+		SetFieldBackgroundColor(theme.Bar).
+		SetFieldTextColor(theme.Text).
+		SetFieldWidth(0)
+	c.InputField.SetBackgroundColor(theme.Bar)
+	c.InputField.SetAutocompleteStyles(theme.Header,
+		tcell.StyleDefault.Foreground(theme.Text),
+		theme.SelectedStyle())
+
+	c.InputField.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			if c.onSubmit != nil {
+				c.onSubmit(c.GetText())
+			}
+		case tcell.KeyEsc:
+			if c.onCancel != nil {
+				c.onCancel()
+			}
+		}
+	})
+}
+
+// OnComplete sets the function listing completions for the text typed so far.
+func (c *CommandBar) OnComplete(fn func(string) []string) *CommandBar {
+	c.InputField.SetAutocompleteFunc(fn)
+	return c
+}
+
+func (c *CommandBar) OnSubmit(fn func(string)) *CommandBar {
+	c.onSubmit = fn
+	return c
+}
+
+func (c *CommandBar) OnCancel(fn func()) *CommandBar {
+	c.onCancel = fn
+	return c
+}
@@ -0,0 +1,529 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maxCompletions caps the entries of the completion list.
+const maxCompletions = 12
+
+// paletteCommand is a command of the ":" command line. Every action is also a command
+// under its own name; these take arguments.
+type paletteCommand struct {
+	name  string
+	usage string
+	help  string
+	// complete lists candidates for the last of args, the words typed so far; nil for free text.
+	complete func(args []string) []string
+	run      func(args string) error
+}
+
+// buildCommands registers the commands that take arguments. Without arguments, a
+// command sharing its name with an action runs that action.
+func (t *tui) buildCommands() {
+	t.commands = []paletteCommand{
+		{name: "connect", usage: "<alias>", help: "SSH to a server", complete: t.completeAliases, run: t.commandConnect},
+		{name: "tag", usage: "+add -remove …", help: "Add or remove tags on the selected servers", complete: t.completeTags, run: t.commandTag},
+		{name: "sort", usage: "<field> [asc|desc]", help: "Sort the list by " + strings.Join(sortFieldNames(), ", "), complete: completeSort, run: t.commandSort},
+		{name: "view", usage: "[name]", help: "Apply a saved view, or show all servers", complete: t.completeViews, run: t.commandView},
+		{name: "group", usage: "tag|domain|file|none", help: "Group the list", complete: completeGroup, run: t.commandGroup},
+		{name: "columns", usage: "[column …]", help: "Choose the list columns, or restore the configured ones", complete: completeColumns, run: t.commandColumns},
+		{name: "width", usage: "<column> <n|auto>", help: "Limit the width of a list column", complete: t.completeWidth, run: t.commandWidth},
+		{name: "search", usage: "<query>", help: "Search with a query", run: t.commandSearch},
+		{name: "exec", usage: "<command>", help: "Run a command on the selected servers", run: t.commandExec},
+		{name: "backup", usage: "[list|restore <n>]", help: "List or restore SSH config backups", complete: completeBackup, run: t.commandBackup},
+	}
+	for _, a := range t.actions {
+		if _, ok := t.findCommand(a.name); !ok {
+			run := a.run
+			t.commands = append(t.commands, paletteCommand{name: a.name, help: a.help, run: func(string) error {
+				run()
+				return nil
+			}})
+		}
+	}
+}
+
+// findCommand looks up a command by name or unambiguous prefix.
+func (t *tui) findCommand(name string) (paletteCommand, bool) {
+	var found []paletteCommand
+	for _, c := range t.commands {
+		if c.name == name {
+			return c, true
+		}
+		if strings.HasPrefix(c.name, name) {
+			found = append(found, c)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true
+	}
+	return paletteCommand{}, false
+}
+
+// handleCommandShow opens the command line in place of the status bar.
+func (t *tui) handleCommandShow() {
+	t.commandBar.SetText("")
+	t.root.RemoveItem(t.statusBar)
+	t.root.AddItem(t.commandBar, 1, 0, true)
+	t.app.SetFocus(t.commandBar)
+}
+
+func (t *tui) hideCommandBar() {
+	t.root.RemoveItem(t.commandBar)
+	t.root.AddItem(t.statusBar, 1, 0, false)
+	t.app.SetFocus(t.serverList)
+}
+
+// handleCommandSubmit records line in the history and runs it.
+func (t *tui) handleCommandSubmit(line string) {
+	t.hideCommandBar()
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	if err := t.commandHistory.Add(line); err != nil {
+		t.logger.Warnw("failed to save command history", "error", err)
+	}
+	name, args, _ := strings.Cut(line, " ")
+	command, ok := t.findCommand(name)
+	if !ok {
+		t.showStatusTempColor(fmt.Sprintf("Unknown command: %s", name), theme.Error)
+		return
+	}
+	if err := command.run(strings.TrimSpace(args)); err != nil {
+		t.showStatusTempColor(fmt.Sprintf("%s: %v", command.name, err), theme.Error)
+	}
+}
+
+// completeCommand lists the history entries starting with text, then the command
+// names or argument values completing it.
+func (t *tui) completeCommand(text string) []string {
+	var entries []string
+	add := func(entry string) {
+		if entry != text && len(entries) < maxCompletions && !containsFold(entries, entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	history, _ := t.commandHistory.List()
+	for _, line := range history {
+		if strings.HasPrefix(strings.ToLower(line), strings.ToLower(text)) {
+			add(line)
+		}
+	}
+	if text == "" {
+		return entries
+	}
+
+	name, rest, hasArgs := strings.Cut(text, " ")
+	if !hasArgs {
+		for _, c := range t.commands {
+			if strings.HasPrefix(c.name, strings.ToLower(name)) {
+				add(c.name)
+			}
+		}
+		return entries
+	}
+
+	command, ok := t.findCommand(name)
+	if !ok || command.complete == nil {
+		return entries
+	}
+	args := strings.Fields(rest)
+	if len(args) == 0 || strings.HasSuffix(rest, " ") {
+		args = append(args, "")
+	}
+	partial := strings.ToLower(args[len(args)-1])
+	prefix := strings.Join(append([]string{command.name}, args[:len(args)-1]...), " ") + " "
+	for _, candidate := range command.complete(args) {
+		if strings.HasPrefix(strings.ToLower(candidate), partial) {
+			add(prefix + candidate)
+		}
+	}
+	return entries
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tui) completeAliases(args []string) []string {
+	if len(args) > 1 {
+		return nil
+	}
+	servers, _ := t.serverService.ListServers("")
+	aliases := make([]string, 0, len(servers))
+	for _, server := range servers {
+		aliases = append(aliases, server.Alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// completeTags offers +tag for every known tag and -tag for the tags of the selected servers.
+func (t *tui) completeTags(args []string) []string {
+	last := args[len(args)-1]
+	var tags []string
+	if strings.HasPrefix(last, "-") {
+		for _, server := range t.selectedServers() {
+			tags = append(tags, server.Tags...)
+		}
+	} else {
+		servers, _ := t.serverService.ListServers("")
+		for _, server := range servers {
+			tags = append(tags, server.Tags...)
+		}
+	}
+	sign := "+"
+	if strings.HasPrefix(last, "-") {
+		sign = "-"
+	}
+	var candidates []string
+	for _, tag := range tags {
+		if !containsFold(candidates, sign+tag) {
+			candidates = append(candidates, sign+tag)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+func completeSort(args []string) []string {
+	switch len(args) {
+	case 1:
+		return sortFieldNames()
+	case 2:
+		return []string{"asc", "desc"}
+	}
+	return nil
+}
+
+func completeColumns(args []string) []string {
+	candidates := make([]string, 0, len(domain.ListColumns))
+	for _, column := range domain.ListColumns {
+		if !containsFold(args[:len(args)-1], string(column)) {
+			candidates = append(candidates, string(column))
+		}
+	}
+	return candidates
+}
+
+func (t *tui) completeWidth(args []string) []string {
+	switch len(args) {
+	case 1:
+		return completeColumns(args)
+	case 2:
+		return []string{"auto"}
+	}
+	return nil
+}
+
+func (t *tui) completeViews(args []string) []string {
+	if len(args) > 1 {
+		return nil
+	}
+	names := make([]string, 0, len(t.views))
+	for _, view := range t.views {
+		names = append(names, view.Name)
+	}
+	return names
+}
+
+func completeGroup(args []string) []string {
+	if len(args) > 1 {
+		return nil
+	}
+	return []string{"tag", "domain", "file", "none"}
+}
+
+func completeBackup(args []string) []string {
+	if len(args) > 1 {
+		return nil
+	}
+	return []string{"list", "restore"}
+}
+
+func (t *tui) commandConnect(args string) error {
+	if args == "" {
+		t.handleServerConnect()
+		return nil
+	}
+	servers, err := t.serverService.ListServers("")
+	if err != nil {
+		return err
+	}
+	var match *domain.Server
+	for i := range servers {
+		if servers[i].Alias == args {
+			match = &servers[i]
+			break
+		}
+		if match == nil && strings.EqualFold(servers[i].Alias, args) {
+			match = &servers[i]
+		}
+	}
+	if match == nil {
+		return fmt.Errorf("no server named %q", args)
+	}
+	server := *match
+	t.offerAgentKey(server, func() { t.connectServer(server) })
+	return nil
+}
+
+// commandTag adds the words starting with + (or no sign) and removes those starting with -.
+func (t *tui) commandTag(args string) error {
+	var add, remove []string
+	for _, word := range strings.Fields(args) {
+		switch {
+		case strings.HasPrefix(word, "-"):
+			remove = append(remove, splitTags(word[1:])...)
+		default:
+			add = append(add, splitTags(strings.TrimPrefix(word, "+"))...)
+		}
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		t.handleTagsEdit()
+		return nil
+	}
+	servers := t.selectedServers()
+	if len(servers) == 0 {
+		return fmt.Errorf("no server selected")
+	}
+
+	updates := make([]domain.ServerUpdate, 0, len(servers))
+	for _, server := range servers {
+		updated := server
+		updated.Tags = domain.EditTags(server.Tags, add, remove)
+		updates = append(updates, domain.ServerUpdate{Old: server, New: updated})
+	}
+	if err := t.serverService.UpdateServers(updates); err != nil {
+		return err
+	}
+	t.refreshServerList()
+	t.showStatusTemp(fmt.Sprintf("Updated tags on %d servers", len(servers)))
+	return nil
+}
+
+func (t *tui) commandSort(args string) error {
+	if strings.TrimSpace(args) == "" {
+		t.handleSortToggle()
+		return nil
+	}
+	order, err := domain.ParseSortOrder(args)
+	if err != nil {
+		return err
+	}
+	t.sortOrder = order
+	t.showStatusTemp("Sort: " + sortLabel(t.sortOrder))
+	t.updateListTitle()
+	t.refreshServerList()
+	return nil
+}
+
+// commandColumns shows the given columns in order; without arguments it restores the
+// columns of config.yaml.
+func (t *tui) commandColumns(args string) error {
+	columns := t.settings.List.Columns
+	if names := strings.Fields(strings.ReplaceAll(args, ",", " ")); len(names) > 0 {
+		var err error
+		if columns, err = domain.ParseListColumns(names); err != nil {
+			return err
+		}
+	}
+	t.serverList.SetColumns(columns)
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = string(column)
+	}
+	t.showStatusTemp("Columns: " + strings.Join(names, ", "))
+	return nil
+}
+
+// commandWidth caps the width of a column, or lets it fit its content again with auto.
+func (t *tui) commandWidth(args string) error {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		return fmt.Errorf("usage: width <column> <n|auto>")
+	}
+	column, err := domain.ParseListColumn(fields[0])
+	if err != nil {
+		return err
+	}
+	current := t.serverList.Widths()
+	widths := make(map[domain.ListColumn]int, len(current)+1)
+	for c, w := range current {
+		widths[c] = w
+	}
+	if strings.EqualFold(fields[1], "auto") {
+		delete(widths, column)
+	} else {
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid width %q (want a positive number or auto)", fields[1])
+		}
+		widths[column] = n
+	}
+	t.serverList.SetWidths(widths)
+	t.showStatusTemp(fmt.Sprintf("Width of %s: %s", column, fields[1]))
+	return nil
+}
+
+func (t *tui) commandView(args string) error {
+	if args == "" {
+		t.applyView(0)
+		return nil
+	}
+	if !t.applyViewByName(args) {
+		return fmt.Errorf("no saved view named %q", args)
+	}
+	return nil
+}
+
+func (t *tui) commandGroup(args string) error {
+	if args == "" {
+		t.handleGroupModeToggle()
+		return nil
+	}
+	mode := domain.GroupNone
+	for !strings.EqualFold(mode.String(), args) {
+		if mode = mode.Next(); mode == domain.GroupNone {
+			return fmt.Errorf("unknown grouping %q (want tag, domain, file or none)", args)
+		}
+	}
+	t.setGroupMode(mode)
+	return nil
+}
+
+func (t *tui) commandSearch(args string) error {
+	t.revealSearchBar()
+	t.searchBar.SetText(args)
+	if args == "" {
+		t.app.SetFocus(t.searchBar)
+	}
+	return nil
+}
+
+func (t *tui) commandExec(args string) error {
+	if args == "" {
+		t.handleRunCommand()
+		return nil
+	}
+	aliases := t.selectedAliases("")
+	if len(aliases) == 0 {
+		return fmt.Errorf("no server selected")
+	}
+	t.runCommand(aliases, args, domain.ExecOptions{Concurrency: 10, Timeout: 30 * time.Second})
+	return nil
+}
+
+func (t *tui) commandBackup(args string) error {
+	fields := strings.Fields(args)
+	backups, err := t.serverService.ListBackups()
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 || fields[0] == "list" {
+		t.showBackups(backups)
+		return nil
+	}
+	if fields[0] != "restore" || len(fields) != 2 {
+		return fmt.Errorf("usage: backup [list|restore <n>]")
+	}
+	n, err := strconv.Atoi(fields[1])
+	if err != nil || n < 1 || n > len(backups) {
+		return fmt.Errorf("no backup %s (there are %d; see :backup list)", fields[1], len(backups))
+	}
+	t.showRestoreConfirm(n, backups[n-1])
+	return nil
+}
+
+// showBackups lists the SSH config backups, numbered as :backup restore expects.
+func (t *tui) showBackups(backups []domain.ConfigBackup) {
+	var b strings.Builder
+	if len(backups) == 0 {
+		b.WriteString("No backups yet. dogssh backs up ~/.ssh/config before every change it makes.\n")
+	}
+	for i, backup := range backups {
+		fmt.Fprintf(&b, " ["+theme.Warning+"]%2d[-]  %s  ["+theme.Dim+"]%6.1f KB  %s[-]\n", i+1,
+			backup.CreatedAt.Format("2006-01-02 15:04:05"), float64(backup.Size)/1024, tview.Escape(filepath.Base(backup.Path)))
+	}
+
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(b.String())
+	text.SetBorder(true).
+		SetTitle(fmt.Sprintf(" SSH Config Backups (%d) ", len(backups))).
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	footer := tview.NewTextView().SetDynamicColors(true)
+	footer.SetBackgroundColor(theme.Bar)
+	footer.SetText(" [" + theme.Strong + "]:backup restore <n>[-] Restore  • [" + theme.Strong + "]Esc/q[-] Close")
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+			t.returnToMain()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+}
+
+func (t *tui) showRestoreConfirm(n int, backup domain.ConfigBackup) {
+	msg := fmt.Sprintf("Restore ~/.ssh/config from backup %d (%s)?\n\nThe current file is backed up first.",
+		n, backup.CreatedAt.Format("2006-01-02 15:04:05"))
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"Cancel", "Restore"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.handleModalClose()
+			if buttonIndex != 1 {
+				return
+			}
+			if err := t.serverService.RestoreBackup(backup.Path); err != nil {
+				t.showStatusTempColor(fmt.Sprintf("Restore failed: %v", err), theme.Error)
+				return
+			}
+			t.refreshServerList()
+			t.showStatusTemp(fmt.Sprintf("Restored backup %d", n))
+		})
+
+	t.app.SetRoot(modal, true)
+}
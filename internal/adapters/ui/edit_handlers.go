@@ -0,0 +1,231 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// handleRemoteEdit asks for a remote path on the selected server and edits it in $EDITOR.
+func (t *tui) handleRemoteEdit() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	alias := server.Alias
+
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle("Edit Remote File: " + alias).
+		SetTitleAlign(tview.AlignLeft)
+	form.AddInputField("Path:", t.lastEditPath, 60, nil, nil)
+
+	form.AddButton("Edit", func() {
+		p := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		if p == "" {
+			form.SetTitle("Edit Remote File: " + alias + " — [" + theme.Error + "::b]path is required[-]")
+			return
+		}
+		t.lastEditPath = p
+		t.returnToMain()
+		t.fetchRemoteFile(alias, p)
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// fetchRemoteFile downloads p into a private temp dir and opens it in the editor.
+func (t *tui) fetchRemoteFile(alias, p string) {
+	t.showStatusTemp(fmt.Sprintf("Fetching %s:%s…", alias, p))
+	go func() {
+		file, err := t.fileService.ReadFile(context.Background(), alias, p)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.logger.Errorw("remote edit: fetch failed", "alias", alias, "path", p, "error", err)
+				t.showStatusTempColor(fmt.Sprintf("Edit %s: %v", p, err), theme.Error)
+				return
+			}
+
+			dir, err := os.MkdirTemp("", "dogssh-edit-")
+			if err != nil {
+				t.showStatusTempColor(fmt.Sprintf("Edit %s: %v", p, err), theme.Error)
+				return
+			}
+			local := filepath.Join(dir, path.Base(p))
+			if err := os.WriteFile(local, file.Content, 0o600); err != nil {
+				_ = os.RemoveAll(dir)
+				t.showStatusTempColor(fmt.Sprintf("Edit %s: %v", p, err), theme.Error)
+				return
+			}
+			t.editRemoteFile(file, local)
+		})
+	}()
+}
+
+// editRemoteFile opens the local copy in $EDITOR while the TUI is suspended and shows the diff afterwards.
+func (t *tui) editRemoteFile(file domain.RemoteFile, local string) {
+	var editErr error
+	t.app.Suspend(func() {
+		editErr = runEditor(local)
+	})
+
+	cleanup := func() { _ = os.RemoveAll(filepath.Dir(local)) }
+	if editErr != nil {
+		cleanup()
+		t.showStatusTempColor(fmt.Sprintf("Editor: %v", editErr), theme.Error)
+		return
+	}
+
+	edited, err := os.ReadFile(local) //nolint:gosec // G304: our own temp file
+	if err != nil {
+		cleanup()
+		t.showStatusTempColor(fmt.Sprintf("Edit %s: %v", file.Path, err), theme.Error)
+		return
+	}
+	if bytes.Equal(edited, file.Content) {
+		cleanup()
+		t.showStatusTemp(fmt.Sprintf("No changes to %s", file.Path))
+		return
+	}
+
+	t.showEditDiff(file, local, edited)
+}
+
+// showEditDiff shows what changed and lets the user upload, edit again or discard.
+func (t *tui) showEditDiff(file domain.RemoteFile, local string, edited []byte) {
+	name := file.Alias + ":" + file.Path
+	diff := domain.UnifiedDiff(name, name+" (edited)", string(file.Content), string(edited))
+
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(colorizeDiff(diff))
+	text.SetBorder(true).
+		SetTitle(" Changes to " + tview.Escape(name) + " ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	footer := tview.NewTextView().SetDynamicColors(true)
+	footer.SetBackgroundColor(theme.Bar)
+	footer.SetText(fmt.Sprintf(" ["+theme.Strong+"]y[-] Upload (mode %04o)  • ["+theme.Strong+"]e[-] Edit again  • ["+theme.Strong+"]n/Esc[-] Discard", file.Mode.Perm()))
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	cleanup := func() { _ = os.RemoveAll(filepath.Dir(local)) }
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Rune() == 'y':
+			t.uploadEditedFile(file, local, edited)
+			return nil
+		case event.Rune() == 'e':
+			t.returnToMain()
+			t.editRemoteFile(file, local)
+			return nil
+		case event.Rune() == 'n' || event.Key() == tcell.KeyEsc:
+			cleanup()
+			t.returnToMain()
+			t.showStatusTemp(fmt.Sprintf("Discarded changes to %s", file.Path))
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+}
+
+// uploadEditedFile writes the edited copy back unless the remote file changed meanwhile.
+func (t *tui) uploadEditedFile(file domain.RemoteFile, local string, edited []byte) {
+	t.returnToMain()
+	t.showStatusTemp(fmt.Sprintf("Uploading %s…", file.Path))
+	go func() {
+		err := t.fileService.WriteFile(context.Background(), file, edited)
+		t.app.QueueUpdateDraw(func() {
+			switch {
+			case errors.Is(err, domain.ErrRemoteFileChanged):
+				// Keep the local copy so the edit is not lost.
+				t.logger.Warnw("remote edit: file changed on server", "alias", file.Alias, "path", file.Path, "local", local)
+				modal := tview.NewModal().
+					SetText(fmt.Sprintf("%s changed on %s while you were editing, so it was not overwritten.\n\nYour edited copy is kept at:\n%s", file.Path, file.Alias, local)).
+					AddButtons([]string{"OK"}).
+					SetDoneFunc(func(int, string) { t.handleModalClose() })
+				t.app.SetRoot(modal, true)
+			case err != nil:
+				t.logger.Errorw("remote edit: upload failed", "alias", file.Alias, "path", file.Path, "error", err)
+				t.showStatusTempColor(fmt.Sprintf("Upload %s: %v. Your copy: %s", file.Path, err, local), theme.Error)
+			default:
+				_ = os.RemoveAll(filepath.Dir(local))
+				t.showStatusTemp(fmt.Sprintf("Saved %s:%s", file.Alias, file.Path))
+			}
+		})
+	}()
+}
+
+// runEditor opens file in $VISUAL or $EDITOR, falling back to vi.
+func runEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], file)...) //nolint:gosec // G204: the user's own editor
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// colorizeDiff adds tview color tags to unified diff output.
+func colorizeDiff(diff string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		escaped := tview.Escape(line)
+		switch {
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
+			b.WriteString("[::b]" + escaped + "[-:-:-]")
+		case strings.HasPrefix(line, "@@"):
+			b.WriteString("[" + theme.Accent + "]" + escaped + "[-]")
+		case strings.HasPrefix(line, "+"):
+			b.WriteString("[" + theme.Success + "]" + escaped + "[-]")
+		case strings.HasPrefix(line, "-"):
+			b.WriteString("[" + theme.Error + "]" + escaped + "[-]")
+		default:
+			b.WriteString(escaped)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
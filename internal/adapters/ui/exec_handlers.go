@@ -0,0 +1,153 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+const (
+	runScopeSelected = iota
+	runScopeListed
+)
+
+func (t *tui) handleRunCommand() {
+	if len(t.serverList.Servers()) == 0 {
+		return
+	}
+	t.showRunForm()
+}
+
+// showRunForm asks for a command and the servers to run it on.
+func (t *tui) showRunForm() {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle("Run Command").
+		SetTitleAlign(tview.AlignLeft)
+
+	scopes := []string{t.selectedScopeLabel(), fmt.Sprintf("All listed servers (%d)", len(t.serverList.Servers()))}
+	form.AddInputField("Command:", "", 50, nil, nil)
+	form.AddDropDown("Run on:", scopes, runScopeSelected, nil)
+	form.AddInputField("Only tag (optional):", "", 20, nil, nil)
+	form.AddInputField("Concurrency:", "10", 6, nil, nil)
+	form.AddInputField("Timeout per host:", "30s", 8, nil, nil)
+
+	form.AddButton("Run", func() {
+		command := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		scope, _ := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		tag := strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText())
+		concurrency, errC := strconv.Atoi(strings.TrimSpace(form.GetFormItem(3).(*tview.InputField).GetText()))
+		timeout, errT := time.ParseDuration(strings.TrimSpace(form.GetFormItem(4).(*tview.InputField).GetText()))
+
+		var aliases []string
+		if scope == runScopeSelected {
+			aliases = t.selectedAliases(tag)
+		} else {
+			aliases = clusterAliases(t.serverList.Servers(), tag)
+		}
+
+		switch {
+		case command == "":
+			form.SetTitle("Run Command — [" + theme.Error + "::b]command is required[-]")
+		case errC != nil || concurrency < 1:
+			form.SetTitle("Run Command — [" + theme.Error + "::b]concurrency must be a positive number[-]")
+		case errT != nil || timeout <= 0:
+			form.SetTitle("Run Command — [" + theme.Error + "::b]timeout must be a duration like 30s[-]")
+		case len(aliases) == 0:
+			form.SetTitle("Run Command — [" + theme.Error + "::b]no matching servers[-]")
+		default:
+			t.runCommand(aliases, command, domain.ExecOptions{Concurrency: concurrency, Timeout: timeout})
+		}
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// runCommand runs command on aliases in the background and streams results into a results view.
+func (t *tui) runCommand(aliases []string, command string, opts domain.ExecOptions) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	view := NewExecResultsView(command, len(aliases))
+	view.OnClose(func() {
+		cancel()
+		t.returnToMain()
+	}).OnSave(func(results []domain.ExecResult) {
+		t.showSaveResultsForm(view, results)
+	})
+
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+
+	go func() {
+		t.serverService.RunCommand(ctx, aliases, command, opts, func(result domain.ExecResult) {
+			t.app.QueueUpdateDraw(func() {
+				view.AddResult(result)
+			})
+		})
+	}()
+}
+
+func (t *tui) showSaveResultsForm(view *ExecResultsView, results []domain.ExecResult) {
+	defaultPath := filepath.Join("~", ".dogssh", "runs", "run-"+time.Now().Format("20060102-150405")+".json")
+
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle("Save Results as JSON").
+		SetTitleAlign(tview.AlignLeft)
+	form.AddInputField("File:", defaultPath, 60, nil, nil)
+
+	back := func() {
+		t.app.SetRoot(view, true)
+		t.app.SetFocus(view)
+	}
+	form.AddButton("Save", func() {
+		path := expandHome(strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()))
+		if err := saveResultsJSON(path, results); err != nil {
+			form.SetTitle(fmt.Sprintf("Save Results as JSON — ["+theme.Error+"::b]%v[-]", err))
+			return
+		}
+		back()
+		t.showStatusTemp("Saved results to " + path)
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func saveResultsJSON(path string, results []domain.ExecResult) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal results: %w", err)
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,198 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ExecResultsView lists per-host results of a command run (failures first)
+// and shows the output of the highlighted host.
+type ExecResultsView struct {
+	*tview.Flex
+	command string
+	total   int
+	results []domain.ExecResult
+	table   *tview.Table
+	output  *tview.TextView
+	footer  *tview.TextView
+	onSave  func([]domain.ExecResult)
+	onClose func()
+}
+
+func NewExecResultsView(command string, total int) *ExecResultsView {
+	view := &ExecResultsView{
+		Flex:    tview.NewFlex(),
+		command: command,
+		total:   total,
+		table:   tview.NewTable(),
+		output:  tview.NewTextView(),
+		footer:  tview.NewTextView(),
+	}
+	view.build()
+	return view
+}
+
+func (v *ExecResultsView) build() {
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetTitle(" Run: " + tview.Escape(v.command) + " ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+	v.table.SetSelectionChangedFunc(func(row, column int) {
+		v.showOutput(row - 1)
+	})
+
+	v.output.SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetBorder(true).
+		SetTitle("Output").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+
+	body := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.table, 0, 2, true).
+		AddItem(v.output, 0, 3, false)
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.Flex.SetInputCapture(v.handleKeys)
+	v.render()
+}
+
+func (v *ExecResultsView) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+		if v.onClose != nil {
+			v.onClose()
+		}
+		return nil
+	case event.Rune() == 's':
+		if v.onSave != nil && len(v.results) == v.total {
+			v.onSave(v.results)
+		}
+		return nil
+	case event.Key() == tcell.KeyPgDn || event.Key() == tcell.KeyPgUp:
+		// Page keys scroll the output while the table keeps the selection.
+		step := 10
+		if event.Key() == tcell.KeyPgUp {
+			step = -10
+		}
+		row, col := v.output.GetScrollOffset()
+		v.output.ScrollTo(max(row+step, 0), col)
+		return nil
+	}
+	return event
+}
+
+// AddResult records a finished host and keeps failures at the top.
+func (v *ExecResultsView) AddResult(result domain.ExecResult) {
+	selected := ""
+	if row, _ := v.table.GetSelection(); row > 0 && row-1 < len(v.results) {
+		selected = v.results[row-1].Alias
+	}
+
+	v.results = append(v.results, result)
+	domain.SortExecResults(v.results)
+	v.render()
+
+	for i, r := range v.results {
+		if r.Alias == selected {
+			v.table.Select(i+1, 0)
+			break
+		}
+	}
+}
+
+func (v *ExecResultsView) render() {
+	v.table.Clear()
+	for col, header := range []string{"Host", "Status", "Exit", "Time"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	failed := 0
+	for i, r := range v.results {
+		status, color := "OK", theme.color(theme.Success)
+		if r.Failed() {
+			failed++
+			status, color = "FAIL", theme.color(theme.Error)
+		}
+		row := i + 1
+		v.table.SetCell(row, 0, tview.NewTableCell(r.Alias).SetExpansion(1))
+		v.table.SetCell(row, 1, tview.NewTableCell(status).SetTextColor(color))
+		v.table.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", r.ExitCode)).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 3, tview.NewTableCell(r.Duration.Round(time.Millisecond).String()).SetAlign(tview.AlignRight))
+	}
+
+	progress := fmt.Sprintf("%d/%d done", len(v.results), v.total)
+	if len(v.results) < v.total {
+		progress = "[" + theme.Warning + "]" + progress + "…[-]"
+	}
+	v.footer.SetText(fmt.Sprintf(" %s  •  ["+theme.Success+"]%d ok[-]  ["+theme.Error+"]%d failed[-]  •  ["+theme.Strong+"]PgUp/PgDn[-] Scroll output  • ["+theme.Strong+"]s[-] Save JSON  • ["+theme.Strong+"]Esc[-] Back",
+		progress, len(v.results)-failed, failed))
+
+	if len(v.results) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(max(row, 1), 0)
+	}
+}
+
+func (v *ExecResultsView) showOutput(idx int) {
+	if idx < 0 || idx >= len(v.results) {
+		v.output.SetText("")
+		return
+	}
+	r := v.results[idx]
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%s[-:-:-]  exit %d  %s\n", r.Alias, r.ExitCode, r.Duration.Round(time.Millisecond))
+	if r.Error != "" {
+		fmt.Fprintf(&b, "["+theme.Error+"]%s[-]\n", tview.Escape(r.Error))
+	}
+	b.WriteString("\n")
+	b.WriteString(tview.Escape(r.Stdout))
+	if r.Stderr != "" {
+		b.WriteString("[" + theme.Error + "]")
+		b.WriteString(tview.Escape(r.Stderr))
+		b.WriteString("[-]")
+	}
+	v.output.SetText(b.String()).ScrollToBeginning()
+	v.output.SetTitle("Output: " + r.Alias)
+}
+
+func (v *ExecResultsView) OnSave(fn func([]domain.ExecResult)) *ExecResultsView {
+	v.onSave = fn
+	return v
+}
+
+func (v *ExecResultsView) OnClose(fn func()) *ExecResultsView {
+	v.onClose = fn
+	return v
+}
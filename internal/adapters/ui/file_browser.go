@@ -0,0 +1,367 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// FileSide tells which pane of the file browser an action comes from.
+type FileSide int
+
+const (
+	FileSideLocal FileSide = iota
+	FileSideRemote
+)
+
+// FilePane lists one directory of the file browser. The first row is the parent directory.
+type FilePane struct {
+	*tview.Table
+	side    FileSide
+	title   string
+	dir     string
+	entries []domain.FileEntry
+	parent  func(dir string) string
+}
+
+func NewFilePane(side FileSide, title string, parent func(dir string) string) *FilePane {
+	pane := &FilePane{
+		Table:  tview.NewTable(),
+		side:   side,
+		title:  title,
+		parent: parent,
+	}
+	pane.SetSelectable(true, false).
+		SetBorder(true).
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title).
+		SetTitleAlign(tview.AlignLeft)
+	return pane
+}
+
+// SetListing shows entries of dir and selects the entry named selected, if present.
+func (p *FilePane) SetListing(dir string, entries []domain.FileEntry, selected string) {
+	p.dir = dir
+	p.entries = entries
+	p.SetTitle(fmt.Sprintf(" %s: %s ", p.title, tview.Escape(dir)))
+
+	p.Clear()
+	p.SetCell(0, 0, tview.NewTableCell("..").SetTextColor(theme.color(theme.Accent)).SetExpansion(1))
+	row := 0
+	for i, e := range entries {
+		name, color := e.Name, theme.Text
+		switch {
+		case e.IsDir:
+			name, color = e.Name+"/", theme.color(theme.Accent)
+		case e.IsLink:
+			color = theme.color(theme.Success)
+		}
+		if e.IsLink {
+			name += " →"
+		}
+		size := formatBytes(e.Size)
+		if e.IsDir {
+			size = ""
+		}
+		modified := ""
+		if !e.ModTime.IsZero() {
+			modified = e.ModTime.Format("2006-01-02 15:04")
+		}
+
+		p.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(name)).SetTextColor(color).SetExpansion(1))
+		p.SetCell(i+1, 1, tview.NewTableCell(size).SetAlign(tview.AlignRight).SetTextColor(theme.Muted))
+		p.SetCell(i+1, 2, tview.NewTableCell(modified).SetTextColor(theme.Muted))
+		if e.Name == selected {
+			row = i + 1
+		}
+	}
+	p.ScrollToBeginning()
+	p.Select(row, 0)
+}
+
+// Dir returns the directory shown in the pane.
+func (p *FilePane) Dir() string {
+	return p.dir
+}
+
+// Side returns whether this is the local or remote pane.
+func (p *FilePane) Side() FileSide {
+	return p.side
+}
+
+// Selected returns the highlighted entry. The parent row is reported as a directory named "..".
+func (p *FilePane) Selected() (domain.FileEntry, bool) {
+	row, _ := p.GetSelection()
+	if row == 0 {
+		return domain.FileEntry{Name: "..", Path: p.parent(p.dir), IsDir: true}, true
+	}
+	if row < 1 || row > len(p.entries) {
+		return domain.FileEntry{}, false
+	}
+	return p.entries[row-1], true
+}
+
+func (p *FilePane) setActive(active bool) {
+	color := theme.Border
+	style := tcell.StyleDefault.Background(theme.Border)
+	if active {
+		color = theme.color(theme.Accent)
+		style = theme.SelectedStyle()
+	}
+	p.SetBorderColor(color)
+	p.SetSelectedStyle(style)
+}
+
+// FileBrowser shows a local and a remote pane side by side.
+// Tab switches panes; the other keys act on the highlighted entry of the active pane.
+type FileBrowser struct {
+	*tview.Flex
+	local    *FilePane
+	remote   *FilePane
+	active   *FilePane
+	status   *tview.TextView
+	footer   *tview.TextView
+	busy     bool
+	onOpen   func(*FilePane, domain.FileEntry)
+	onCopy   func(*FilePane, domain.FileEntry)
+	onRename func(*FilePane, domain.FileEntry)
+	onDelete func(*FilePane, domain.FileEntry)
+	onMkdir  func(*FilePane)
+	onReload func()
+	onCancel func()
+	onClose  func()
+}
+
+func NewFileBrowser(local, remote *FilePane) *FileBrowser {
+	browser := &FileBrowser{
+		Flex:   tview.NewFlex(),
+		local:  local,
+		remote: remote,
+		active: local,
+		status: tview.NewTextView(),
+		footer: tview.NewTextView(),
+	}
+	browser.build()
+	return browser
+}
+
+func (b *FileBrowser) build() {
+	b.status.SetDynamicColors(true)
+	b.footer.SetDynamicColors(true)
+	b.footer.SetBackgroundColor(theme.Bar)
+	b.footer.SetText(" [" + theme.Strong + "]Tab[-] Switch pane  • [" + theme.Strong + "]Enter[-] Open  • [" + theme.Strong + "]Backspace[-] Up  • [" + theme.Strong + "]c[-] Copy →  • [" + theme.Strong + "]r[-] Rename  • [" + theme.Strong + "]d[-] Delete  • [" + theme.Strong + "]m[-] Mkdir  • [" + theme.Strong + "]R[-] Reload  • [" + theme.Strong + "]Esc[-] Back/Cancel")
+
+	panes := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(b.local, 0, 1, true).
+		AddItem(b.remote, 0, 1, false)
+
+	b.Flex.SetDirection(tview.FlexRow).
+		AddItem(panes, 0, 1, true).
+		AddItem(b.status, 1, 0, false).
+		AddItem(b.footer, 1, 0, false)
+
+	b.local.setActive(true)
+	b.remote.setActive(false)
+}
+
+// Focus always hands focus to the active pane.
+func (b *FileBrowser) Focus(delegate func(p tview.Primitive)) {
+	delegate(b.active)
+}
+
+func (b *FileBrowser) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return b.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab {
+			b.setActive(b.Other(b.active))
+			setFocus(b.active)
+			return
+		}
+		if b.handleKeys(event) == nil {
+			return
+		}
+		if handler := b.active.InputHandler(); handler != nil {
+			handler(event, setFocus)
+		}
+	})
+}
+
+// MouseHandler keeps the active pane in sync with clicks.
+func (b *FileBrowser) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+		if action == tview.MouseLeftDown {
+			x, y := event.Position()
+			for _, pane := range []*FilePane{b.local, b.remote} {
+				if pane.InRect(x, y) {
+					b.setActive(pane)
+				}
+			}
+		}
+		return b.Flex.MouseHandler()(action, event, setFocus)
+	}
+}
+
+func (b *FileBrowser) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+		if b.busy {
+			if b.onCancel != nil {
+				b.onCancel()
+			}
+		} else if b.onClose != nil {
+			b.onClose()
+		}
+		return nil
+	}
+	if b.busy {
+		// Only navigation is allowed while a transfer runs.
+		return event
+	}
+
+	entry, ok := b.active.Selected()
+	switch {
+	case event.Key() == tcell.KeyEnter:
+		if ok && entry.IsDir && b.onOpen != nil {
+			b.onOpen(b.active, entry)
+		}
+		return nil
+	case event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2 || event.Key() == tcell.KeyLeft:
+		if b.onOpen != nil {
+			b.onOpen(b.active, domain.FileEntry{Name: "..", Path: b.active.parent(b.active.Dir()), IsDir: true})
+		}
+		return nil
+	case event.Rune() == 'c' || event.Key() == tcell.KeyF5:
+		if ok && entry.Name != ".." && b.onCopy != nil {
+			b.onCopy(b.active, entry)
+		}
+		return nil
+	case event.Rune() == 'r' || event.Key() == tcell.KeyF6:
+		if ok && entry.Name != ".." && b.onRename != nil {
+			b.onRename(b.active, entry)
+		}
+		return nil
+	case event.Rune() == 'd' || event.Key() == tcell.KeyF8 || event.Key() == tcell.KeyDelete:
+		if ok && entry.Name != ".." && b.onDelete != nil {
+			b.onDelete(b.active, entry)
+		}
+		return nil
+	case event.Rune() == 'm' || event.Key() == tcell.KeyF7:
+		if b.onMkdir != nil {
+			b.onMkdir(b.active)
+		}
+		return nil
+	case event.Rune() == 'R':
+		if b.onReload != nil {
+			b.onReload()
+		}
+		return nil
+	}
+	return event
+}
+
+func (b *FileBrowser) setActive(pane *FilePane) {
+	b.active = pane
+	b.local.setActive(pane == b.local)
+	b.remote.setActive(pane == b.remote)
+}
+
+// Other returns the pane opposite to pane.
+func (b *FileBrowser) Other(pane *FilePane) *FilePane {
+	if pane == b.local {
+		return b.remote
+	}
+	return b.local
+}
+
+// SetStatus shows a message below the panes.
+func (b *FileBrowser) SetStatus(text string) {
+	b.status.SetText(" " + text)
+}
+
+// SetBusy blocks file operations while a transfer runs; Esc then cancels it.
+func (b *FileBrowser) SetBusy(busy bool) {
+	b.busy = busy
+}
+
+// SetProgress renders a progress bar for the running transfer.
+func (b *FileBrowser) SetProgress(verb string, p domain.TransferProgress) {
+	pct := p.Percent()
+	if pct < 0 {
+		b.SetStatus(fmt.Sprintf("["+theme.Warning+"]%s %s…[-]  %s", verb, tview.Escape(p.File), formatBytes(p.Done)))
+		return
+	}
+	const width = 30
+	filled := pct * width / 100
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+	b.SetStatus(fmt.Sprintf("["+theme.Warning+"]%s %s[-]  %s %3d%%  %s / %s  ["+theme.Dim+"](Esc to cancel)[-]",
+		verb, tview.Escape(p.File), bar, pct, formatBytes(p.Done), formatBytes(p.Total)))
+}
+
+func (b *FileBrowser) OnOpen(fn func(*FilePane, domain.FileEntry)) *FileBrowser {
+	b.onOpen = fn
+	return b
+}
+
+func (b *FileBrowser) OnCopy(fn func(*FilePane, domain.FileEntry)) *FileBrowser {
+	b.onCopy = fn
+	return b
+}
+
+func (b *FileBrowser) OnRename(fn func(*FilePane, domain.FileEntry)) *FileBrowser {
+	b.onRename = fn
+	return b
+}
+
+func (b *FileBrowser) OnDelete(fn func(*FilePane, domain.FileEntry)) *FileBrowser {
+	b.onDelete = fn
+	return b
+}
+
+func (b *FileBrowser) OnMkdir(fn func(*FilePane)) *FileBrowser {
+	b.onMkdir = fn
+	return b
+}
+
+func (b *FileBrowser) OnReload(fn func()) *FileBrowser {
+	b.onReload = fn
+	return b
+}
+
+func (b *FileBrowser) OnCancel(fn func()) *FileBrowser {
+	b.onCancel = fn
+	return b
+}
+
+func (b *FileBrowser) OnClose(fn func()) *FileBrowser {
+	b.onClose = fn
+	return b
+}
+
+// formatBytes renders a size like "1.5 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
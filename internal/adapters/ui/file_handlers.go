@@ -0,0 +1,247 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+// handleFileBrowser opens the dual-pane file browser for the selected server.
+func (t *tui) handleFileBrowser() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	alias := server.Alias
+	t.showStatusTemp(fmt.Sprintf("Connecting to %s…", alias))
+
+	go func() {
+		home, err := t.fileService.Home(alias)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.logger.Errorw("file browser: failed to open", "alias", alias, "error", err)
+				t.showStatusTempColor(fmt.Sprintf("Browse %s: %v", alias, err), theme.Error)
+				return
+			}
+			t.showFileBrowser(alias, home)
+		})
+	}()
+}
+
+func (t *tui) showFileBrowser(alias, remoteHome string) {
+	localDir, err := os.UserHomeDir()
+	if err != nil {
+		localDir = "."
+	}
+
+	local := NewFilePane(FileSideLocal, "Local", filepath.Dir)
+	remote := NewFilePane(FileSideRemote, alias, path.Dir)
+	browser := NewFileBrowser(local, remote)
+
+	var cancel context.CancelFunc
+	back := func() {
+		t.app.SetRoot(browser, true)
+		t.app.SetFocus(browser)
+	}
+
+	browser.OnOpen(func(pane *FilePane, entry domain.FileEntry) {
+		// Going up keeps the directory we came from highlighted.
+		selected := ""
+		if entry.Name == ".." {
+			selected = filepath.Base(pane.Dir())
+			if pane.Side() == FileSideRemote {
+				selected = path.Base(pane.Dir())
+			}
+		}
+		t.loadFilePane(alias, browser, pane, entry.Path, selected)
+	}).OnReload(func() {
+		t.loadFilePane(alias, browser, local, local.Dir(), "")
+		t.loadFilePane(alias, browser, remote, remote.Dir(), "")
+	}).OnCopy(func(pane *FilePane, entry domain.FileEntry) {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		t.copyFile(ctx, alias, browser, pane, entry)
+	}).OnCancel(func() {
+		if cancel != nil {
+			cancel()
+		}
+	}).OnRename(func(pane *FilePane, entry domain.FileEntry) {
+		t.showFileNameForm(browser, "Rename "+entry.Name, entry.Name, back, func(name string) error {
+			if pane.Side() == FileSideLocal {
+				return os.Rename(entry.Path, filepath.Join(pane.Dir(), name))
+			}
+			return t.fileService.Rename(alias, entry.Path, path.Join(pane.Dir(), name))
+		}, func(name string) {
+			t.loadFilePane(alias, browser, pane, pane.Dir(), name)
+		})
+	}).OnMkdir(func(pane *FilePane) {
+		t.showFileNameForm(browser, "New directory", "", back, func(name string) error {
+			if pane.Side() == FileSideLocal {
+				return os.Mkdir(filepath.Join(pane.Dir(), name), 0o750)
+			}
+			return t.fileService.Mkdir(alias, path.Join(pane.Dir(), name))
+		}, func(name string) {
+			t.loadFilePane(alias, browser, pane, pane.Dir(), name)
+		})
+	}).OnDelete(func(pane *FilePane, entry domain.FileEntry) {
+		t.showFileDeleteModal(alias, browser, pane, entry, back)
+	}).OnClose(func() {
+		t.returnToMain()
+		t.app.SetFocus(t.serverList)
+	})
+
+	t.loadFilePane(alias, browser, local, localDir, "")
+	remote.SetListing(remoteHome, nil, "")
+	t.loadFilePane(alias, browser, remote, remoteHome, "")
+
+	t.app.SetRoot(browser, true)
+	t.app.SetFocus(browser)
+}
+
+// loadFilePane lists dir into pane, in the background for the remote side.
+func (t *tui) loadFilePane(alias string, browser *FileBrowser, pane *FilePane, dir, selected string) {
+	if pane.Side() == FileSideLocal {
+		entries, err := t.fileService.ListLocal(dir)
+		if err != nil {
+			browser.SetStatus(fmt.Sprintf("["+theme.Error+"]%v[-]", err))
+			return
+		}
+		pane.SetListing(dir, entries, selected)
+		return
+	}
+
+	browser.SetStatus(fmt.Sprintf("["+theme.Dim+"]Listing %s…[-]", tview.Escape(dir)))
+	go func() {
+		entries, err := t.fileService.List(alias, dir)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				browser.SetStatus(fmt.Sprintf("["+theme.Error+"]%s[-]", tview.Escape(err.Error())))
+				return
+			}
+			browser.SetStatus("")
+			pane.SetListing(dir, entries, selected)
+		})
+	}()
+}
+
+// copyFile uploads or downloads entry into the directory shown in the other pane.
+func (t *tui) copyFile(ctx context.Context, alias string, browser *FileBrowser, pane *FilePane, entry domain.FileEntry) {
+	target := browser.Other(pane)
+	targetDir := target.Dir()
+	verb := "Uploading"
+	if pane.Side() == FileSideRemote {
+		verb = "Downloading"
+	}
+
+	browser.SetBusy(true)
+	browser.SetProgress(verb, domain.TransferProgress{File: entry.Name})
+
+	go func() {
+		progress := func(p domain.TransferProgress) {
+			t.app.QueueUpdateDraw(func() { browser.SetProgress(verb, p) })
+		}
+		var err error
+		if pane.Side() == FileSideLocal {
+			err = t.fileService.Upload(ctx, alias, entry.Path, targetDir, progress)
+		} else {
+			err = t.fileService.Download(ctx, alias, entry.Path, targetDir, progress)
+		}
+
+		t.app.QueueUpdateDraw(func() {
+			browser.SetBusy(false)
+			switch {
+			case ctx.Err() != nil:
+				browser.SetStatus("[" + theme.Warning + "]Transfer cancelled[-]")
+			case err != nil:
+				t.logger.Errorw("file transfer failed", "alias", alias, "path", entry.Path, "error", err)
+				browser.SetStatus(fmt.Sprintf("["+theme.Error+"]%s[-]", tview.Escape(err.Error())))
+			default:
+				t.loadFilePane(alias, browser, target, targetDir, entry.Name)
+				browser.SetStatus(fmt.Sprintf("["+theme.Success+"]Copied %s to %s[-]", tview.Escape(entry.Name), tview.Escape(targetDir)))
+			}
+		})
+	}()
+}
+
+// showFileNameForm asks for a file name, runs apply and calls done on success.
+func (t *tui) showFileNameForm(browser *FileBrowser, title, value string, back func(), apply func(name string) error, done func(name string)) {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	form.AddInputField("Name:", value, 40, nil, nil)
+
+	form.AddButton("OK", func() {
+		name := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			form.SetTitle(title + " — [" + theme.Error + "::b]invalid name[-]")
+			return
+		}
+		if err := apply(name); err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%s[-]", title, tview.Escape(err.Error())))
+			return
+		}
+		back()
+		done(name)
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) showFileDeleteModal(alias string, browser *FileBrowser, pane *FilePane, entry domain.FileEntry, back func()) {
+	kind := "file"
+	if entry.IsDir && !entry.IsLink {
+		kind = "directory and everything in it"
+	}
+	where := "local"
+	if pane.Side() == FileSideRemote {
+		where = alias
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete %s %s on %s?\n\nThis action cannot be undone.", kind, entry.Path, where)).
+		AddButtons([]string{"Cancel", "Confirm"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			back()
+			if buttonIndex != 1 {
+				return
+			}
+			var err error
+			if pane.Side() == FileSideLocal {
+				err = os.RemoveAll(entry.Path)
+			} else {
+				err = t.fileService.Remove(alias, entry.Path)
+			}
+			if err != nil {
+				browser.SetStatus(fmt.Sprintf("["+theme.Error+"]%s[-]", tview.Escape(err.Error())))
+				return
+			}
+			t.loadFilePane(alias, browser, pane, pane.Dir(), "")
+			browser.SetStatus(fmt.Sprintf("["+theme.Success+"]Deleted %s[-]", tview.Escape(entry.Name)))
+		})
+
+	t.app.SetRoot(modal, true)
+}
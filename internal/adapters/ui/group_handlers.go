@@ -0,0 +1,168 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+)
+
+// groupPingConcurrency bounds the pings running at once when a whole group is pinged.
+const groupPingConcurrency = 16
+
+// handleGroupCollapse collapses or expands the group of the selected row in the tree view.
+func (t *tui) handleGroupCollapse(collapsed bool) {
+	if t.serverList.GroupMode() != domain.GroupNone && t.app.GetFocus() == t.serverList {
+		t.serverList.SetCollapsed(collapsed)
+	}
+}
+
+// handleGroupModeToggle cycles the server list through the tree views and back to the flat list.
+func (t *tui) handleGroupModeToggle() {
+	t.setGroupMode(t.serverList.GroupMode().Next())
+}
+
+func (t *tui) setGroupMode(mode domain.GroupMode) {
+	t.serverList.SetGroupMode(mode)
+	if mode == domain.GroupNone {
+		t.showStatusTemp("Flat list")
+	} else {
+		t.showStatusTemp("Group by: " + mode.String())
+	}
+	t.updateListTitle()
+}
+
+func (t *tui) handleGroupSelectionChange(group domain.ServerGroup) {
+	t.details.UpdateGroup(group, t.serverList.GroupMode(), t.reachabilityOf)
+}
+
+// reachabilityOf returns the last ping result recorded for alias.
+func (t *tui) reachabilityOf(alias string) (domain.Reachability, bool) {
+	r, ok := t.reachability[alias]
+	return r, ok
+}
+
+// recordReachability stores a ping result; it must run on the UI goroutine.
+func (t *tui) recordReachability(alias string, up bool, latency time.Duration) {
+	t.reachability[alias] = domain.Reachability{Up: up, Latency: latency, CheckedAt: time.Now()}
+}
+
+// resortByReachability re-sorts the list after pings when its order depends on their
+// results, keeping the cursor on the same server or row.
+func (t *tui) resortByReachability() {
+	if !sortsByReachability(t.sortOrder) {
+		return
+	}
+	current := t.serverList.GetCurrentItem()
+	server, selected := t.serverList.GetSelectedServer()
+	t.refreshServerList()
+	if selected && t.serverList.SelectServer(server.Alias) {
+		return
+	}
+	if current >= 0 && current < t.serverList.GetItemCount() {
+		t.serverList.SetCurrentItem(current)
+	}
+}
+
+// handleGroupPing pings every server of group and summarizes the results in its header.
+func (t *tui) handleGroupPing(group domain.ServerGroup) {
+	t.showStatusTemp(fmt.Sprintf("Pinging %d servers in %s…", len(group.Servers), group.Name))
+	go func() {
+		var (
+			mu       sync.Mutex
+			wg       sync.WaitGroup
+			up, down int
+		)
+		sem := make(chan struct{}, groupPingConcurrency)
+		for _, server := range group.Servers {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(server domain.Server) {
+				defer func() { <-sem; wg.Done() }()
+				ok, latency, err := t.serverService.Ping(server)
+				ok = ok && err == nil
+				mu.Lock()
+				if ok {
+					up++
+				} else {
+					down++
+				}
+				mu.Unlock()
+				t.app.QueueUpdateDraw(func() {
+					t.recordReachability(server.Alias, ok, latency)
+					t.serverList.Redraw()
+				})
+			}(server)
+		}
+		wg.Wait()
+		t.app.QueueUpdateDraw(func() {
+			t.resortByReachability()
+			if selected, ok := t.serverList.GetSelectedGroup(); ok && selected.Name == group.Name {
+				t.handleGroupSelectionChange(selected)
+			}
+			color := theme.Success
+			if down > 0 {
+				color = theme.Error
+			}
+			t.showStatusTempColor(fmt.Sprintf("Ping %s: %d up, %d down", group.Name, up, down), color)
+		})
+	}()
+}
+
+// handleGroupCluster opens synchronized tmux panes on every server of group.
+func (t *tui) handleGroupCluster(group domain.ServerGroup) {
+	aliases := clusterAliases(group.Servers, "")
+	if err := t.tmuxService.OpenCluster("cssh:"+group.Name, aliases); err != nil {
+		t.logger.Errorw("tmux cluster open failed", "group", group.Name, "error", err)
+		t.showStatusTempColor(fmt.Sprintf("tmux: %v", err), theme.Error)
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Opened %d synchronized panes", len(aliases)))
+}
+
+// selectedScopeLabel names the "selected" scope of the batch forms: the marked servers,
+// the server under the cursor or, in the tree view, the group whose header is under it.
+func (t *tui) selectedScopeLabel() string {
+	if marked := t.serverList.Marked(); len(marked) > 0 {
+		return fmt.Sprintf("Marked servers (%d)", len(marked))
+	}
+	if group, ok := t.serverList.GetSelectedGroup(); ok {
+		return fmt.Sprintf("Group %s (%d)", group.Name, len(group.Servers))
+	}
+	return "Selected server"
+}
+
+// selectedAliases returns the aliases of the marked servers, or of the selected server
+// or group, that carry tag.
+func (t *tui) selectedAliases(tag string) []string {
+	return clusterAliases(t.selectedServers(), tag)
+}
+
+// selectedServers returns the marked servers, or else the server or group under the cursor.
+func (t *tui) selectedServers() []domain.Server {
+	if servers := t.markedServers(); len(servers) > 0 {
+		return servers
+	}
+	if group, ok := t.serverList.GetSelectedGroup(); ok {
+		return group.Servers
+	}
+	if server, ok := t.serverList.GetSelectedServer(); ok {
+		return []domain.Server{server}
+	}
+	return nil
+}
@@ -16,9 +16,11 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/ChengzeHsiao/dogssh/internal/adapters/terminal"
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
 	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
@@ -34,65 +36,40 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	if t.app.GetFocus() == t.searchBar {
 		return event
 	}
-
-	switch event.Rune() {
-	case 'q':
-		t.handleQuit()
-		return nil
-	case '/':
-		t.handleSearchToggle()
-		return nil
-	case 'a':
-		t.handleServerAdd()
-		return nil
-	case 'e':
-		t.handleServerEdit()
-		return nil
-	case 'd':
-		t.handleServerDelete()
-		return nil
-	case 'p':
-		t.handleServerPin()
-		return nil
-	case 's':
-		t.handleSortToggle()
-		return nil
-	case 'S':
-		t.handleSortReverse()
-		return nil
-	case 'c':
-		t.handleCopyCommand()
-		return nil
-	case 'g':
-		t.handlePingSelected()
-		return nil
-	case 'r':
-		t.handleRefreshBackground()
-		return nil
-	case 't':
-		t.handleTagsEdit()
-		return nil
-	case 'j':
-		t.handleNavigateDown()
-		return nil
-	case 'k':
-		t.handleNavigateUp()
-		return nil
+	// The views sidebar and the command line handle their own keys
+	if focus := t.app.GetFocus(); focus == t.viewsPanel || focus == t.commandBar {
+		return event
 	}
 
-	if event.Key() == tcell.KeyEnter {
-		t.handleServerConnect()
+	if a, ok := t.keyActions[eventKeyName(event)]; ok {
+		a.run()
 		return nil
 	}
+	return event
+}
 
+// handleAppKeys sees every key before tview, which quits on Ctrl+C. In an embedded
+// session Ctrl+C belongs to the remote shell, so it is passed on as a new event that
+// tview does not treat as the quit key.
+func (t *tui) handleAppKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyCtrlC {
+		if _, ok := t.app.GetFocus().(*TerminalView); ok {
+			return tcell.NewEventKey(event.Key(), event.Rune(), event.Modifiers())
+		}
+	}
 	return event
 }
 
 func (t *tui) handleQuit() {
+	// Run cleans up once the event loop has stopped.
 	t.app.Stop()
 }
 
 func (t *tui) handleServerPin() {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.handleBatchPin(servers)
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		pinned := server.PinnedAt.IsZero()
 		_ = t.serverService.SetPinned(server.Alias, pinned)
@@ -101,20 +78,42 @@ func (t *tui) handleServerPin() {
 }
 
 func (t *tui) handleSortToggle() {
-	t.sortMode = t.sortMode.ToggleField()
-	t.showStatusTemp("Sort: " + t.sortMode.String())
+	t.sortOrder = t.sortOrder.NextField()
+	t.showStatusTemp("Sort: " + sortLabel(t.sortOrder))
 	t.updateListTitle()
 	t.refreshServerList()
 }
 
 func (t *tui) handleSortReverse() {
-	t.sortMode = t.sortMode.Reverse()
-	t.showStatusTemp("Sort: " + t.sortMode.String())
+	t.sortOrder = t.sortOrder.Reversed()
+	t.showStatusTemp("Sort: " + sortLabel(t.sortOrder))
+	t.updateListTitle()
+	t.refreshServerList()
+}
+
+// handleColumnSort sorts by a clicked column header, reversing the order when the list
+// is already sorted by it.
+func (t *tui) handleColumnSort(column domain.ListColumn) {
+	field, ok := column.SortField()
+	if !ok {
+		t.showStatusTemp("The list cannot be sorted by " + columnSpecs[column].title)
+		return
+	}
+	if t.sortOrder.Field == field {
+		t.sortOrder = t.sortOrder.Reversed()
+	} else {
+		t.sortOrder = domain.DefaultSortOrder(field)
+	}
+	t.showStatusTemp("Sort: " + sortLabel(t.sortOrder))
 	t.updateListTitle()
 	t.refreshServerList()
 }
 
 func (t *tui) handleCopyCommand() {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.handleBatchCopy(servers)
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		cmd := BuildSSHCommand(server)
 		if err := clipboard.WriteAll(cmd); err == nil {
@@ -126,6 +125,10 @@ func (t *tui) handleCopyCommand() {
 }
 
 func (t *tui) handleTagsEdit() {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.showBatchTagsForm(servers)
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		t.showEditTagsForm(server)
 	}
@@ -154,11 +157,38 @@ func (t *tui) handleNavigateUp() {
 	}
 }
 
+// handleNavigatePage moves the selection by one screen of entries in direction (1 or -1).
+func (t *tui) handleNavigatePage(direction int) {
+	_, _, _, height := t.serverList.GetInnerRect()
+	t.handleNavigateTo(t.serverList.GetCurrentItem() + direction*max(height, 1))
+}
+
+// handleNavigateTo selects the entry at index, clamped to the list.
+func (t *tui) handleNavigateTo(index int) {
+	if t.app.GetFocus() != t.serverList || t.serverList.GetItemCount() == 0 {
+		return
+	}
+	index = min(max(index, 0), t.serverList.GetItemCount()-1)
+	t.serverList.SetCurrentItem(index)
+}
+
 func (t *tui) handleSearchInput(query string) {
-	filtered, _ := t.serverService.ListServers(query)
-	sortServersForUI(filtered, t.sortMode)
-	t.serverList.UpdateServers(filtered)
-	if len(filtered) == 0 {
+	// Editing the query of a saved view turns it into an ad-hoc search
+	if t.activeView.Name != "" && query != t.activeView.Query {
+		t.activeView = domain.SavedView{}
+		t.viewsPanel.SetActive("")
+		t.updateListTitle()
+	}
+	matches, err := t.serverService.SearchServers(query)
+	if err != nil {
+		// Keep the last results while the query is incomplete or invalid.
+		t.searchBar.SetError(err.Error())
+		return
+	}
+	t.searchBar.SetError("")
+	domain.SortMatches(matches, t.sortOrder, t.reachability)
+	t.serverList.UpdateMatches(matches)
+	if len(matches) == 0 {
 		t.details.ShowEmpty()
 	}
 }
@@ -168,32 +198,155 @@ func (t *tui) handleSearchToggle() {
 }
 
 func (t *tui) handleServerConnect() {
+	if _, ok := t.serverList.GetSelectedGroup(); ok {
+		t.serverList.ToggleCollapsed()
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
-		// Store the current server for post-SSH operations
-		alias := server.Alias
+		t.offerAgentKey(server, func() { t.connectServer(server) })
+	}
+}
 
-		// Suspend the TUI and execute SSH
-		t.app.Suspend(func() {
-			_ = t.serverService.SSH(alias)
-		})
+// connectServer opens an SSH session to server.
+func (t *tui) connectServer(server domain.Server) {
+	// Store the current server for post-SSH operations
+	alias := server.Alias
+
+	// Inside tmux, open the session next to the TUI instead of suspending it;
+	// the new window runs "dogssh connect", which renews certificates itself.
+	if t.useTmux() {
+		t.connectInTmux(alias)
+		if warning := t.certificateWarning(server); warning != "" {
+			t.showStatusTempColor(warning, theme.Error)
+		}
+		return
+	}
 
-		// After SSH session ends, ensure we're back to the main screen
-		// and refresh the server list
+	// Suspend the TUI, renew an expired certificate and execute SSH
+	warning := t.certificateWarning(server)
+	var renewErr error
+	t.app.Suspend(func() {
+		if warning != "" {
+			_, _ = fmt.Fprintln(os.Stderr, warning)
+		}
+		renewErr = t.renewCertificate(alias)
+		_ = t.serverService.SSH(alias)
+	})
+
+	// After SSH session ends, ensure we're back to the main screen and
+	// refresh the server list. This already runs on the event loop, where
+	// QueueUpdateDraw would block forever waiting for itself.
+	t.returnToMain()
+	// Hide search bar if it was visible
+	if t.searchVisible {
+		t.hideSearchBar()
+	}
+	// Refresh the server list to update connection metadata
+	t.refreshServerList()
+	// Re-focus on the server list
+	t.app.SetFocus(t.serverList)
+	// Show a status message
+	switch {
+	case renewErr != nil:
+		t.showStatusTempColor(fmt.Sprintf("Disconnected from %s — certificate renewal failed: %v", alias, renewErr), theme.Error)
+	case warning != "":
+		t.showStatusTempColor(fmt.Sprintf("Disconnected from %s — %s", alias, warning), theme.Error)
+	default:
+		t.showStatusTemp(fmt.Sprintf("Disconnected from %s", alias))
+	}
+}
+
+// connectInTmux opens the session for alias in a tmux window or split and keeps the TUI live.
+func (t *tui) connectInTmux(alias string) {
+	mode := t.settings.Connection.TmuxMode
+	if err := t.tmuxService.Open(alias, mode); err != nil {
+		t.logger.Errorw("tmux open failed", "alias", alias, "error", err)
+		t.showStatusTempColor(fmt.Sprintf("tmux: %v", err), theme.Error)
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Opened %s in tmux %s", alias, mode))
+}
+
+// handleSessionOpen opens the selected server in a new embedded terminal tab.
+func (t *tui) handleSessionOpen() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	alias := server.Alias
+
+	name, args := connectCommand(alias)
+	session := terminal.NewSession(alias, terminal.Command(name, args...))
+	session.OnUpdate(func() {
+		t.app.Draw()
+	}).OnExit(func(err error) {
 		t.app.QueueUpdateDraw(func() {
-			// Ensure we're showing the main interface
-			t.returnToMain()
-			// Hide search bar if it was visible
-			if t.searchVisible {
-				t.hideSearchBar()
-			}
-			// Refresh the server list to update connection metadata
-			t.refreshServerList()
-			// Re-focus on the server list
-			t.app.SetFocus(t.serverList)
-			// Show a status message
-			t.showStatusTemp(fmt.Sprintf("Disconnected from %s", alias))
+			t.handleSessionExit(session, err)
 		})
+	})
+
+	_, _, width, height := t.root.GetRect()
+	if err := session.Start(max(width, 80), max(height-1, 24)); err != nil {
+		t.logger.Errorw("failed to start terminal session", "alias", alias, "error", err)
+		t.showStatusTempColor(fmt.Sprintf("Session %s: %v", alias, err), theme.Error)
+		return
+	}
+	t.logger.Infow("terminal session started", "alias", alias)
+
+	t.sessions.Add(session)
+	t.showSessions()
+}
+
+func (t *tui) handleSessionsShow() {
+	if t.sessions.Len() == 0 {
+		t.showStatusTemp("No open sessions — press T to open one")
+		return
+	}
+	t.showSessions()
+}
+
+func (t *tui) handleSessionsDetach() {
+	t.returnToMain()
+	t.app.SetFocus(t.serverList)
+}
+
+func (t *tui) handleSessionExit(session *terminal.Session, err error) {
+	t.sessions.Remove(session)
+	t.refreshServerList()
+
+	msg := fmt.Sprintf("Session %s closed", session.Title())
+	if err != nil {
+		t.logger.Warnw("terminal session ended with error", "alias", session.Title(), "error", err)
+		msg = fmt.Sprintf("Session %s closed: %v", session.Title(), err)
+	}
+
+	// Only move focus if the user is looking at the tabs right now.
+	if _, inSessions := t.app.GetFocus().(*TerminalView); inSessions {
+		if t.sessions.Len() == 0 {
+			t.handleSessionsDetach()
+		} else {
+			t.showSessions()
+		}
 	}
+	t.showStatusTemp(msg)
+}
+
+// handleClusterConnect opens a synchronized tmux window for the marked servers, else for
+// the group under the cursor, and otherwise asks for a tag.
+func (t *tui) handleClusterConnect() {
+	if !t.tmuxService.Available() {
+		t.showStatusTempColor("Cluster SSH requires running dogssh inside tmux", theme.Error)
+		return
+	}
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.handleGroupCluster(markedGroup(servers))
+		return
+	}
+	if group, ok := t.serverList.GetSelectedGroup(); ok {
+		t.handleGroupCluster(group)
+		return
+	}
+	t.showClusterForm()
 }
 
 func (t *tui) handleServerSelectionChange(server domain.Server) {
@@ -205,12 +358,28 @@ func (t *tui) handleServerSelectionChange(server domain.Server) {
 		hasPassword = false
 	}
 
-	// Update the details view with the server information and password status
-	t.details.UpdateServerWithPasswordCheck(server, hasPassword)
+	// Update the details view with the server information and local checks
+	hostKeys, err := t.knownHostsService.Lookup(server)
+	if err != nil {
+		t.logger.Warnw("failed to look up known_hosts", "alias", server.Alias, "error", err)
+	}
+	// Without an agent there is nothing to report, so the line is left out.
+	agentKeys, _ := t.agentService.KeyStatus(server)
+	checks := ServerChecks{
+		HasPassword: hasPassword,
+		KeyIssues:   t.keyService.IdentityIssues(server),
+		AgentKeys:   agentKeys,
+		HostKeys:    hostKeys,
+	}
+	if cert, ok := t.certService.Inspect(server); ok {
+		checks.Certificate = &cert
+		checks.CertificateStatus = t.certService.Status(server)
+	}
+	t.details.UpdateServerWithChecks(server, checks)
 }
 
 func (t *tui) handleServerAdd() {
-	form := NewServerForm(ServerFormAdd, nil).
+	form := NewServerForm(ServerFormAdd, nil, t.settings.Form).
 		OnSave(t.handleServerSave).
 		OnCancel(t.handleFormCancel)
 	t.app.SetRoot(form, true)
@@ -218,7 +387,7 @@ func (t *tui) handleServerAdd() {
 
 func (t *tui) handleServerEdit() {
 	if server, ok := t.serverList.GetSelectedServer(); ok {
-		form := NewServerForm(ServerFormEdit, &server).
+		form := NewServerForm(ServerFormEdit, &server, t.settings.Form).
 			OnSave(t.handleServerSave).
 			OnCancel(t.handleFormCancel)
 		t.app.SetRoot(form, true)
@@ -249,6 +418,10 @@ func (t *tui) handleServerSave(server domain.Server, original *domain.Server) {
 }
 
 func (t *tui) handleServerDelete() {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.handleBatchDelete(servers)
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		t.showDeleteConfirmModal(server)
 	}
@@ -259,6 +432,14 @@ func (t *tui) handleFormCancel() {
 }
 
 func (t *tui) handlePingSelected() {
+	if servers := t.markedServers(); len(servers) > 0 {
+		t.handleGroupPing(markedGroup(servers))
+		return
+	}
+	if group, ok := t.serverList.GetSelectedGroup(); ok {
+		t.handleGroupPing(group)
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		alias := server.Alias
 
@@ -266,14 +447,17 @@ func (t *tui) handlePingSelected() {
 		go func() {
 			up, dur, err := t.serverService.Ping(server)
 			t.app.QueueUpdateDraw(func() {
+				t.recordReachability(alias, up && err == nil, dur)
+				t.serverList.Redraw()
+				t.resortByReachability()
 				if err != nil {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN (%v)", alias, err), "#FF6B6B")
+					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN (%v)", alias, err), theme.Error)
 					return
 				}
 				if up {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: UP (%s)", alias, dur), "#A0FFA0")
+					t.showStatusTempColor(fmt.Sprintf("Ping %s: UP (%s)", alias, dur), theme.Success)
 				} else {
-					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN", alias), "#FF6B6B")
+					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN", alias), theme.Error)
 				}
 			})
 		}()
@@ -288,32 +472,31 @@ func (t *tui) handleModalClose() {
 // It preserves the current search query and selection, shows transient status, and avoids concurrent runs.
 func (t *tui) handleRefreshBackground() {
 	currentIdx := t.serverList.GetCurrentItem()
-	query := ""
-	if t.searchVisible {
-		query = t.searchBar.InputField.GetText()
-	}
+	query := t.currentQuery()
 
 	t.showStatusTemp("Refreshing…")
 
 	go func(prevIdx int, q string) {
-		servers, err := t.serverService.ListServers(q)
+		matches, err := t.serverService.SearchServers(q)
 		if err != nil {
 			t.app.QueueUpdateDraw(func() {
-				t.showStatusTempColor(fmt.Sprintf("Refresh failed: %v", err), "#FF6B6B")
+				t.showStatusTempColor(fmt.Sprintf("Refresh failed: %v", err), theme.Error)
 			})
 			return
 		}
-		sortServersForUI(servers, t.sortMode)
 		t.app.QueueUpdateDraw(func() {
-			t.serverList.UpdateServers(servers)
+			// Reachability is only touched on the UI goroutine.
+			domain.SortMatches(matches, t.sortOrder, t.reachability)
+			t.serverList.UpdateMatches(matches)
 			// Try to restore selection if still valid
-			if prevIdx >= 0 && prevIdx < t.serverList.List.GetItemCount() {
+			if prevIdx >= 0 && prevIdx < t.serverList.GetItemCount() {
 				t.serverList.SetCurrentItem(prevIdx)
 				if srv, ok := t.serverList.GetSelectedServer(); ok {
-					t.details.UpdateServer(srv)
+					t.handleServerSelectionChange(srv)
 				}
 			}
-			t.showStatusTemp(fmt.Sprintf("Refreshed %d servers", len(servers)))
+			t.refreshViews()
+			t.showStatusTemp(fmt.Sprintf("Refreshed %d servers", len(matches)))
 		})
 	}(currentIdx, query)
 }
@@ -323,10 +506,18 @@ func (t *tui) handleRefreshBackground() {
 // =============================================================================
 
 func (t *tui) showSearchBar() {
-	t.left.Clear()
-	t.left.AddItem(t.searchBar, 3, 0, true)
-	t.left.AddItem(t.serverList, 0, 1, false)
+	t.revealSearchBar()
 	t.app.SetFocus(t.searchBar)
+}
+
+// revealSearchBar shows the search bar above the list without moving focus.
+func (t *tui) revealSearchBar() {
+	if t.searchVisible {
+		return
+	}
+	t.left.Clear()
+	t.left.AddItem(t.searchBar, 3, 0, false)
+	t.left.AddItem(t.serverList, 0, 1, true)
 	t.searchVisible = true
 }
 
@@ -338,11 +529,13 @@ func (t *tui) showDeleteConfirmModal(server domain.Server) {
 		SetText(msg).
 		AddButtons([]string{"Cancel", "Confirm"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.handleModalClose()
 			if buttonIndex == 1 {
-				_ = t.serverService.DeleteServer(server)
+				if err := t.serverService.DeleteServer(server); err != nil {
+					t.showStatusTempColor(fmt.Sprintf("Delete failed: %v", err), theme.Error)
+				}
 				t.refreshServerList()
 			}
-			t.handleModalClose()
 		})
 
 	t.app.SetRoot(modal, true)
@@ -358,17 +551,8 @@ func (t *tui) showEditTagsForm(server domain.Server) {
 	form.AddInputField("Tags (comma):", defaultTags, 40, nil, nil)
 
 	form.AddButton("Save", func() {
-		text := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
-		var tags []string
-
-		for _, part := range strings.Split(text, ",") {
-			if s := strings.TrimSpace(part); s != "" {
-				tags = append(tags, s)
-			}
-		}
-
 		newServer := server
-		newServer.Tags = tags
+		newServer.Tags = splitTags(form.GetFormItem(0).(*tview.InputField).GetText())
 		_ = t.serverService.UpdateServer(server, newServer)
 		// Refresh UI and go back
 		t.refreshServerList()
@@ -382,6 +566,51 @@ func (t *tui) showEditTagsForm(server domain.Server) {
 	t.app.SetFocus(form)
 }
 
+// showClusterForm asks for a tag and opens a synchronized tmux window for every
+// listed server carrying it. An empty tag uses every server currently listed; to pick
+// servers one by one, mark them first.
+func (t *tui) showClusterForm() {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle("Cluster SSH").
+		SetTitleAlign(tview.AlignLeft)
+
+	form.AddInputField("Tag (empty = all listed):", "", 30, nil, nil)
+
+	form.AddButton("Open", func() {
+		tag := strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText())
+		aliases := clusterAliases(t.serverList.Servers(), tag)
+		if len(aliases) == 0 {
+			form.SetTitle("Cluster SSH — [" + theme.Error + "::b]no matching servers[-]")
+			return
+		}
+
+		name := "cssh"
+		if tag != "" {
+			name = "cssh:" + tag
+		}
+		t.returnToMain()
+		if err := t.tmuxService.OpenCluster(name, aliases); err != nil {
+			t.logger.Errorw("tmux cluster open failed", "tag", tag, "error", err)
+			t.showStatusTempColor(fmt.Sprintf("tmux: %v", err), theme.Error)
+			return
+		}
+		t.showStatusTemp(fmt.Sprintf("Opened %d synchronized panes", len(aliases)))
+	})
+	form.AddButton("Cancel", func() { t.returnToMain() })
+	form.SetCancelFunc(func() { t.returnToMain() })
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) showSessions() {
+	t.app.SetRoot(t.sessions, true)
+	if current := t.sessions.Current(); current != nil {
+		t.app.SetFocus(current)
+	}
+}
+
 // =============================================================================
 // UI State Management (hide UI elements)
 // =============================================================================
@@ -399,13 +628,45 @@ func (t *tui) hideSearchBar() {
 // =============================================================================
 
 func (t *tui) refreshServerList() {
-	query := ""
-	if t.searchVisible {
-		query = t.searchBar.InputField.GetText()
+	t.handleSearchInput(t.currentQuery())
+	t.refreshViews()
+}
+
+// connectCommand returns the command line that runs the normal dogssh SSH path for alias,
+// so passwords and metadata recording work the same as with Enter.
+func connectCommand(alias string) (string, []string) {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = AppName
 	}
-	filtered, _ := t.serverService.ListServers(query)
-	sortServersForUI(filtered, t.sortMode)
-	t.serverList.UpdateServers(filtered)
+	return executable, []string{"connect", alias}
+}
+
+// clusterAliases returns the aliases of servers carrying tag, or all of them when tag is empty.
+func clusterAliases(servers []domain.Server, tag string) []string {
+	aliases := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if tag == "" || hasTag(server, tag) {
+			aliases = append(aliases, server.Alias)
+		}
+	}
+	return aliases
+}
+
+func hasTag(server domain.Server, tag string) bool {
+	for _, t := range server.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// useTmux reports whether connections should be opened through tmux.
+func (t *tui) useTmux() bool {
+	return t.tmuxService != nil &&
+		t.settings.Connection.TmuxMode != domain.TmuxModeOff &&
+		t.tmuxService.Available()
 }
 
 func (t *tui) returnToMain() {
@@ -417,7 +678,7 @@ func (t *tui) showStatusTemp(msg string) {
 	if t.statusBar == nil {
 		return
 	}
-	t.showStatusTempColor(msg, "#A0FFA0")
+	t.showStatusTempColor(msg, theme.Success)
 }
 
 // showStatusTempColor displays a temporary colored message in the status bar and restores default text after 2s.
@@ -430,7 +691,7 @@ func (t *tui) showStatusTempColor(msg string, color string) {
 		if t.app != nil {
 			t.app.QueueUpdateDraw(func() {
 				if t.statusBar != nil {
-					t.statusBar.SetText(DefaultStatusText())
+					t.statusBar.SetText(t.defaultStatusText())
 				}
 			})
 		}
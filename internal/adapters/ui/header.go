@@ -41,7 +41,7 @@ func NewAppHeader(version, gitCommit, repoURL string) *AppHeader {
 }
 
 func (h *AppHeader) build() {
-	headerBg := tcell.Color234
+	headerBg := theme.Header
 
 	left := h.buildLeftSection(headerBg)
 	center := h.buildCenterSection(headerBg)
@@ -64,7 +64,7 @@ func (h *AppHeader) buildLeftSection(bg tcell.Color) *tview.TextView {
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignLeft)
 	left.SetBackgroundColor(bg)
-	stylizedName := "🚀 [#FFFFFF::b]Dog[-][#55D7FF::b]SSH[-]"
+	stylizedName := "🚀 [" + theme.Strong + "::b]Dog[-][" + theme.Accent + "::b]SSH[-]"
 	left.SetText(stylizedName)
 	return left
 }
@@ -78,10 +78,10 @@ func (h *AppHeader) buildCenterSection(bg tcell.Color) *tview.TextView {
 	commit := shortCommit(h.gitCommit)
 
 	// Build tag-like chips for version, commit, and build time
-	versionTag := makeTag(h.version, "#22C55E") // green
+	versionTag := makeTag(h.version, theme.Success)
 	commitTag := ""
 	if commit != "" {
-		commitTag = makeTag(commit, "#A78BFA") // violet
+		commitTag = makeTag(commit, theme.Accent)
 	}
 
 	text := versionTag
@@ -99,14 +99,14 @@ func (h *AppHeader) buildRightSection(bg tcell.Color) *tview.TextView {
 		SetTextAlign(tview.AlignRight)
 	right.SetBackgroundColor(bg)
 	currentTime := time.Now().Format("Mon, 02 Jan 2006 15:04")
-	right.SetText("[#AAAAAA]• " + currentTime + "[-]")
+	right.SetText("[" + theme.Subtle + "]• " + currentTime + "[-]")
 	return right
 }
 
 func (h *AppHeader) createSeparator() *tview.TextView {
 	separator := tview.NewTextView().SetDynamicColors(true)
-	separator.SetBackgroundColor(tcell.Color235)
-	separator.SetText("[#444444]" + strings.Repeat("─", 200) + "[-]")
+	separator.SetBackgroundColor(theme.Bar)
+	separator.SetText("[" + colorTag(theme.Border) + "]" + strings.Repeat("─", 200) + "[-]")
 	return separator
 }
 
@@ -128,5 +128,5 @@ func makeTag(text, bg string) string {
 	if text == "" {
 		return ""
 	}
-	return "[black:" + bg + "::b]  " + text + "  [-]"
+	return theme.chip(bg, "b") + "  " + text + "  [-:-:-]"
 }
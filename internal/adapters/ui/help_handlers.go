@@ -0,0 +1,53 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// handleHelpShow lists every action with its key bindings.
+func (t *tui) handleHelpShow() {
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false).
+		SetText(t.helpText())
+	text.SetBorder(true).
+		SetTitle(" Key Bindings ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	footer := tview.NewTextView().SetDynamicColors(true)
+	footer.SetBackgroundColor(theme.Bar)
+	footer.SetText(" Rebind actions under [" + theme.Strong + "]keys:[-] in ~/.dogssh/config.yaml  • [" + theme.Strong + "]↑↓[-] Scroll  • [" + theme.Strong + "]Esc/q[-] Close")
+
+	view := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(text, 0, 1, true).
+		AddItem(footer, 1, 0, false)
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if a, ok := t.keyActions[eventKeyName(event)]; ok && a.name == "help" ||
+			event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+			t.returnToMain()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+}
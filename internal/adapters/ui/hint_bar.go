@@ -15,13 +15,12 @@
 package ui
 
 import (
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-func NewHintBar() *tview.TextView {
+func NewHintBar(text string) *tview.TextView {
 	hint := tview.NewTextView().SetDynamicColors(true)
-	hint.SetBackgroundColor(tcell.Color233)
-	hint.SetText("[#BBBBBB]Press [::b]/[-:-:b] to search…  •  ↑↓ Navigate  •  Enter SSH  •  c Copy SSH  •  g Ping  •  r Refresh  •  a Add  •  e Edit  •  t Tags  •  d Delete  •  p Pin/Unpin  •  s Sort[-]")
+	hint.SetBackgroundColor(theme.Field)
+	hint.SetText(text)
 	return hint
 }
@@ -0,0 +1,320 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+var keyTypes = []string{"ed25519", "rsa", "ecdsa"}
+
+// defaultKeyBits are offered in the generate form; ed25519 has a fixed size.
+var defaultKeyBits = map[string]string{"ed25519": "", "rsa": "4096", "ecdsa": "521"}
+
+func (t *tui) handleKeysShow() {
+	view := NewKeysView()
+	view.OnClose(func() {
+		t.keysView = nil
+		t.returnToMain()
+		t.refreshServerList()
+	}).OnGenerate(func() {
+		t.showKeyGenForm()
+	}).OnReload(func() {
+		t.refreshKeys()
+	}).OnRotate(func(key domain.SSHKey) {
+		t.handleKeyRotate(key)
+	}).OnInstall(func(key domain.SSHKey) {
+		t.showKeyInstallForm(key.Path, func() {
+			t.app.SetRoot(view, true)
+			t.app.SetFocus(view)
+			t.refreshKeys()
+		})
+	})
+
+	t.keysView = view
+	t.refreshKeys()
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+}
+
+func (t *tui) refreshKeys() {
+	if t.keysView == nil {
+		return
+	}
+	keys, err := t.keyService.List()
+	if err != nil {
+		t.logger.Errorw("failed to list keys", "error", err)
+		t.showStatusTempColor(fmt.Sprintf("List keys: %v", err), theme.Error)
+	}
+	t.keysView.SetKeys(keys)
+}
+
+func (t *tui) showKeyGenForm() {
+	title := "Generate SSH Key"
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	keyGenOptions := addKeyGenFields(form, "", defaultKeyComment())
+
+	back := func() {
+		t.app.SetRoot(t.keysView, true)
+		t.app.SetFocus(t.keysView)
+	}
+	form.AddButton("Generate", func() {
+		opts, err := keyGenOptions()
+		if err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%v[-]", title, err))
+			return
+		}
+		key, err := t.keyService.Generate(opts)
+		if err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%v[-]", title, err))
+			return
+		}
+		back()
+		t.refreshKeys()
+		t.keysView.SelectPath(key.Path)
+		t.showStatusTemp(fmt.Sprintf("Generated %s (%s)", key.Path, key.Fingerprint))
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// addKeyGenFields adds the type, bits, path, comment and passphrase fields to form
+// and returns a function reading them. An empty path defaults to ~/.ssh/id_<type>
+// and follows the selected type.
+func addKeyGenFields(form *tview.Form, path, comment string) func() (domain.KeyGenOptions, error) {
+	defaultPath := func(keyType string) string { return "~/.ssh/id_" + keyType }
+	followType := path == ""
+	if followType {
+		path = defaultPath(keyTypes[0])
+	}
+
+	typeField := tview.NewDropDown().SetLabel("Type:").SetOptions(keyTypes, nil).SetCurrentOption(0)
+	bitsField := tview.NewInputField().SetLabel("Bits:").SetText(defaultKeyBits[keyTypes[0]]).
+		SetFieldWidth(6).SetAcceptanceFunc(tview.InputFieldInteger)
+	pathField := tview.NewInputField().SetLabel("Path:").SetText(path).SetFieldWidth(40)
+	commentField := tview.NewInputField().SetLabel("Comment:").SetText(comment).SetFieldWidth(40)
+	passField := tview.NewInputField().SetLabel("Passphrase (optional):").SetFieldWidth(40).SetMaskCharacter('*')
+	repeatField := tview.NewInputField().SetLabel("Repeat passphrase:").SetFieldWidth(40).SetMaskCharacter('*')
+	form.AddFormItem(typeField).
+		AddFormItem(bitsField).
+		AddFormItem(pathField).
+		AddFormItem(commentField).
+		AddFormItem(passField).
+		AddFormItem(repeatField)
+
+	previous := keyTypes[0]
+	typeField.SetSelectedFunc(func(text string, index int) {
+		if text == previous {
+			return
+		}
+		// Follow the type unless the user typed their own path.
+		if followType && pathField.GetText() == defaultPath(previous) {
+			pathField.SetText(defaultPath(text))
+		}
+		bitsField.SetText(defaultKeyBits[text])
+		previous = text
+	})
+
+	return func() (domain.KeyGenOptions, error) {
+		_, keyType := typeField.GetCurrentOption()
+		bits, _ := strconv.Atoi(strings.TrimSpace(bitsField.GetText()))
+		if passField.GetText() != repeatField.GetText() {
+			return domain.KeyGenOptions{}, fmt.Errorf("passphrases do not match")
+		}
+		return domain.KeyGenOptions{
+			Type:       keyType,
+			Bits:       bits,
+			Path:       strings.TrimSpace(pathField.GetText()),
+			Comment:    strings.TrimSpace(commentField.GetText()),
+			Passphrase: passField.GetText(),
+		}, nil
+	}
+}
+
+func defaultKeyComment() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	if host, err := os.Hostname(); err == nil {
+		return u.Username + "@" + host
+	}
+	return u.Username
+}
+
+// keyInstallConcurrency bounds how many servers are contacted at once.
+const keyInstallConcurrency = 5
+
+func (t *tui) handleKeyInstall() {
+	if len(t.serverList.Servers()) == 0 {
+		return
+	}
+	t.showKeyInstallForm("", t.returnToMain)
+}
+
+// showKeyInstallForm asks for a key and the servers to install it on.
+// keyPath preselects a key; back returns to the calling screen.
+func (t *tui) showKeyInstallForm(keyPath string, back func()) {
+	keys, err := t.keyService.List()
+	if err != nil {
+		t.logger.Errorw("failed to list keys", "error", err)
+	}
+	var paths, labels []string
+	selected := 0
+	for _, key := range keys {
+		if key.PublicKeyPath == "" {
+			continue
+		}
+		if key.Path == keyPath {
+			selected = len(paths)
+		}
+		paths = append(paths, key.Path)
+		labels = append(labels, fmt.Sprintf("%s (%s)", filepath.Base(key.Path), key.Type))
+	}
+	if len(paths) == 0 {
+		t.showStatusTempColor("No public keys found in ~/.ssh; generate one with K", theme.Error)
+		back()
+		return
+	}
+
+	title := "Install Public Key"
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	scopes := []string{t.selectedScopeLabel(), fmt.Sprintf("All listed servers (%d)", len(t.serverList.Servers()))}
+	form.AddDropDown("Key:", labels, selected, nil)
+	form.AddDropDown("Install on:", scopes, runScopeSelected, nil)
+	form.AddInputField("Only tag (optional):", "", 20, nil, nil)
+
+	form.AddButton("Install", func() {
+		keyIdx, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		scope, _ := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		tag := strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText())
+
+		var aliases []string
+		if scope == runScopeSelected {
+			aliases = t.selectedAliases(tag)
+		} else {
+			aliases = clusterAliases(t.serverList.Servers(), tag)
+		}
+		if len(aliases) == 0 {
+			form.SetTitle(title + " — [" + theme.Error + "::b]no matching servers[-]")
+			return
+		}
+		t.installKey(paths[max(keyIdx, 0)], aliases, back)
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// installKey installs keyPath on aliases in the background and shows per-host results.
+func (t *tui) installKey(keyPath string, aliases []string, back func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	view := NewKeyInstallView(keyPath, len(aliases))
+	view.OnClose(func() {
+		cancel()
+		back()
+		t.refreshServerList()
+	})
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+
+	go func() {
+		sem := make(chan struct{}, keyInstallConcurrency)
+		var wg sync.WaitGroup
+		for _, alias := range aliases {
+			wg.Add(1)
+			go func(alias string) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				result := t.keyService.InstallKey(ctx, alias, keyPath)
+				t.app.QueueUpdateDraw(func() {
+					view.AddResult(result)
+				})
+			}(alias)
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+		t.app.QueueUpdateDraw(func() {
+			t.offerKeyAuthSwitch(view, keyPath)
+		})
+	}()
+}
+
+// offerKeyAuthSwitch asks whether servers that now accept the key should stop using their stored password.
+func (t *tui) offerKeyAuthSwitch(view *KeyInstallView, keyPath string) {
+	var aliases []string
+	for _, r := range view.Results() {
+		if r.Verified && r.HasPassword {
+			aliases = append(aliases, r.Alias)
+		}
+	}
+	if len(aliases) == 0 {
+		return
+	}
+
+	back := func() {
+		t.app.SetRoot(view, true)
+		t.app.SetFocus(view)
+	}
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Key login works on %s.\n\nUse %s as IdentityFile and delete the stored password?",
+			strings.Join(aliases, ", "), filepath.Base(keyPath))).
+		AddButtons([]string{"Keep password", "Switch to key"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			back()
+			if buttonIndex != 1 {
+				return
+			}
+			var failed []string
+			for _, alias := range aliases {
+				if err := t.keyService.UseKey(alias, keyPath); err != nil {
+					t.logger.Errorw("failed to switch to key auth", "alias", alias, "error", err)
+					failed = append(failed, alias)
+				}
+			}
+			if len(failed) > 0 {
+				t.showStatusTempColor("Could not switch "+strings.Join(failed, ", ")+" to key auth", theme.Error)
+				return
+			}
+			t.showStatusTemp(fmt.Sprintf("Switched %d server(s) to key auth", len(aliases)))
+		})
+
+	t.app.SetRoot(modal, true)
+}
@@ -0,0 +1,152 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KeyInstallView shows per-host progress of installing a public key.
+type KeyInstallView struct {
+	*tview.Flex
+	total   int
+	results []domain.KeyInstallResult
+	table   *tview.Table
+	footer  *tview.TextView
+	onClose func()
+}
+
+func NewKeyInstallView(keyPath string, total int) *KeyInstallView {
+	view := &KeyInstallView{
+		Flex:   tview.NewFlex(),
+		total:  total,
+		table:  tview.NewTable(),
+		footer: tview.NewTextView(),
+	}
+	view.table.SetTitle(" Install key: " + tview.Escape(filepath.Base(keyPath)) + " ")
+	view.build()
+	return view
+}
+
+func (v *KeyInstallView) build() {
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc || event.Rune() == 'q' {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		return event
+	})
+	v.render()
+}
+
+// AddResult records a finished host, keeping failures at the top.
+func (v *KeyInstallView) AddResult(result domain.KeyInstallResult) {
+	v.results = append(v.results, result)
+	sort.SliceStable(v.results, func(i, j int) bool {
+		fi, fj := v.results[i].Failed(), v.results[j].Failed()
+		if fi != fj {
+			return fi
+		}
+		return v.results[i].Alias < v.results[j].Alias
+	})
+	v.render()
+}
+
+// Results returns the hosts finished so far.
+func (v *KeyInstallView) Results() []domain.KeyInstallResult {
+	return v.results
+}
+
+// Done reports whether every host has finished.
+func (v *KeyInstallView) Done() bool {
+	return len(v.results) == v.total
+}
+
+func (v *KeyInstallView) render() {
+	v.table.Clear()
+	for col, header := range []string{"Host", "authorized_keys", "Key login", "Password", "Time", "Error"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	failed := 0
+	for i, r := range v.results {
+		row := i + 1
+		installed := "-"
+		if r.Added {
+			installed = "added"
+		} else if r.Error == "" || r.Verified {
+			installed = "already present"
+		}
+		login, loginColor := "failed", theme.color(theme.Error)
+		if r.Verified {
+			login, loginColor = "OK", theme.color(theme.Success)
+		} else {
+			failed++
+		}
+		password := "-"
+		if r.HasPassword {
+			password = "stored"
+		}
+
+		v.table.SetCell(row, 0, tview.NewTableCell(r.Alias))
+		v.table.SetCell(row, 1, tview.NewTableCell(installed))
+		v.table.SetCell(row, 2, tview.NewTableCell(login).SetTextColor(loginColor))
+		v.table.SetCell(row, 3, tview.NewTableCell(password))
+		v.table.SetCell(row, 4, tview.NewTableCell(r.Duration.Round(time.Millisecond).String()).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 5, tview.NewTableCell(r.Error).SetTextColor(theme.color(theme.Error)).SetExpansion(1))
+	}
+
+	progress := fmt.Sprintf("%d/%d done", len(v.results), v.total)
+	if !v.Done() {
+		progress = "[" + theme.Warning + "]" + progress + "…[-]"
+	}
+	v.footer.SetText(fmt.Sprintf(" %s  •  ["+theme.Success+"]%d ok[-]  ["+theme.Error+"]%d failed[-]  •  ["+theme.Strong+"]Esc[-] Back",
+		progress, len(v.results)-failed, failed))
+
+	if len(v.results) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(max(row, 1), 0)
+	}
+}
+
+func (v *KeyInstallView) OnClose(fn func()) *KeyInstallView {
+	v.onClose = fn
+	return v
+}
@@ -0,0 +1,194 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KeysView lists the private keys in ~/.ssh and the servers that use them.
+type KeysView struct {
+	*tview.Flex
+	keys       []domain.SSHKey
+	table      *tview.Table
+	footer     *tview.TextView
+	onGenerate func()
+	onInstall  func(domain.SSHKey)
+	onRotate   func(domain.SSHKey)
+	onReload   func()
+	onClose    func()
+}
+
+func NewKeysView() *KeysView {
+	view := &KeysView{
+		Flex:   tview.NewFlex(),
+		table:  tview.NewTable(),
+		footer: tview.NewTextView(),
+	}
+	view.build()
+	return view
+}
+
+func (v *KeysView) build() {
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetTitle(" SSH Keys ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+	v.footer.SetText(" [" + theme.Strong + "]n[-] Generate key  • [" + theme.Strong + "]i[-] Install on servers  • [" + theme.Strong + "]R[-] Rotate  • [" + theme.Strong + "]r[-] Reload  • [" + theme.Strong + "]Esc[-] Back")
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.Flex.SetInputCapture(v.handleKeys)
+	v.render()
+}
+
+func (v *KeysView) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+		if v.onClose != nil {
+			v.onClose()
+		}
+		return nil
+	case event.Rune() == 'n':
+		if v.onGenerate != nil {
+			v.onGenerate()
+		}
+		return nil
+	case event.Rune() == 'i':
+		if key, ok := v.Selected(); ok && v.onInstall != nil {
+			v.onInstall(key)
+		}
+		return nil
+	case event.Rune() == 'R':
+		if key, ok := v.Selected(); ok && v.onRotate != nil {
+			v.onRotate(key)
+		}
+		return nil
+	case event.Rune() == 'r':
+		if v.onReload != nil {
+			v.onReload()
+		}
+		return nil
+	}
+	return event
+}
+
+// Selected returns the highlighted key.
+func (v *KeysView) Selected() (domain.SSHKey, bool) {
+	row, _ := v.table.GetSelection()
+	if row < 1 || row > len(v.keys) {
+		return domain.SSHKey{}, false
+	}
+	return v.keys[row-1], true
+}
+
+// SetKeys replaces the listed keys and keeps the selection on the same file.
+func (v *KeysView) SetKeys(keys []domain.SSHKey) {
+	selected, hadSelection := v.Selected()
+	v.keys = keys
+	v.render()
+
+	if !hadSelection {
+		return
+	}
+	v.SelectPath(selected.Path)
+}
+
+// SelectPath highlights the key stored at p.
+func (v *KeysView) SelectPath(p string) {
+	for i, key := range v.keys {
+		if key.Path == p {
+			v.table.Select(i+1, 0)
+			return
+		}
+	}
+}
+
+func (v *KeysView) render() {
+	v.table.Clear()
+	for col, header := range []string{"Name", "Type", "Bits", "Fingerprint", "Comment", "Protected", "Used by", "Problem"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, key := range v.keys {
+		row := i + 1
+		bits, protected, usedBy := "-", "no", "-"
+		if key.Bits > 0 {
+			bits = fmt.Sprintf("%d", key.Bits)
+		}
+		protectedColor := theme.Muted
+		if key.Encrypted {
+			protected, protectedColor = "yes", theme.color(theme.Success)
+		}
+		if len(key.Servers) > 0 {
+			usedBy = strings.Join(key.Servers, ", ")
+		}
+
+		v.table.SetCell(row, 0, tview.NewTableCell(filepath.Base(key.Path)))
+		v.table.SetCell(row, 1, tview.NewTableCell(key.Type))
+		v.table.SetCell(row, 2, tview.NewTableCell(bits).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 3, tview.NewTableCell(key.Fingerprint))
+		v.table.SetCell(row, 4, tview.NewTableCell(key.Comment))
+		v.table.SetCell(row, 5, tview.NewTableCell(protected).SetTextColor(protectedColor))
+		v.table.SetCell(row, 6, tview.NewTableCell(usedBy).SetMaxWidth(30))
+		v.table.SetCell(row, 7, tview.NewTableCell(key.Problem).SetTextColor(theme.color(theme.Error)).SetExpansion(1))
+	}
+
+	if len(v.keys) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(min(max(row, 1), len(v.keys)), 0)
+	}
+}
+
+func (v *KeysView) OnGenerate(fn func()) *KeysView {
+	v.onGenerate = fn
+	return v
+}
+
+func (v *KeysView) OnInstall(fn func(domain.SSHKey)) *KeysView {
+	v.onInstall = fn
+	return v
+}
+
+func (v *KeysView) OnRotate(fn func(domain.SSHKey)) *KeysView {
+	v.onRotate = fn
+	return v
+}
+
+func (v *KeysView) OnReload(fn func()) *KeysView {
+	v.onReload = fn
+	return v
+}
+
+func (v *KeysView) OnClose(fn func()) *KeysView {
+	v.onClose = fn
+	return v
+}
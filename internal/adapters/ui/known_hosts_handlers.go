@@ -0,0 +1,177 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+func (t *tui) handleKnownHostsShow() {
+	view := NewKnownHostsView()
+	view.OnClose(func() {
+		t.knownHostsView = nil
+		t.returnToMain()
+		t.refreshServerList()
+	}).OnSearch(func(string) {
+		t.refreshKnownHosts()
+	}).OnReload(func() {
+		t.refreshKnownHosts()
+	}).OnDelete(func(entry domain.KnownHost) {
+		t.showKnownHostDeleteModal(entry)
+	})
+
+	t.knownHostsView = view
+	t.refreshKnownHosts()
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+}
+
+// refreshKnownHosts lists the entries matching the search text. Hashed entries
+// can't be searched by substring, so the text is also looked up as a host name.
+func (t *tui) refreshKnownHosts() {
+	view := t.knownHostsView
+	if view == nil {
+		return
+	}
+	entries, err := t.knownHostsService.List()
+	if err != nil {
+		t.logger.Errorw("failed to read known_hosts", "error", err)
+		t.showStatusTempColor(fmt.Sprintf("known_hosts: %v", err), theme.Error)
+	}
+
+	query := strings.ToLower(view.Query())
+	if query == "" {
+		view.SetEntries(entries)
+		return
+	}
+
+	matches := make(map[int]bool)
+	if found, err := t.knownHostsService.Lookup(domain.Server{Alias: query}); err == nil {
+		for _, entry := range found {
+			matches[entry.Line] = true
+		}
+	}
+	var filtered []domain.KnownHost
+	for _, entry := range entries {
+		text := strings.ToLower(strings.Join(entry.Hosts, ",") + " " + entry.KeyType + " " + entry.Fingerprint + " " + entry.Comment)
+		if matches[entry.Line] || (!entry.Hashed && strings.Contains(text, query)) ||
+			strings.Contains(strings.ToLower(entry.Fingerprint), query) {
+			filtered = append(filtered, entry)
+		}
+	}
+	view.SetEntries(filtered)
+}
+
+func (t *tui) showKnownHostDeleteModal(entry domain.KnownHost) {
+	hosts := strings.Join(entry.Hosts, ",")
+	if entry.Hashed {
+		hosts = "a hashed host"
+	}
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Remove the %s key of %s (line %d) from known_hosts?\n\n%s\n\nThe previous file is kept as known_hosts.old.",
+			entry.KeyType, hosts, entry.Line, entry.Fingerprint)).
+		AddButtons([]string{"Cancel", "Remove"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				if err := t.knownHostsService.Remove([]domain.KnownHost{entry}); err != nil {
+					t.showStatusTempColor(fmt.Sprintf("Remove entry: %v", err), theme.Error)
+				} else {
+					t.showStatusTemp(fmt.Sprintf("Removed line %d from known_hosts", entry.Line))
+				}
+			}
+			t.app.SetRoot(t.knownHostsView, true)
+			t.app.SetFocus(t.knownHostsView)
+			t.refreshKnownHosts()
+		})
+	t.app.SetRoot(modal, true)
+}
+
+// handleHostKeyVerify scans the selected server's host key and compares it with known_hosts.
+func (t *tui) handleHostKeyVerify() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Scanning host key of %s…", server.Alias))
+	go func() {
+		check, err := t.knownHostsService.Check(context.Background(), server)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.logger.Errorw("host key check failed", "alias", server.Alias, "error", err)
+				t.showStatusTempColor(fmt.Sprintf("Host key %s: %v", server.Alias, err), theme.Error)
+				return
+			}
+			t.showHostKeyCheck(check)
+		})
+	}()
+}
+
+// showHostKeyCheck explains the result of a host key check and offers to record the scanned keys.
+func (t *tui) showHostKeyCheck(check domain.HostKeyCheck) {
+	var b strings.Builder
+	switch check.Status {
+	case domain.HostKeyMatch:
+		fmt.Fprintf(&b, "✓ The host key of %s matches known_hosts.\n\n", check.Address)
+	case domain.HostKeyUnknown:
+		fmt.Fprintf(&b, "%s is not in known_hosts yet.\n\nCompare the fingerprint with one obtained out of band before trusting it.\n\n", check.Address)
+	case domain.HostKeyChanged:
+		fmt.Fprintf(&b, "⚠ THE HOST KEY OF %s HAS CHANGED.\n\nThis happens after a reinstall or key regeneration, but it may also mean someone is intercepting the connection. Only replace the recorded keys if you expected the change.\n\n", check.Address)
+	}
+	b.WriteString("Recorded:\n")
+	if len(check.Recorded) == 0 {
+		b.WriteString("  (none)\n")
+	}
+	for _, entry := range check.Recorded {
+		fmt.Fprintf(&b, "  %s %s (line %d)\n", entry.KeyType, entry.Fingerprint, entry.Line)
+	}
+	b.WriteString("Server presents:\n")
+	for _, key := range check.Scanned {
+		fmt.Fprintf(&b, "  %s %s\n", key.Type, key.Fingerprint)
+	}
+
+	var buttons []string
+	switch check.Status {
+	case domain.HostKeyMatch:
+		buttons = []string{"OK"}
+	case domain.HostKeyUnknown:
+		buttons = []string{"Cancel", "Trust and record"}
+	case domain.HostKeyChanged:
+		buttons = []string{"Cancel", "Replace recorded keys"}
+	}
+
+	modal := tview.NewModal().
+		SetText(b.String()).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				if err := t.knownHostsService.Accept(check); err != nil {
+					t.showStatusTempColor(fmt.Sprintf("Record host key: %v", err), theme.Error)
+				} else {
+					t.showStatusTemp(fmt.Sprintf("Recorded %d host key(s) for %s", len(check.Scanned), check.Address))
+				}
+			}
+			t.returnToMain()
+			t.refreshServerList()
+		})
+	if check.Status == domain.HostKeyChanged {
+		modal.SetBackgroundColor(theme.Danger)
+	}
+	t.app.SetRoot(modal, true)
+}
@@ -0,0 +1,206 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KnownHostsView lists known_hosts entries with a search field.
+type KnownHostsView struct {
+	*tview.Flex
+	entries []domain.KnownHost
+	search  *tview.InputField
+	table   *tview.Table
+	footer  *tview.TextView
+	// active is the search field or the table, whichever receives keys.
+	active   tview.Primitive
+	onSearch func(string)
+	onDelete func(domain.KnownHost)
+	onReload func()
+	onClose  func()
+}
+
+func NewKnownHostsView() *KnownHostsView {
+	view := &KnownHostsView{
+		Flex:   tview.NewFlex(),
+		search: tview.NewInputField(),
+		table:  tview.NewTable(),
+		footer: tview.NewTextView(),
+	}
+	view.active = view.table
+	view.build()
+	return view
+}
+
+func (v *KnownHostsView) build() {
+	v.search.SetLabel(" / ").
+		SetFieldBackgroundColor(theme.Field).
+		SetPlaceholder("host, key type or fingerprint; exact host names also match hashed entries").
+		SetPlaceholderTextColor(theme.Muted)
+	v.search.SetChangedFunc(func(text string) {
+		if v.onSearch != nil {
+			v.onSearch(strings.TrimSpace(text))
+		}
+	})
+	v.search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEsc && v.search.GetText() != "" {
+			v.search.SetText("")
+		}
+		v.active = v.table
+	})
+
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetTitle(" Known Hosts ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+	v.footer.SetText(" [" + theme.Strong + "]/[-] Search  • [" + theme.Strong + "]d[-] Remove entry  • [" + theme.Strong + "]r[-] Reload  • [" + theme.Strong + "]Esc[-] Back")
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(v.search, 1, 0, false).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+	v.render()
+}
+
+func (v *KnownHostsView) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+		if v.onClose != nil {
+			v.onClose()
+		}
+		return nil
+	case event.Rune() == '/':
+		v.active = v.search
+		return nil
+	case event.Rune() == 'd':
+		if entry, ok := v.Selected(); ok && v.onDelete != nil {
+			v.onDelete(entry)
+		}
+		return nil
+	case event.Rune() == 'r':
+		if v.onReload != nil {
+			v.onReload()
+		}
+		return nil
+	}
+	return event
+}
+
+// Focus implements tview.Primitive.
+func (v *KnownHostsView) Focus(delegate func(p tview.Primitive)) {
+	delegate(v.active)
+}
+
+// InputHandler sends keys to the search field while it is active and to the table otherwise.
+func (v *KnownHostsView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return v.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		active := v.active
+		if active == v.table {
+			event = v.handleKeys(event)
+		}
+		if event != nil {
+			if handler := active.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+		}
+		if v.active != active {
+			setFocus(v.active)
+		}
+	})
+}
+
+// Query returns the current search text.
+func (v *KnownHostsView) Query() string {
+	return strings.TrimSpace(v.search.GetText())
+}
+
+// Selected returns the highlighted entry.
+func (v *KnownHostsView) Selected() (domain.KnownHost, bool) {
+	row, _ := v.table.GetSelection()
+	if row < 1 || row > len(v.entries) {
+		return domain.KnownHost{}, false
+	}
+	return v.entries[row-1], true
+}
+
+// SetEntries replaces the listed entries.
+func (v *KnownHostsView) SetEntries(entries []domain.KnownHost) {
+	v.entries = entries
+	v.render()
+}
+
+func (v *KnownHostsView) render() {
+	v.table.Clear()
+	for col, header := range []string{"Line", "Host", "Type", "Fingerprint", "Comment"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, entry := range v.entries {
+		row := i + 1
+		hosts := strings.Join(entry.Hosts, ",")
+		hostColor := theme.Text
+		if entry.Hashed {
+			hosts, hostColor = "(hashed)", theme.Muted
+		}
+		if entry.Marker != "" {
+			hosts = "@" + entry.Marker + " " + hosts
+		}
+		v.table.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("%d", entry.Line)).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(hosts)).SetTextColor(hostColor).SetMaxWidth(50))
+		v.table.SetCell(row, 2, tview.NewTableCell(entry.KeyType))
+		v.table.SetCell(row, 3, tview.NewTableCell(entry.Fingerprint))
+		v.table.SetCell(row, 4, tview.NewTableCell(tview.Escape(entry.Comment)).SetExpansion(1))
+	}
+
+	v.table.SetTitle(fmt.Sprintf(" Known Hosts (%d) ", len(v.entries)))
+	if len(v.entries) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(min(max(row, 1), len(v.entries)), 0)
+	}
+}
+
+func (v *KnownHostsView) OnSearch(fn func(string)) *KnownHostsView {
+	v.onSearch = fn
+	return v
+}
+
+func (v *KnownHostsView) OnDelete(fn func(domain.KnownHost)) *KnownHostsView {
+	v.onDelete = fn
+	return v
+}
+
+func (v *KnownHostsView) OnReload(fn func()) *KnownHostsView {
+	v.onReload = fn
+	return v
+}
+
+func (v *KnownHostsView) OnClose(fn func()) *KnownHostsView {
+	v.onClose = fn
+	return v
+}
@@ -0,0 +1,136 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+// handleKeyRotate rotates key, resuming an unfinished rotation of it if there is one.
+func (t *tui) handleKeyRotate(key domain.SSHKey) {
+	pending, err := t.rotationService.Pending()
+	if err != nil {
+		t.logger.Errorw("failed to load pending rotations", "error", err)
+	}
+	for _, rotation := range pending {
+		if rotation.OldKey == key.Path || rotation.NewKey == key.Path {
+			t.showRotationResumeModal(rotation)
+			return
+		}
+	}
+	if len(key.Servers) == 0 {
+		t.showStatusTempColor(fmt.Sprintf("No server uses %s", filepath.Base(key.Path)), theme.Error)
+		return
+	}
+	t.showRotationForm(key)
+}
+
+func (t *tui) showRotationResumeModal(rotation domain.KeyRotation) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("An unfinished rotation of %s → %s started %s.\n\nResume it?",
+			filepath.Base(rotation.OldKey), filepath.Base(rotation.NewKey),
+			rotation.StartedAt.Format("2006-01-02 15:04"))).
+		AddButtons([]string{"Cancel", "Resume"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				t.runRotation(rotation)
+				return
+			}
+			t.app.SetRoot(t.keysView, true)
+			t.app.SetFocus(t.keysView)
+		})
+	t.app.SetRoot(modal, true)
+}
+
+// showRotationForm asks for the replacement of key before starting the rotation.
+func (t *tui) showRotationForm(key domain.SSHKey) {
+	title := "Rotate " + filepath.Base(key.Path)
+	newPath := strings.TrimSuffix(key.Path, filepath.Ext(key.Path)) + "_" + time.Now().Format("20060102")
+
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	form.AddTextView("Used by:", strings.Join(key.Servers, ", "), 60, 2, false, false)
+	keyGenOptions := addKeyGenFields(form, newPath, key.Comment)
+
+	back := func() {
+		t.app.SetRoot(t.keysView, true)
+		t.app.SetFocus(t.keysView)
+	}
+	form.AddButton("Rotate", func() {
+		opts, err := keyGenOptions()
+		if err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%v[-]", title, err))
+			return
+		}
+		rotation, err := t.rotationService.Start(key.Path, opts)
+		if err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%v[-]", title, err))
+			return
+		}
+		t.runRotation(rotation)
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// runRotation performs the remaining steps of rotation in the background.
+func (t *tui) runRotation(rotation domain.KeyRotation) {
+	ctx, cancel := context.WithCancel(context.Background())
+	view := NewRotationView(rotation, t.rotationService.ReportPath(rotation.ID))
+
+	run := func() {
+		view.SetRunning(true)
+		go func() {
+			result, err := t.rotationService.Run(ctx, rotation, func(r domain.KeyRotation) {
+				t.app.QueueUpdateDraw(func() { view.SetRotation(r) })
+			})
+			t.app.QueueUpdateDraw(func() {
+				rotation = result
+				view.SetRotation(result)
+				view.SetRunning(false)
+				switch {
+				case err != nil && ctx.Err() == nil:
+					t.logger.Errorw("key rotation failed", "id", rotation.ID, "error", err)
+					t.showStatusTempColor(fmt.Sprintf("Rotation: %v", err), theme.Error)
+				case result.Done():
+					t.showStatusTemp(fmt.Sprintf("Rotated %s on %d server(s)", filepath.Base(result.OldKey), len(result.Hosts)))
+				}
+			})
+		}()
+	}
+
+	view.OnRetry(run).OnClose(func() {
+		cancel()
+		t.app.SetRoot(t.keysView, true)
+		t.app.SetFocus(t.keysView)
+		t.refreshKeys()
+	})
+
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+	run()
+}
@@ -0,0 +1,151 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// RotationView shows the per-host progress of a key rotation.
+type RotationView struct {
+	*tview.Flex
+	rotation   domain.KeyRotation
+	reportPath string
+	running    bool
+	table      *tview.Table
+	footer     *tview.TextView
+	onRetry    func()
+	onClose    func()
+}
+
+func NewRotationView(rotation domain.KeyRotation, reportPath string) *RotationView {
+	view := &RotationView{
+		Flex:       tview.NewFlex(),
+		rotation:   rotation,
+		reportPath: reportPath,
+		table:      tview.NewTable(),
+		footer:     tview.NewTextView(),
+	}
+	view.build()
+	return view
+}
+
+func (v *RotationView) build() {
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetTitle(fmt.Sprintf(" Rotate %s → %s ", filepath.Base(v.rotation.OldKey), filepath.Base(v.rotation.NewKey))).
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.Flex.SetInputCapture(v.handleKeys)
+	v.render()
+}
+
+func (v *RotationView) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+		if v.onClose != nil {
+			v.onClose()
+		}
+		return nil
+	case event.Rune() == 'r':
+		if !v.running && !v.rotation.Done() && v.onRetry != nil {
+			v.onRetry()
+		}
+		return nil
+	}
+	return event
+}
+
+// SetRotation shows the latest state of the rotation.
+func (v *RotationView) SetRotation(rotation domain.KeyRotation) {
+	v.rotation = rotation
+	v.render()
+}
+
+// SetRunning marks whether steps are still being performed.
+func (v *RotationView) SetRunning(running bool) {
+	v.running = running
+	v.render()
+}
+
+func (v *RotationView) render() {
+	v.table.Clear()
+	for col, header := range []string{"Host", "New key", "IdentityFile", "Old key removed", "Error"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	done := 0
+	for i, host := range v.rotation.Hosts {
+		if host.Done() {
+			done++
+		}
+		row := i + 1
+		v.table.SetCell(row, 0, tview.NewTableCell(host.Alias))
+		v.table.SetCell(row, 1, rotationStepCell(host.Installed, "installed"))
+		v.table.SetCell(row, 2, rotationStepCell(host.ConfigUpdated, "updated"))
+		v.table.SetCell(row, 3, rotationStepCell(host.OldKeyRemoved, "removed"))
+		v.table.SetCell(row, 4, tview.NewTableCell(host.Error).SetTextColor(theme.color(theme.Error)).SetExpansion(1))
+	}
+
+	status := fmt.Sprintf("%d/%d hosts done", done, len(v.rotation.Hosts))
+	switch {
+	case v.running:
+		status = "[" + theme.Warning + "]" + status + "…[-]"
+	case v.rotation.Done():
+		status = "[" + theme.Success + "]" + status + "[-]"
+	default:
+		status += "  •  [" + theme.Strong + "]r[-] Retry failed"
+	}
+	v.footer.SetText(fmt.Sprintf(" %s  •  Report: %s  • ["+theme.Strong+"]Esc[-] Back", status, tview.Escape(v.reportPath)))
+
+	if len(v.rotation.Hosts) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(max(row, 1), 0)
+	}
+}
+
+func rotationStepCell(ok bool, label string) *tview.TableCell {
+	if ok {
+		return tview.NewTableCell("✓ " + label).SetTextColor(theme.color(theme.Success))
+	}
+	return tview.NewTableCell("-").SetTextColor(theme.Muted)
+}
+
+func (v *RotationView) OnRetry(fn func()) *RotationView {
+	v.onRetry = fn
+	return v
+}
+
+func (v *RotationView) OnClose(fn func()) *RotationView {
+	v.onClose = fn
+	return v
+}
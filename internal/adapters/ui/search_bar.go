@@ -35,13 +35,13 @@ func NewSearchBar() *SearchBar {
 
 func (s *SearchBar) build() {
 	s.InputField.SetLabel(" 🔍 Search: ").
-		SetFieldBackgroundColor(tcell.Color233).
-		SetFieldTextColor(tcell.Color252).
-		SetFieldWidth(30).
+		SetFieldBackgroundColor(theme.Field).
+		SetFieldTextColor(theme.Text).
+		SetFieldWidth(0).
 		SetBorder(true).
 		SetTitle("Search").
-		SetBorderColor(tcell.Color238).
-		SetTitleColor(tcell.Color250)
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
 
 	s.InputField.SetChangedFunc(func(text string) {
 		if s.onSearch != nil {
@@ -58,6 +58,15 @@ func (s *SearchBar) build() {
 	})
 }
 
+// SetError shows a query syntax error in the title, or restores it when msg is empty.
+func (s *SearchBar) SetError(msg string) {
+	if msg == "" {
+		s.InputField.SetTitle("Search").SetBorderColor(theme.Border)
+		return
+	}
+	s.InputField.SetTitle("Search — [" + theme.Error + "::b]" + tview.Escape(msg) + "[-:-:-]").SetBorderColor(theme.color(theme.Error))
+}
+
 func (s *SearchBar) OnSearch(fn func(string)) *SearchBar {
 	s.onSearch = fn
 	return s
@@ -0,0 +1,160 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+// columnSpec describes how a column of the server list is shown.
+type columnSpec struct {
+	title string
+	align int
+	// min is the narrowest the column gets when the terminal is too small.
+	min int
+}
+
+var columnSpecs = map[domain.ListColumn]columnSpec{
+	domain.ColumnAlias:        {title: "Alias", align: tview.AlignLeft, min: 8},
+	domain.ColumnHost:         {title: "Host", align: tview.AlignLeft, min: 8},
+	domain.ColumnUser:         {title: "User", align: tview.AlignLeft, min: 4},
+	domain.ColumnPort:         {title: "Port", align: tview.AlignRight, min: 4},
+	domain.ColumnLastSeen:     {title: "Last SSH", align: tview.AlignLeft, min: 8},
+	domain.ColumnSSHCount:     {title: "SSH", align: tview.AlignRight, min: 3},
+	domain.ColumnTags:         {title: "Tags", align: tview.AlignLeft, min: 6},
+	domain.ColumnReachability: {title: "Status", align: tview.AlignLeft, min: 6},
+	domain.ColumnLatency:      {title: "Latency", align: tview.AlignRight, min: 7},
+	domain.ColumnJump:         {title: "Jump", align: tview.AlignLeft, min: 6},
+	domain.ColumnNotes:        {title: "Notes", align: tview.AlignLeft, min: 6},
+}
+
+// shrinkOrder lists the columns narrowed first when the terminal is too small.
+var shrinkOrder = []domain.ListColumn{
+	domain.ColumnNotes, domain.ColumnTags, domain.ColumnJump, domain.ColumnHost, domain.ColumnUser,
+	domain.ColumnAlias, domain.ColumnLastSeen, domain.ColumnReachability, domain.ColumnLatency,
+	domain.ColumnSSHCount, domain.ColumnPort,
+}
+
+// serverCell returns the text of one column for server.
+func (sl *ServerList) serverCell(server domain.Server, column domain.ListColumn) string {
+	hl := sl.highlights[server.Alias]
+	switch column {
+	case domain.ColumnAlias:
+		alias := tview.Escape(server.Alias)
+		if len(hl.Alias) > 0 {
+			alias = highlightMatches(server.Alias, hl.Alias, "["+theme.Warning+"::u]", "["+theme.Strong+"::U]", 0)
+		}
+		text := "[" + theme.Strong + "::b]" + alias + "[-:-:-]"
+		if sl.certificateStatus != nil {
+			text += certificateBadge(sl.certificateStatus(server))
+		}
+		return text
+	case domain.ColumnHost:
+		host := tview.Escape(server.Host)
+		if len(hl.Host) > 0 {
+			host = highlightMatches(server.Host, hl.Host, "["+theme.Warning+"::u]", "["+theme.Subtle+"::U]", 0)
+		}
+		return "[" + theme.Subtle + "]" + host + "[-:-:-]"
+	case domain.ColumnUser:
+		return tview.Escape(server.User)
+	case domain.ColumnPort:
+		return strconv.Itoa(server.Port)
+	case domain.ColumnLastSeen:
+		return "[" + theme.Dim + "]" + humanizeDuration(server.LastSeen) + "[-]"
+	case domain.ColumnSSHCount:
+		return strconv.Itoa(server.SSHCount)
+	case domain.ColumnTags:
+		return renderTagBadgesForList(server.Tags, hl.Tags)
+	case domain.ColumnReachability, domain.ColumnLatency:
+		if sl.reachability == nil {
+			return ""
+		}
+		r, ok := sl.reachability(server.Alias)
+		switch {
+		case !ok:
+			return ""
+		case column == domain.ColumnLatency && r.Up:
+			return fmt.Sprintf("%dms", r.Latency.Milliseconds())
+		case column == domain.ColumnLatency:
+			return ""
+		case r.Up:
+			return "[" + theme.Success + "]● up[-]"
+		default:
+			return "[" + theme.Error + "]● down[-]"
+		}
+	case domain.ColumnJump:
+		return "[" + theme.Subtle + "]" + tview.Escape(server.ProxyJump) + "[-]"
+	case domain.ColumnNotes:
+		return "[" + theme.Dim + "]" + tview.Escape(firstLine(server.Notes)) + "[-]"
+	}
+	return ""
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+// fitColumns sizes the columns to their content, within the configured widths, and
+// narrows them in shrinkOrder until the table fits width.
+func (sl *ServerList) fitColumns(width int) {
+	rows := sl.Table.GetRowCount()
+	columnWidth := func(c int) int {
+		w := 0
+		for r := 0; r < rows; r++ {
+			if cell := sl.Table.GetCell(r, c); cell != nil {
+				w = max(w, tview.TaggedStringWidth(cell.Text))
+			}
+		}
+		return w
+	}
+
+	widths := make(map[domain.ListColumn]int, len(sl.columns))
+	// Columns are separated by one space.
+	total := columnWidth(0) + len(sl.columns)
+	for c, column := range sl.columns {
+		w := columnWidth(c + 1)
+		if limit := sl.widths[column]; limit > 0 {
+			w = min(w, limit)
+		}
+		widths[column] = w
+		total += w
+	}
+	for _, column := range shrinkOrder {
+		if total <= width {
+			break
+		}
+		w, ok := widths[column]
+		if !ok {
+			continue
+		}
+		cut := min(max(w-columnSpecs[column].min, 0), total-width)
+		widths[column] = w - cut
+		total -= cut
+	}
+
+	for c, column := range sl.columns {
+		for r := 0; r < rows; r++ {
+			if cell := sl.Table.GetCell(r, c+1); cell != nil {
+				cell.SetMaxWidth(widths[column])
+			}
+		}
+	}
+}
@@ -16,15 +16,19 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
 type ServerDetails struct {
 	*tview.TextView
+	// commands and groupCommands list the key bindings below a server and a group.
+	commands      string
+	groupCommands string
 }
 
 func NewServerDetails() *ServerDetails {
@@ -40,8 +44,15 @@ func (sd *ServerDetails) build() {
 		SetWrap(true).
 		SetBorder(true).
 		SetTitle("Details").
-		SetBorderColor(tcell.Color238).
-		SetTitleColor(tcell.Color250)
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+}
+
+// SetCommands sets the key binding lists shown below a server and below a group.
+func (sd *ServerDetails) SetCommands(commands, groupCommands string) *ServerDetails {
+	sd.commands = commands
+	sd.groupCommands = groupCommands
+	return sd
 }
 
 // renderTagChips builds colored tag chips for details view.
@@ -51,13 +62,32 @@ func renderTagChips(tags []string) string {
 	}
 	chips := make([]string, 0, len(tags))
 	for _, t := range tags {
-		chips = append(chips, fmt.Sprintf("[black:#5FAFFF] %s [-:-:-]", t))
+		chips = append(chips, theme.chip(theme.Accent, "")+" "+t+" [-:-:-]")
 	}
 	return strings.Join(chips, " ")
 }
 
+// ServerChecks carries local checks shown alongside a server's details.
+type ServerChecks struct {
+	HasPassword bool
+	// KeyIssues lists identity files that are missing or have unsafe permissions.
+	KeyIssues []string
+	// AgentKeys tells which identity files ssh-agent holds; nil when there is no agent.
+	AgentKeys []domain.AgentKeyStatus
+	// HostKeys are the known_hosts entries recorded for the server.
+	HostKeys []domain.KnownHost
+	// Certificate is the SSH certificate the server logs in with; nil when it uses none.
+	Certificate       *domain.Certificate
+	CertificateStatus domain.CertificateStatus
+}
+
 // UpdateServer updates the details view with the provided server information.
 func (sd *ServerDetails) UpdateServer(server domain.Server) {
+	sd.UpdateServerWithChecks(server, ServerChecks{})
+}
+
+// UpdateServerWithChecks updates the details view and shows the results of local checks.
+func (sd *ServerDetails) UpdateServerWithChecks(server domain.Server, checks ServerChecks) {
 	lastSeen := server.LastSeen.Format("2006-01-02 15:04:05")
 	if server.LastSeen.IsZero() {
 		lastSeen = "Never"
@@ -69,45 +99,188 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		pinnedStr = "false"
 	}
 	tagsText := renderTagChips(server.Tags)
+	jumpText := ""
+	if server.ProxyJump != "" {
+		jumpText = "Jump: [" + theme.Strong + "]" + tview.Escape(server.ProxyJump) + "[-]\n"
+	}
+	notesText := ""
+	if server.Notes != "" {
+		notesText = "Notes: " + tview.Escape(server.Notes) + "\n"
+	}
 
 	// 显示密码状态而不是明文密码
 	passwordStatus := "Not set"
+	if checks.HasPassword {
+		passwordStatus = "Set (hidden)"
+	}
 
 	text := fmt.Sprintf(
-		"[::b]%s[-]\n\nHost: [white]%s[-]\nUser: [white]%s[-]\nPort: [white]%d[-]\nKey:  [white]%s[-]\nPassword: [white]%s[-]\nTags: %s\nPinned: [white]%s[-]\nLast SSH: %s\nSSH Count: [white]%d[-]\n\n[::b]Commands:[-]\n  Enter: SSH connect\n  c: Copy SSH command\n  g: Ping server\n  r: Refresh list\n  a: Add new server\n  e: Edit entry\n  t: Edit tags\n  d: Delete entry\n  p: Pin/Unpin",
-		strings.Join(server.Aliases, ", "), server.Host, server.User, server.Port,
-		serverKey, passwordStatus, tagsText, pinnedStr,
-		lastSeen, server.SSHCount)
+		"[::b]%s[-]\n\nHost: ["+theme.Strong+"]%s[-]\nUser: ["+theme.Strong+"]%s[-]\nPort: ["+theme.Strong+"]%d[-]\n%sKey:  ["+theme.Strong+"]%s[-]\n%sPassword: ["+theme.Strong+"]%s[-]\n%sTags: %s\n%sPinned: ["+theme.Strong+"]%s[-]\nLast SSH: %s\nSSH Count: ["+theme.Strong+"]%d[-]\n\n[::b]Commands:[-]%s",
+		strings.Join(server.Aliases, ", "), server.Host, server.User, server.Port, jumpText,
+		serverKey, renderKeyIssues(checks.KeyIssues)+renderAgentKeys(checks.AgentKeys)+renderCertificate(checks.Certificate, checks.CertificateStatus), passwordStatus, renderHostKeys(checks.HostKeys), tagsText, notesText, pinnedStr,
+		lastSeen, server.SSHCount, sd.commands)
 	sd.TextView.SetText(text)
 }
 
-// UpdateServerWithPasswordCheck updates the details view with the provided server information.
-// It also checks if a password is stored for the server and displays the appropriate status.
-func (sd *ServerDetails) UpdateServerWithPasswordCheck(server domain.Server, hasPassword bool) {
-	lastSeen := server.LastSeen.Format("2006-01-02 15:04:05")
-	if server.LastSeen.IsZero() {
-		lastSeen = "Never"
+// renderKeyIssues lists identity file problems as warning lines.
+func renderKeyIssues(issues []string) string {
+	var b strings.Builder
+	for _, issue := range issues {
+		fmt.Fprintf(&b, "  ["+theme.Error+"]⚠ %s[-]\n", tview.Escape(issue))
 	}
-	serverKey := strings.Join(server.IdentityFiles, ", ")
+	return b.String()
+}
 
-	pinnedStr := "true"
-	if server.PinnedAt.IsZero() {
-		pinnedStr = "false"
+// renderAgentKeys shows whether each identity file is loaded in ssh-agent.
+func renderAgentKeys(keys []domain.AgentKeyStatus) string {
+	if len(keys) == 0 {
+		return ""
 	}
-	tagsText := renderTagChips(server.Tags)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		name := tview.Escape(filepath.Base(key.Path))
+		switch {
+		case key.Loaded:
+			parts = append(parts, "["+theme.Success+"]✓[-] "+name)
+		case key.Unreadable:
+			parts = append(parts, "["+theme.Dim+"]? "+name+"[-]")
+		case key.Encrypted:
+			parts = append(parts, "["+theme.Error+"]✗[-] "+name+" ["+theme.Dim+"](locked)[-]")
+		default:
+			parts = append(parts, "["+theme.Error+"]✗[-] "+name)
+		}
+	}
+	return "Agent: " + strings.Join(parts, ", ") + "\n"
+}
 
-	// 显示密码状态而不是明文密码
-	passwordStatus := "Not set"
-	if hasPassword {
-		passwordStatus = "Set (hidden)"
+// renderCertificate shows the key ID, principals and validity window of a certificate.
+func renderCertificate(cert *domain.Certificate, status domain.CertificateStatus) string {
+	if cert == nil {
+		return ""
+	}
+	var b strings.Builder
+	name := tview.Escape(filepath.Base(cert.Path))
+	switch status {
+	case domain.CertificateMissing:
+		fmt.Fprintf(&b, "Cert: ["+theme.Strong+"]%s[-] %s\n", name, certificateStatusLabel(status))
+		return b.String()
+	case domain.CertificateInvalid:
+		fmt.Fprintf(&b, "Cert: ["+theme.Strong+"]%s[-] %s ["+theme.Dim+"](%s)[-]\n", name, certificateStatusLabel(status), tview.Escape(cert.Error))
+		return b.String()
 	}
 
-	text := fmt.Sprintf(
-		"[::b]%s[-]\n\nHost: [white]%s[-]\nUser: [white]%s[-]\nPort: [white]%d[-]\nKey:  [white]%s[-]\nPassword: [white]%s[-]\nTags: %s\nPinned: [white]%s[-]\nLast SSH: %s\nSSH Count: [white]%d[-]\n\n[::b]Commands:[-]\n  Enter: SSH connect\n  c: Copy SSH command\n  g: Ping server\n  r: Refresh list\n  a: Add new server\n  e: Edit entry\n  t: Edit tags\n  d: Delete entry\n  p: Pin/Unpin",
-		strings.Join(server.Aliases, ", "), server.Host, server.User, server.Port,
-		serverKey, passwordStatus, tagsText, pinnedStr,
-		lastSeen, server.SSHCount)
-	sd.TextView.SetText(text)
+	fmt.Fprintf(&b, "Cert: ["+theme.Strong+"]%s[-] %s ["+theme.Dim+"](%s, serial %d)[-]\n", tview.Escape(cert.KeyID), certificateStatusLabel(status), cert.Type, cert.Serial)
+	principals := "[" + theme.Dim + "]any[-]"
+	if len(cert.Principals) > 0 {
+		principals = "[" + theme.Strong + "]" + tview.Escape(strings.Join(cert.Principals, ", ")) + "[-]"
+	}
+	fmt.Fprintf(&b, "  Principals: %s\n", principals)
+	fmt.Fprintf(&b, "  Valid: %s → %s%s\n", formatCertTime(cert.ValidAfter, "always"), formatCertTime(cert.ValidBefore, "forever"), certificateRemaining(cert.ValidBefore, status))
+	return b.String()
+}
+
+// certificateStatusLabel colors a certificate status for the details view.
+func certificateStatusLabel(status domain.CertificateStatus) string {
+	switch status {
+	case domain.CertificateValid:
+		return "[" + theme.Success + "]✓ valid[-]"
+	case domain.CertificateExpiring:
+		return "[" + theme.Warning + "]⚠ expiring[-]"
+	default:
+		return "[" + theme.Error + "]✗ " + string(status) + "[-]"
+	}
+}
+
+// certificateBadge marks servers whose certificate needs attention in the server list.
+func certificateBadge(status domain.CertificateStatus) string {
+	switch status {
+	case "", domain.CertificateValid:
+		return ""
+	case domain.CertificateExpiring:
+		return " [" + theme.Warning + "]⌛ cert expiring[-]"
+	default:
+		return " [" + theme.Error + "]⌛ cert " + string(status) + "[-]"
+	}
+}
+
+func formatCertTime(t time.Time, unset string) string {
+	if t.IsZero() {
+		return unset
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// certificateRemaining describes how long until a certificate expires or since it did.
+func certificateRemaining(validBefore time.Time, status domain.CertificateStatus) string {
+	if validBefore.IsZero() {
+		return ""
+	}
+	switch status {
+	case domain.CertificateExpired:
+		return fmt.Sprintf(" ["+theme.Error+"](expired %s ago)[-]", formatCertDuration(time.Since(validBefore)))
+	case domain.CertificateValid, domain.CertificateExpiring:
+		return fmt.Sprintf(" ["+theme.Dim+"](%s left)[-]", formatCertDuration(time.Until(validBefore)))
+	}
+	return ""
+}
+
+func formatCertDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours())/24)
+	}
+}
+
+// renderHostKeys lists the recorded host-key fingerprints of a server.
+func renderHostKeys(entries []domain.KnownHost) string {
+	if len(entries) == 0 {
+		return "Host key: [" + theme.Dim + "]not in known_hosts[-]\n"
+	}
+	var b strings.Builder
+	b.WriteString("Host keys:\n")
+	for _, entry := range entries {
+		hashed := ""
+		if entry.Hashed {
+			hashed = " [" + theme.Dim + "](hashed)[-]"
+		}
+		fmt.Fprintf(&b, "  ["+theme.Strong+"]%s[-] %s%s\n", strings.TrimPrefix(entry.KeyType, "ssh-"), entry.Fingerprint, hashed)
+	}
+	return b.String()
+}
+
+// UpdateGroup shows a group of the tree view with the last ping result of its servers.
+func (sd *ServerDetails) UpdateGroup(group domain.ServerGroup, mode domain.GroupMode, reachability func(alias string) (domain.Reachability, bool)) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%s[-]\n\nGrouped by: ["+theme.Strong+"]%s[-]\nServers: ["+theme.Strong+"]%d[-]\n", tview.Escape(group.Name), mode, len(group.Servers))
+
+	up, down, unknown := 0, 0, 0
+	var lines strings.Builder
+	for _, server := range group.Servers {
+		state := "[" + theme.Dim + "]○[-]"
+		latency := ""
+		if r, ok := reachability(server.Alias); !ok {
+			unknown++
+		} else if r.Up {
+			up++
+			state = "[" + theme.Success + "]●[-]"
+			latency = r.Latency.Round(time.Millisecond).String()
+		} else {
+			down++
+			state = "[" + theme.Error + "]●[-]"
+			latency = "down"
+		}
+		fmt.Fprintf(&lines, "  %s %-12s ["+theme.Subtle+"]%-18s[-] %s\n", state, tview.Escape(server.Alias), tview.Escape(server.Host), latency)
+	}
+	fmt.Fprintf(&b, "Reachable: ["+theme.Success+"]%d up[-], ["+theme.Error+"]%d down[-], %d not checked\n\n", up, down, unknown)
+	b.WriteString(lines.String())
+	b.WriteString("\n[::b]Group commands:[-]" + sd.groupCommands)
+	sd.TextView.SetText(b.String())
 }
 
 func (sd *ServerDetails) ShowEmpty() {
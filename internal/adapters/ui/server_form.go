@@ -22,7 +22,6 @@ import (
 	"strings"
 
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
@@ -37,15 +36,18 @@ type ServerForm struct {
 	*tview.Form
 	mode     ServerFormMode
 	original *domain.Server
+	// defaults prefill the fields of a new server.
+	defaults domain.FormSettings
 	onSave   func(domain.Server, *domain.Server)
 	onCancel func()
 }
 
-func NewServerForm(mode ServerFormMode, original *domain.Server) *ServerForm {
+func NewServerForm(mode ServerFormMode, original *domain.Server, defaults domain.FormSettings) *ServerForm {
 	form := &ServerForm{
 		Form:     tview.NewForm(),
 		mode:     mode,
 		original: original,
+		defaults: defaults,
 	}
 	form.build()
 	return form
@@ -57,8 +59,8 @@ func (sf *ServerForm) build() {
 	sf.Form.SetBorder(true).
 		SetTitle(title).
 		SetTitleAlign(tview.AlignLeft).
-		SetBorderColor(tcell.Color238).
-		SetTitleColor(tcell.Color250)
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
 
 	sf.addFormFields()
 
@@ -83,14 +85,16 @@ func (sf *ServerForm) addFormFields() {
 			User:     sf.original.User,
 			Port:     fmt.Sprint(sf.original.Port),
 			Key:      strings.Join(sf.original.IdentityFiles, ", "),
+			Cert:     sf.original.CertificateFile,
 			Password: sf.original.Password, // Display existing password (as placeholder)
 			Tags:     strings.Join(sf.original.Tags, ", "),
+			Notes:    sf.original.Notes,
 		}
 	} else {
 		defaultValues = ServerFormData{
-			User: "root",
-			Port: "22",
-			Key:  "~/.ssh/id_ed25519",
+			User: sf.defaults.User,
+			Port: strconv.Itoa(sf.defaults.Port),
+			Key:  sf.defaults.IdentityFile,
 		}
 	}
 
@@ -99,8 +103,10 @@ func (sf *ServerForm) addFormFields() {
 	sf.Form.AddInputField("User:", defaultValues.User, 20, nil, nil)
 	sf.Form.AddInputField("Port:", defaultValues.Port, 20, nil, nil)
 	sf.Form.AddInputField("Key (Comma):", defaultValues.Key, 40, nil, nil)
+	sf.Form.AddInputField("Certificate:", defaultValues.Cert, 40, nil, nil)
 	sf.Form.AddInputField("Password:", defaultValues.Password, 20, nil, nil) // Add password input field
 	sf.Form.AddInputField("Tags (comma):", defaultValues.Tags, 30, nil, nil)
+	sf.Form.AddInputField("Notes:", defaultValues.Notes, 40, nil, nil)
 }
 
 type ServerFormData struct {
@@ -109,8 +115,10 @@ type ServerFormData struct {
 	User     string
 	Port     string
 	Key      string
+	Cert     string
 	Password string
 	Tags     string
+	Notes    string
 }
 
 func (sf *ServerForm) getFormData() ServerFormData {
@@ -120,8 +128,10 @@ func (sf *ServerForm) getFormData() ServerFormData {
 		User:     strings.TrimSpace(sf.Form.GetFormItem(2).(*tview.InputField).GetText()),
 		Port:     strings.TrimSpace(sf.Form.GetFormItem(3).(*tview.InputField).GetText()),
 		Key:      strings.TrimSpace(sf.Form.GetFormItem(4).(*tview.InputField).GetText()),
-		Password: strings.TrimSpace(sf.Form.GetFormItem(5).(*tview.InputField).GetText()), // Get password input
-		Tags:     strings.TrimSpace(sf.Form.GetFormItem(6).(*tview.InputField).GetText()),
+		Cert:     strings.TrimSpace(sf.Form.GetFormItem(5).(*tview.InputField).GetText()),
+		Password: strings.TrimSpace(sf.Form.GetFormItem(6).(*tview.InputField).GetText()), // Get password input
+		Tags:     strings.TrimSpace(sf.Form.GetFormItem(7).(*tview.InputField).GetText()),
+		Notes:    strings.TrimSpace(sf.Form.GetFormItem(8).(*tview.InputField).GetText()),
 	}
 }
 
@@ -130,13 +140,13 @@ func (sf *ServerForm) handleSave() {
 
 	if errMsg := validateServerForm(data); errMsg != "" {
 
-		sf.Form.SetTitle(fmt.Sprintf("%s — [red::b]%s[-]", sf.titleForMode(), errMsg))
-		sf.Form.SetBorderColor(tcell.ColorRed)
+		sf.Form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%s[-]", sf.titleForMode(), errMsg))
+		sf.Form.SetBorderColor(theme.color(theme.Error))
 		return
 	}
 
 	sf.Form.SetTitle(sf.titleForMode())
-	sf.Form.SetBorderColor(tcell.Color238)
+	sf.Form.SetBorderColor(theme.Border)
 
 	server := sf.dataToServer(data)
 	if sf.onSave != nil {
@@ -185,13 +195,15 @@ func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 	}
 
 	return domain.Server{
-		Alias:         data.Alias,
-		Host:          data.Host,
-		User:          data.User,
-		Port:          port,
-		IdentityFiles: keys,
-		Password:      password, // Only set if user entered a new password
-		Tags:          tags,
+		Alias:           data.Alias,
+		Host:            data.Host,
+		User:            data.User,
+		Port:            port,
+		IdentityFiles:   keys,
+		CertificateFile: data.Cert,
+		Password:        password, // Only set if user entered a new password
+		Tags:            tags,
+		Notes:           data.Notes,
 	}
 }
 
@@ -15,74 +15,468 @@
 package ui
 
 import (
+	"fmt"
+	"os"
+	"sort"
+
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// listRow is one line of the list: a group header when server is -1, otherwise a server
+// of group (or of the flat list when group is -1).
+type listRow struct {
+	group  int
+	server int
+}
+
 type ServerList struct {
-	*tview.List
+	*tview.Table
 	servers           []domain.Server
 	onSelection       func(domain.Server)
 	onSelectionChange func(domain.Server)
+	onGroupChange     func(domain.ServerGroup)
+	onSort            func(domain.ListColumn)
+	certificateStatus func(domain.Server) domain.CertificateStatus
+	reachability      func(alias string) (domain.Reachability, bool)
+	// highlights holds the characters matched by the current search, keyed by alias.
+	highlights map[string]domain.SearchHighlights
+
+	// columns are the fields shown for each server; widths caps some of them.
+	columns   []domain.ListColumn
+	widths    map[domain.ListColumn]int
+	sortOrder domain.SortOrder
+
+	// groupMode selects the tree view; groups and rows describe what is shown.
+	groupMode domain.GroupMode
+	groups    []domain.ServerGroup
+	collapsed map[string]bool
+	rows      []listRow
+	// current is the row under the cursor, -1 before anything was selected.
+	current int
+	// marked holds the aliases picked for batch operations.
+	marked map[string]bool
 }
 
 func NewServerList() *ServerList {
 	list := &ServerList{
-		List: tview.NewList(),
+		Table:     tview.NewTable(),
+		columns:   domain.DefaultListColumns,
+		collapsed: make(map[string]bool),
+		marked:    make(map[string]bool),
+		current:   -1,
 	}
 	list.build()
 	return list
 }
 
 func (sl *ServerList) build() {
-	sl.List.ShowSecondaryText(false)
-	sl.List.SetBorder(true).
+	sl.Table.SetBorder(true).
 		SetTitle("Servers").
-		SetBorderColor(tcell.Color238).
-		SetTitleColor(tcell.Color250)
-	sl.List.
-		SetSelectedBackgroundColor(tcell.Color24).
-		SetSelectedTextColor(tcell.Color255).
-		SetHighlightFullLine(true)
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+	sl.Table.
+		SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetEvaluateAllRows(true).
+		SetSelectedStyle(theme.SelectedStyle())
 
-	sl.List.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
-		if index >= 0 && index < len(sl.servers) && sl.onSelectionChange != nil {
-			sl.onSelectionChange(sl.servers[index])
+	sl.Table.SetSelectionChangedFunc(func(row, column int) {
+		if row-1 != sl.current {
+			sl.current = row - 1
+			sl.notifyChange(sl.current)
 		}
 	})
+	sl.Table.SetSelectedFunc(func(row, column int) {
+		if server, ok := sl.GetSelectedServer(); ok && sl.onSelection != nil {
+			sl.onSelection(server)
+		}
+	})
+	sl.render()
 }
 
 func (sl *ServerList) UpdateServers(servers []domain.Server) {
+	sl.highlights = nil
+	sl.update(servers)
+}
+
+// UpdateMatches shows search results, highlighting the matched characters.
+func (sl *ServerList) UpdateMatches(matches []domain.SearchMatch) {
+	servers := make([]domain.Server, 0, len(matches))
+	sl.highlights = make(map[string]domain.SearchHighlights, len(matches))
+	for _, match := range matches {
+		servers = append(servers, match.Server)
+		sl.highlights[match.Server.Alias] = match.Highlights
+	}
+	sl.update(servers)
+}
+
+func (sl *ServerList) update(servers []domain.Server) {
 	sl.servers = servers
-	sl.List.Clear()
-
-	for i := range servers {
-		primary, secondary := formatServerLine(servers[i])
-		idx := i
-		sl.List.AddItem(primary, secondary, 0, func() {
-			if sl.onSelection != nil {
-				sl.onSelection(sl.servers[idx])
+	home, _ := os.UserHomeDir()
+	sl.groups = domain.GroupServers(servers, sl.groupMode, home)
+	sl.render()
+
+	if sl.GetItemCount() > 0 {
+		sl.SetCurrentItem(0)
+		sl.notifyChange(0)
+	}
+}
+
+// GetItemCount returns the number of rows below the header.
+func (sl *ServerList) GetItemCount() int {
+	return len(sl.rows)
+}
+
+// GetCurrentItem returns the index of the row under the cursor, not counting the header.
+func (sl *ServerList) GetCurrentItem() int {
+	row, _ := sl.Table.GetSelection()
+	return row - 1
+}
+
+// SetCurrentItem moves the cursor to the row at index, not counting the header.
+func (sl *ServerList) SetCurrentItem(index int) {
+	sl.Table.Select(index+1, 0)
+}
+
+// render rebuilds the table from the servers or groups, leaving the cursor alone.
+func (sl *ServerList) render() {
+	sl.rows = sl.rows[:0]
+	if sl.groupMode == domain.GroupNone {
+		for i := range sl.servers {
+			sl.rows = append(sl.rows, listRow{group: -1, server: i})
+		}
+	} else {
+		for g, group := range sl.groups {
+			sl.rows = append(sl.rows, listRow{group: g, server: -1})
+			if sl.collapsed[group.Name] {
+				continue
+			}
+			for i := range group.Servers {
+				sl.rows = append(sl.rows, listRow{group: g, server: i})
+			}
+		}
+	}
+
+	sl.Table.Clear()
+	sl.renderHeader()
+	for i, row := range sl.rows {
+		if row.server < 0 {
+			// Group headers are drawn across the whole row by Draw.
+			for c := 0; c <= len(sl.columns); c++ {
+				sl.Table.SetCell(i+1, c, tview.NewTableCell(""))
+			}
+			continue
+		}
+		server := sl.serverAt(row)
+		sl.Table.SetCell(i+1, 0, tview.NewTableCell(sl.leadCell(server, row.group >= 0)))
+		for c, column := range sl.columns {
+			cell := tview.NewTableCell(sl.serverCell(server, column)).
+				SetAlign(columnSpecs[column].align)
+			sl.Table.SetCell(i+1, c+1, cell)
+		}
+	}
+}
+
+// renderHeader fills the header row, marking the column the list is sorted by.
+func (sl *ServerList) renderHeader() {
+	sl.Table.SetCell(0, 0, tview.NewTableCell("").SetSelectable(false))
+	for c, column := range sl.columns {
+		spec := columnSpecs[column]
+		title := spec.title
+		if field, ok := column.SortField(); ok && field == sl.sortOrder.Field {
+			if sl.sortOrder.Descending {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		column := column
+		cell := tview.NewTableCell("[" + colorTag(theme.Title) + "::b]" + title).
+			SetAlign(spec.align).
+			SetSelectable(false).
+			SetClickedFunc(func() bool {
+				if sl.onSort != nil {
+					sl.onSort(column)
+				}
+				return true
+			})
+		if c == len(sl.columns)-1 {
+			cell.SetExpansion(1)
+		}
+		sl.Table.SetCell(0, c+1, cell)
+	}
+}
+
+// leadCell shows the batch mark and the pin icon, indented inside groups.
+func (sl *ServerList) leadCell(server domain.Server, grouped bool) string {
+	lead := cellPad(pinnedIcon(server.PinnedAt), 2)
+	if len(sl.marked) > 0 {
+		if sl.marked[server.Alias] {
+			lead = "[" + theme.Warning + "::b]✓[-::-] " + lead
+		} else {
+			lead = "  " + lead
+		}
+	}
+	if grouped {
+		lead = "   " + lead
+	}
+	return lead
+}
+
+// Draw fits the columns to the width, then draws the table and the group headers over
+// their rows.
+func (sl *ServerList) Draw(screen tcell.Screen) {
+	_, _, width, height := sl.Table.GetInnerRect()
+	sl.fitColumns(width)
+	sl.Table.Draw(screen)
+
+	x, y, _, _ := sl.Table.GetInnerRect()
+	rowOffset, _ := sl.Table.GetOffset()
+	for line := 1; line < height; line++ {
+		index := rowOffset + line - 1
+		if index >= len(sl.rows) {
+			break
+		}
+		row := sl.rows[index]
+		if row.server >= 0 {
+			continue
+		}
+		// Reuse the style the table gave the row, so the cursor stays visible.
+		_, _, style, _ := screen.GetContent(x, y+line)
+		for i := 0; i < width; i++ {
+			screen.SetContent(x+i, y+line, ' ', nil, style)
+		}
+		tview.Print(screen, sl.formatGroupLine(sl.groups[row.group]), x, y+line, width, tview.AlignLeft, theme.Text)
+		if index == sl.current {
+			for i := 0; i < width; i++ {
+				m, c, _, _ := screen.GetContent(x+i, y+line)
+				screen.SetContent(x+i, y+line, m, c, style)
 			}
+		}
+	}
+}
+
+// MouseHandler gives focus to the list rather than to the embedded table, so that focus
+// checks against the list hold after a click.
+func (sl *ServerList) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	handler := sl.Table.MouseHandler()
+	return func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (bool, tview.Primitive) {
+		return handler(action, event, func(p tview.Primitive) {
+			if p == sl.Table {
+				p = sl
+			}
+			setFocus(p)
 		})
 	}
+}
 
-	if sl.List.GetItemCount() > 0 {
-		sl.List.SetCurrentItem(0)
-		if sl.onSelectionChange != nil {
-			sl.onSelectionChange(sl.servers[0])
+func (sl *ServerList) formatGroupLine(group domain.ServerGroup) string {
+	arrow := "▾"
+	if sl.collapsed[group.Name] {
+		arrow = "▸"
+	}
+	line := fmt.Sprintf("["+theme.Accent+"::b]%s %s[-::-] ["+theme.Dim+"](%d)[-]", arrow, tview.Escape(group.Name), len(group.Servers))
+	up, down := sl.groupReachability(group)
+	if up > 0 {
+		line += fmt.Sprintf("  ["+theme.Success+"]● %d up[-]", up)
+	}
+	if down > 0 {
+		line += fmt.Sprintf("  ["+theme.Error+"]● %d down[-]", down)
+	}
+	return line
+}
+
+// groupReachability counts the servers of group last pinged as up and as down.
+func (sl *ServerList) groupReachability(group domain.ServerGroup) (up, down int) {
+	if sl.reachability == nil {
+		return 0, 0
+	}
+	for _, server := range group.Servers {
+		if r, ok := sl.reachability(server.Alias); ok {
+			if r.Up {
+				up++
+			} else {
+				down++
+			}
+		}
+	}
+	return up, down
+}
+
+func (sl *ServerList) serverAt(row listRow) domain.Server {
+	if row.group < 0 {
+		return sl.servers[row.server]
+	}
+	return sl.groups[row.group].Servers[row.server]
+}
+
+func (sl *ServerList) notifyChange(index int) {
+	if index < 0 || index >= len(sl.rows) {
+		return
+	}
+	row := sl.rows[index]
+	if row.server < 0 {
+		if sl.onGroupChange != nil {
+			sl.onGroupChange(sl.groups[row.group])
 		}
+		return
+	}
+	if sl.onSelectionChange != nil {
+		sl.onSelectionChange(sl.serverAt(row))
 	}
 }
 
 func (sl *ServerList) GetSelectedServer() (domain.Server, bool) {
-	idx := sl.List.GetCurrentItem()
-	if idx >= 0 && idx < len(sl.servers) {
-		return sl.servers[idx], true
+	idx := sl.GetCurrentItem()
+	if idx >= 0 && idx < len(sl.rows) && sl.rows[idx].server >= 0 {
+		return sl.serverAt(sl.rows[idx]), true
 	}
 	return domain.Server{}, false
 }
 
+// SelectServer moves the cursor to the server called alias and reports whether it is shown.
+func (sl *ServerList) SelectServer(alias string) bool {
+	for i, row := range sl.rows {
+		if row.server >= 0 && sl.serverAt(row).Alias == alias {
+			sl.SetCurrentItem(i)
+			sl.notifyChange(i)
+			return true
+		}
+	}
+	return false
+}
+
+// GetSelectedGroup returns the group whose header is under the cursor, if any.
+func (sl *ServerList) GetSelectedGroup() (domain.ServerGroup, bool) {
+	idx := sl.GetCurrentItem()
+	if idx >= 0 && idx < len(sl.rows) && sl.rows[idx].server < 0 {
+		return sl.groups[sl.rows[idx].group], true
+	}
+	return domain.ServerGroup{}, false
+}
+
+// Servers returns the servers currently shown in the list, in display order.
+// Servers of collapsed groups are included.
+func (sl *ServerList) Servers() []domain.Server {
+	return sl.servers
+}
+
+// GroupMode returns how the list groups servers.
+func (sl *ServerList) GroupMode() domain.GroupMode {
+	return sl.groupMode
+}
+
+// SetGroupMode switches between the flat list and the tree grouped by mode.
+func (sl *ServerList) SetGroupMode(mode domain.GroupMode) {
+	if mode != sl.groupMode {
+		sl.collapsed = make(map[string]bool)
+	}
+	sl.groupMode = mode
+	sl.update(sl.servers)
+}
+
+// SetCollapsed folds or unfolds the group under the cursor, or the group of the server
+// under the cursor, leaving the cursor on the group header.
+func (sl *ServerList) SetCollapsed(collapsed bool) {
+	sl.setCollapsed(func(bool) bool { return collapsed })
+}
+
+// ToggleCollapsed folds the group under the cursor when it is open and unfolds it otherwise.
+func (sl *ServerList) ToggleCollapsed() {
+	sl.setCollapsed(func(current bool) bool { return !current })
+}
+
+func (sl *ServerList) setCollapsed(next func(bool) bool) {
+	idx := sl.GetCurrentItem()
+	if idx < 0 || idx >= len(sl.rows) || sl.rows[idx].group < 0 {
+		return
+	}
+	g := sl.rows[idx].group
+	name := sl.groups[g].Name
+	sl.collapsed[name] = next(sl.collapsed[name])
+	sl.render()
+	for i, row := range sl.rows {
+		if row.group == g && row.server < 0 {
+			sl.SetCurrentItem(i)
+			sl.notifyChange(i)
+			break
+		}
+	}
+}
+
+// Redraw refreshes the group headers, e.g. after new ping results, keeping the cursor.
+func (sl *ServerList) Redraw() {
+	idx := sl.GetCurrentItem()
+	sl.render()
+	if idx >= 0 && idx < sl.GetItemCount() {
+		sl.SetCurrentItem(idx)
+	}
+}
+
+// ToggleMark marks or unmarks the server under the cursor for batch operations, or every
+// server of the group under it, and moves the cursor to the next line.
+func (sl *ServerList) ToggleMark() {
+	idx := sl.GetCurrentItem()
+	if idx < 0 || idx >= len(sl.rows) {
+		return
+	}
+	var servers []domain.Server
+	if row := sl.rows[idx]; row.server < 0 {
+		servers = sl.groups[row.group].Servers
+	} else {
+		servers = []domain.Server{sl.serverAt(row)}
+	}
+	sl.SetMarked(servers, !sl.allMarked(servers))
+	if idx+1 < sl.GetItemCount() {
+		sl.SetCurrentItem(idx + 1)
+	}
+}
+
+// SetMarked marks or unmarks servers.
+func (sl *ServerList) SetMarked(servers []domain.Server, marked bool) {
+	for _, server := range servers {
+		if marked {
+			sl.marked[server.Alias] = true
+		} else {
+			delete(sl.marked, server.Alias)
+		}
+	}
+	sl.Redraw()
+}
+
+// AllMarked reports whether every listed server is marked.
+func (sl *ServerList) AllMarked() bool {
+	return sl.allMarked(sl.servers)
+}
+
+func (sl *ServerList) allMarked(servers []domain.Server) bool {
+	for _, server := range servers {
+		if !sl.marked[server.Alias] {
+			return false
+		}
+	}
+	return len(servers) > 0
+}
+
+// Marked returns the aliases marked for batch operations, sorted.
+// Marks survive searches, so they may include servers that are not listed.
+func (sl *ServerList) Marked() []string {
+	aliases := make([]string, 0, len(sl.marked))
+	for alias := range sl.marked {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// ClearMarks unmarks every server.
+func (sl *ServerList) ClearMarks() {
+	sl.marked = make(map[string]bool)
+	sl.Redraw()
+}
+
 func (sl *ServerList) OnSelection(fn func(server domain.Server)) *ServerList {
 	sl.onSelection = fn
 	return sl
@@ -92,3 +486,53 @@ func (sl *ServerList) OnSelectionChange(fn func(server domain.Server)) *ServerLi
 	sl.onSelectionChange = fn
 	return sl
 }
+
+// OnGroupChange sets the function called when the cursor moves onto a group header.
+func (sl *ServerList) OnGroupChange(fn func(group domain.ServerGroup)) *ServerList {
+	sl.onGroupChange = fn
+	return sl
+}
+
+// SetColumns selects the fields shown for each server, in order, keeping the cursor.
+func (sl *ServerList) SetColumns(columns []domain.ListColumn) *ServerList {
+	sl.columns = columns
+	sl.Redraw()
+	return sl
+}
+
+// SetWidths caps the width of columns; the others fit their content and the terminal.
+func (sl *ServerList) SetWidths(widths map[domain.ListColumn]int) *ServerList {
+	sl.widths = widths
+	return sl
+}
+
+// Widths returns the column width limits.
+func (sl *ServerList) Widths() map[domain.ListColumn]int {
+	return sl.widths
+}
+
+// SetSortOrder marks the column the list is sorted by in the header.
+func (sl *ServerList) SetSortOrder(order domain.SortOrder) *ServerList {
+	sl.sortOrder = order
+	sl.renderHeader()
+	return sl
+}
+
+// OnSort sets the function called when a column header is clicked.
+func (sl *ServerList) OnSort(fn func(column domain.ListColumn)) *ServerList {
+	sl.onSort = fn
+	return sl
+}
+
+// SetCertificateStatus sets the function used to flag servers with expiring or expired certificates.
+func (sl *ServerList) SetCertificateStatus(fn func(server domain.Server) domain.CertificateStatus) *ServerList {
+	sl.certificateStatus = fn
+	return sl
+}
+
+// SetReachability sets the function returning the last ping result of a server,
+// used to summarize groups.
+func (sl *ServerList) SetReachability(fn func(alias string) (domain.Reachability, bool)) *ServerList {
+	sl.reachability = fn
+	return sl
+}
@@ -0,0 +1,161 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/adapters/terminal"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// SessionTabs shows live terminal sessions as tabs.
+// Ctrl-] detaches back to the server list, Alt-←/→ and Alt-1..9 switch tabs.
+type SessionTabs struct {
+	*tview.Flex
+	tabBar   *tview.TextView
+	pages    *tview.Pages
+	views    []*TerminalView
+	current  int
+	onDetach func()
+}
+
+func NewSessionTabs() *SessionTabs {
+	tabs := &SessionTabs{
+		Flex:   tview.NewFlex(),
+		tabBar: tview.NewTextView(),
+		pages:  tview.NewPages(),
+	}
+	tabs.build()
+	return tabs
+}
+
+func (st *SessionTabs) build() {
+	st.tabBar.SetDynamicColors(true).SetRegions(false).SetWrap(false)
+	st.tabBar.SetBackgroundColor(theme.Header)
+
+	st.Flex.SetDirection(tview.FlexRow).
+		AddItem(st.tabBar, 1, 0, false).
+		AddItem(st.pages, 0, 1, true)
+
+	st.Flex.SetInputCapture(st.handleKeys)
+}
+
+func (st *SessionTabs) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyCtrlRightSq {
+		if st.onDetach != nil {
+			st.onDetach()
+		}
+		return nil
+	}
+	if event.Modifiers()&tcell.ModAlt == 0 {
+		return event
+	}
+	switch event.Key() {
+	case tcell.KeyRight:
+		st.Select(st.current + 1)
+		return nil
+	case tcell.KeyLeft:
+		st.Select(st.current - 1)
+		return nil
+	case tcell.KeyRune:
+		if r := event.Rune(); r >= '1' && r <= '9' {
+			st.Select(int(r - '1'))
+			return nil
+		}
+	}
+	return event
+}
+
+// Add appends a tab for session and makes it current.
+func (st *SessionTabs) Add(session *terminal.Session) {
+	view := NewTerminalView(session)
+	st.pages.AddPage(st.pageName(view), view, true, false)
+	st.views = append(st.views, view)
+	st.Select(len(st.views) - 1)
+}
+
+// Remove closes the tab showing session, if any.
+func (st *SessionTabs) Remove(session *terminal.Session) {
+	for i, view := range st.views {
+		if view.Session() != session {
+			continue
+		}
+		st.pages.RemovePage(st.pageName(view))
+		st.views = append(st.views[:i], st.views[i+1:]...)
+		if st.current >= len(st.views) {
+			st.current = len(st.views) - 1
+		}
+		st.Select(st.current)
+		return
+	}
+}
+
+// Select makes the i-th tab current, wrapping around at both ends.
+func (st *SessionTabs) Select(i int) {
+	if len(st.views) == 0 {
+		st.current = 0
+		st.renderTabBar()
+		return
+	}
+	st.current = (i + len(st.views)) % len(st.views)
+	st.pages.SwitchToPage(st.pageName(st.views[st.current]))
+	st.renderTabBar()
+}
+
+// Len returns the number of open tabs.
+func (st *SessionTabs) Len() int {
+	return len(st.views)
+}
+
+// Current returns the active terminal view, or nil when there are no tabs.
+func (st *SessionTabs) Current() *TerminalView {
+	if len(st.views) == 0 {
+		return nil
+	}
+	return st.views[st.current]
+}
+
+// CloseAll terminates every session.
+func (st *SessionTabs) CloseAll() {
+	for _, view := range st.views {
+		_ = view.Session().Close()
+	}
+}
+
+func (st *SessionTabs) OnDetach(fn func()) *SessionTabs {
+	st.onDetach = fn
+	return st
+}
+
+func (st *SessionTabs) pageName(view *TerminalView) string {
+	return fmt.Sprintf("session-%p", view)
+}
+
+func (st *SessionTabs) renderTabBar() {
+	parts := make([]string, 0, len(st.views))
+	for i, view := range st.views {
+		label := fmt.Sprintf(" %d %s ", i+1, view.Session().Title())
+		if i == st.current {
+			parts = append(parts, theme.chip(theme.Accent, "b")+label+"[-:-:-]")
+		} else {
+			parts = append(parts, "["+theme.Subtle+"]"+label+"[-]")
+		}
+	}
+	hint := "[" + theme.Dim + "]  Ctrl-] servers • Alt-←/→ switch[-]"
+	st.tabBar.SetText(strings.Join(parts, " ") + hint)
+}
@@ -15,116 +15,43 @@
 package ui
 
 import (
-	"sort"
-	"strings"
-
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
 )
 
-// SortMode controls how unpinned servers are ordered in the UI.
-type SortMode int
-
-const (
-	SortByAliasAsc SortMode = iota
-	SortByAliasDesc
-	SortByLastSeenDesc
-	SortByLastSeenAsc
-)
-
-func (m SortMode) String() string {
-	switch m {
-	case SortByAliasAsc:
-		return "Alias ↑"
-	case SortByAliasDesc:
-		return "Alias ↓"
-	case SortByLastSeenAsc:
-		return "Last SSH ↑"
-	case SortByLastSeenDesc:
-		return "Last SSH ↓"
-	default:
-		return "Alias ↑"
-	}
+// sortFieldLabels names the sort fields in the list title and status bar.
+var sortFieldLabels = map[domain.SortField]string{
+	domain.SortFieldAlias:        "Alias",
+	domain.SortFieldHost:         "Host",
+	domain.SortFieldLastSeen:     "Last SSH",
+	domain.SortFieldSSHCount:     "SSH count",
+	domain.SortFieldLatency:      "Latency",
+	domain.SortFieldReachability: "Reachability",
+	domain.SortFieldFrecency:     "Frecency",
 }
 
-// ToggleField switches between Alias and LastSeen while preserving direction.
-func (m SortMode) ToggleField() SortMode {
-	switch m {
-	case SortByAliasAsc:
-		return SortByLastSeenAsc
-	case SortByAliasDesc:
-		return SortByLastSeenDesc
-	case SortByLastSeenAsc:
-		return SortByAliasAsc
-	case SortByLastSeenDesc:
-		return SortByAliasDesc
-	default:
-		return SortByAliasAsc
+// sortLabel describes order for display, e.g. "Last SSH ↓".
+func sortLabel(order domain.SortOrder) string {
+	label, ok := sortFieldLabels[order.Field]
+	if !ok {
+		label = sortFieldLabels[domain.SortFieldAlias]
+	}
+	if order.Descending {
+		return label + " ↓"
 	}
+	return label + " ↑"
 }
 
-// Reverse flips the direction within the current field.
-func (m SortMode) Reverse() SortMode {
-	switch m {
-	case SortByAliasAsc:
-		return SortByAliasDesc
-	case SortByAliasDesc:
-		return SortByAliasAsc
-	case SortByLastSeenAsc:
-		return SortByLastSeenDesc
-	case SortByLastSeenDesc:
-		return SortByLastSeenAsc
-	default:
-		return SortByAliasAsc
+// sortFieldNames lists the sort fields accepted by the command palette.
+func sortFieldNames() []string {
+	names := make([]string, len(domain.SortFields))
+	for i, field := range domain.SortFields {
+		names[i] = string(field)
 	}
+	return names
 }
 
-// sortServersForUI sorts servers according to the rules required by the UI.
-// Pinned servers are always at the top, ordered by pinned date (newest first).
-// Unpinned servers are sorted by the selected mode. "Never" (zero time) goes to
-// the bottom when sorting by last seen asc/desc accordingly. Ties break by Alias asc.
-func sortServersForUI(servers []domain.Server, mode SortMode) {
-	sort.SliceStable(servers, func(i, j int) bool {
-		si, sj := servers[i], servers[j]
-
-		pi, pj := !si.PinnedAt.IsZero(), !sj.PinnedAt.IsZero()
-		if pi != pj {
-			return pi
-		}
-		if pi && pj { // both pinned: newer pinned first, tie-break alias
-			if !si.PinnedAt.Equal(sj.PinnedAt) {
-				return si.PinnedAt.After(sj.PinnedAt)
-			}
-			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
-		}
-
-		// both unpinned
-		switch mode {
-		case SortByLastSeenDesc, SortByLastSeenAsc:
-			zi := si.LastSeen.IsZero()
-			zj := sj.LastSeen.IsZero()
-			if zi != zj {
-				// when sorting by last seen, entries with zero (never) should be bottom in either direction
-				return !zi // non-zero first
-			}
-			if !zi && !zj && !si.LastSeen.Equal(sj.LastSeen) {
-				if mode == SortByLastSeenDesc {
-					return si.LastSeen.After(sj.LastSeen)
-				}
-				return si.LastSeen.Before(sj.LastSeen)
-			}
-			// tie-break by alias asc
-			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
-		case SortByAliasAsc:
-			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
-		case SortByAliasDesc:
-			ai := strings.ToLower(si.Alias)
-			aj := strings.ToLower(sj.Alias)
-			if ai != aj {
-				return ai > aj
-			}
-			return false
-		default:
-			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
-		}
-	})
+// sortsByReachability reports whether order depends on ping results, so the list has
+// to be re-sorted when they arrive.
+func sortsByReachability(order domain.SortOrder) bool {
+	return order.Field == domain.SortFieldReachability || order.Field == domain.SortFieldLatency
 }
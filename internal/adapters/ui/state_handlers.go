@@ -0,0 +1,58 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import "github.com/ChengzeHsiao/dogssh/internal/core/domain"
+
+// loadUIState returns the selection, sort and search of the last run.
+func (t *tui) loadUIState() domain.UIState {
+	if t.uiState == nil {
+		return domain.UIState{}
+	}
+	state, err := t.uiState.Load()
+	if err != nil {
+		t.logger.Warnw("failed to load UI state", "error", err)
+	}
+	return state
+}
+
+// restoreUIState reapplies the search and selection of the last run. A startup view
+// takes precedence over the remembered search.
+func (t *tui) restoreUIState(state domain.UIState) {
+	if state.Search != "" && t.activeView.Name == "" {
+		t.revealSearchBar()
+		t.searchBar.SetText(state.Search)
+	}
+	if state.Selected != "" {
+		t.serverList.SelectServer(state.Selected)
+	}
+}
+
+// saveUIState remembers the selection, sort and ad-hoc search for the next run.
+func (t *tui) saveUIState() {
+	if t.uiState == nil {
+		return
+	}
+	state := domain.UIState{Sort: t.sortOrder}
+	if server, ok := t.serverList.GetSelectedServer(); ok {
+		state.Selected = server.Alias
+	}
+	if t.searchVisible && t.activeView.Name == "" {
+		state.Search = t.searchBar.GetText()
+	}
+	if err := t.uiState.Save(state); err != nil {
+		t.logger.Warnw("failed to save UI state", "error", err)
+	}
+}
@@ -15,18 +15,13 @@
 package ui
 
 import (
-	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-func DefaultStatusText() string {
-	return "[white]↑↓[-] Navigate  • [white]Enter[-] SSH  • [white]c[-] Copy SSH  • [white]a[-] Add  • [white]e[-] Edit  • [white]g[-] Ping  • [white]d[-] Delete  • [white]p[-] Pin/Unpin  • [white]/[-] Search  • [white]q[-] Quit"
-}
-
-func NewStatusBar() *tview.TextView {
+func NewStatusBar(text string) *tview.TextView {
 	status := tview.NewTextView().SetDynamicColors(true)
-	status.SetBackgroundColor(tcell.Color235)
+	status.SetBackgroundColor(theme.Bar)
 	status.SetTextAlign(tview.AlignCenter)
-	status.SetText(DefaultStatusText())
+	status.SetText(text)
 	return status
 }
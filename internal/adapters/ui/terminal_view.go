@@ -0,0 +1,206 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"unicode/utf8"
+
+	"github.com/ChengzeHsiao/dogssh/internal/adapters/terminal"
+	"github.com/gdamore/tcell/v2"
+	"github.com/hinshun/vt10x"
+	"github.com/rivo/tview"
+)
+
+// Glyph attribute bits as used by vt10x.
+const (
+	glyphReverse = 1 << iota
+	glyphUnderline
+	glyphBold
+	_ // graphics charset
+	glyphItalic
+	glyphBlink
+)
+
+// TerminalView renders a terminal session and forwards keys to it.
+type TerminalView struct {
+	*tview.Box
+	session    *terminal.Session
+	cols, rows int
+}
+
+func NewTerminalView(session *terminal.Session) *TerminalView {
+	return &TerminalView{
+		Box:     tview.NewBox(),
+		session: session,
+	}
+}
+
+// Session returns the session shown by this view.
+func (tv *TerminalView) Session() *terminal.Session {
+	return tv.session
+}
+
+// Draw renders the emulator screen and resizes the session to match the view.
+func (tv *TerminalView) Draw(screen tcell.Screen) {
+	tv.Box.DrawForSubclass(screen, tv)
+	x, y, width, height := tv.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	if width != tv.cols || height != tv.rows {
+		tv.cols, tv.rows = width, height
+		_ = tv.session.Resize(width, height)
+	}
+
+	view := tv.session.View()
+	if view == nil {
+		return
+	}
+	view.Lock()
+	defer view.Unlock()
+
+	cols, rows := view.Size()
+	for row := 0; row < height && row < rows; row++ {
+		for col := 0; col < width && col < cols; col++ {
+			glyph := view.Cell(col, row)
+			ch := glyph.Char
+			if ch == 0 {
+				ch = ' '
+			}
+			screen.SetContent(x+col, y+row, ch, nil, glyphStyle(glyph))
+		}
+	}
+
+	if tv.HasFocus() && view.CursorVisible() {
+		cursor := view.Cursor()
+		if cursor.X < width && cursor.Y < height {
+			screen.ShowCursor(x+cursor.X, y+cursor.Y)
+		}
+	}
+}
+
+// InputHandler forwards key presses to the session as terminal input.
+func (tv *TerminalView) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return tv.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		appCursor := false
+		if view := tv.session.View(); view != nil {
+			appCursor = view.Mode()&vt10x.ModeAppCursor != 0
+		}
+		if data := encodeKey(event, appCursor); len(data) > 0 {
+			_, _ = tv.session.Write(data)
+		}
+	})
+}
+
+// PasteHandler forwards pasted text to the session unchanged.
+func (tv *TerminalView) PasteHandler() func(text string, setFocus func(p tview.Primitive)) {
+	return tv.WrapPasteHandler(func(text string, setFocus func(p tview.Primitive)) {
+		_, _ = tv.session.Write([]byte(text))
+	})
+}
+
+func glyphStyle(g vt10x.Glyph) tcell.Style {
+	fg, bg := vtColor(g.FG), vtColor(g.BG)
+	if g.Mode&glyphReverse != 0 {
+		fg, bg = bg, fg
+		if fg == tcell.ColorDefault {
+			fg = tview.Styles.PrimitiveBackgroundColor
+		}
+		if bg == tcell.ColorDefault {
+			bg = tview.Styles.PrimaryTextColor
+		}
+	}
+	return tcell.StyleDefault.
+		Foreground(fg).
+		Background(bg).
+		Bold(g.Mode&glyphBold != 0).
+		Italic(g.Mode&glyphItalic != 0).
+		Underline(g.Mode&glyphUnderline != 0).
+		Blink(g.Mode&glyphBlink != 0)
+}
+
+// vtColor maps vt10x colors (palette, 24-bit RGB or default) to tcell colors.
+func vtColor(c vt10x.Color) tcell.Color {
+	switch {
+	case c >= vt10x.DefaultFG:
+		return tcell.ColorDefault
+	case c < 256:
+		return tcell.PaletteColor(int(c))
+	default:
+		return tcell.NewHexColor(int32(c))
+	}
+}
+
+// keySequences maps special keys to the xterm sequences a remote shell expects.
+var keySequences = map[tcell.Key]string{
+	tcell.KeyBacktab: "\x1b[Z",
+	tcell.KeyHome:    "\x1b[H",
+	tcell.KeyEnd:     "\x1b[F",
+	tcell.KeyInsert:  "\x1b[2~",
+	tcell.KeyDelete:  "\x1b[3~",
+	tcell.KeyPgUp:    "\x1b[5~",
+	tcell.KeyPgDn:    "\x1b[6~",
+	tcell.KeyF1:      "\x1bOP",
+	tcell.KeyF2:      "\x1bOQ",
+	tcell.KeyF3:      "\x1bOR",
+	tcell.KeyF4:      "\x1bOS",
+	tcell.KeyF5:      "\x1b[15~",
+	tcell.KeyF6:      "\x1b[17~",
+	tcell.KeyF7:      "\x1b[18~",
+	tcell.KeyF8:      "\x1b[19~",
+	tcell.KeyF9:      "\x1b[20~",
+	tcell.KeyF10:     "\x1b[21~",
+	tcell.KeyF11:     "\x1b[23~",
+	tcell.KeyF12:     "\x1b[24~",
+}
+
+var arrowKeys = map[tcell.Key]byte{
+	tcell.KeyUp:    'A',
+	tcell.KeyDown:  'B',
+	tcell.KeyRight: 'C',
+	tcell.KeyLeft:  'D',
+}
+
+// encodeKey converts a tcell key event into the bytes a terminal would send.
+// appCursor selects application cursor mode (DECCKM) for arrow keys.
+func encodeKey(event *tcell.EventKey, appCursor bool) []byte {
+	var data []byte
+	key := event.Key()
+
+	switch {
+	case key == tcell.KeyRune:
+		buf := make([]byte, utf8.UTFMax)
+		n := utf8.EncodeRune(buf, event.Rune())
+		data = buf[:n]
+	case key < tcell.KeyRune && key <= 0x7f:
+		// Control keys, Enter, Tab, Esc and Backspace map directly to ASCII.
+		data = []byte{byte(key)}
+	default:
+		if final, ok := arrowKeys[key]; ok {
+			if appCursor {
+				data = []byte{0x1b, 'O', final}
+			} else {
+				data = []byte{0x1b, '[', final}
+			}
+		} else if seq, ok := keySequences[key]; ok {
+			data = []byte(seq)
+		}
+	}
+
+	if len(data) > 0 && event.Modifiers()&tcell.ModAlt != 0 {
+		data = append([]byte{0x1b}, data...)
+	}
+	return data
+}
@@ -0,0 +1,268 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Theme holds the colors of the UI. Panel colors are tcell colors; text colors go into
+// tview color tags, so they are strings such as "#FF6B6B", with "-" for the terminal's
+// default color.
+type Theme struct {
+	Name string
+
+	Background tcell.Color
+	// Bar is the background of the status bar, footers and the command line.
+	Bar tcell.Color
+	// Header is the background of the header and the tab bar.
+	Header tcell.Color
+	// Field is the background of input fields and the hint bar.
+	Field  tcell.Color
+	Border tcell.Color
+	Title  tcell.Color
+	Text   tcell.Color
+	// Muted is used for secondary text such as sizes, dates and placeholders.
+	Muted        tcell.Color
+	Selection    tcell.Color
+	SelectedText tcell.Color
+	// Danger is the background of warnings that need attention, such as a changed host key.
+	Danger tcell.Color
+
+	// Strong highlights values and key labels.
+	Strong string
+	// Accent colors tag chips, directories and the selected pane.
+	Accent  string
+	Error   string
+	Success string
+	Warning string
+	// Dim is used for hints and timestamps; Subtle for host names and other secondary values.
+	Dim    string
+	Subtle string
+	// ChipText is the text color on tag chips.
+	ChipText string
+
+	// monochrome themes use no colors at all and show selections in reverse video.
+	monochrome bool
+}
+
+// theme is the active theme. Like tview.Styles it is set once, before the UI is built.
+var theme = darkTheme()
+
+func darkTheme() Theme {
+	return Theme{
+		Name:         string(domain.ThemeDark),
+		Background:   tcell.Color232,
+		Bar:          tcell.Color235,
+		Header:       tcell.Color234,
+		Field:        tcell.Color233,
+		Border:       tcell.Color238,
+		Title:        tcell.Color250,
+		Text:         tcell.Color252,
+		Muted:        tcell.Color245,
+		Selection:    tcell.Color24,
+		SelectedText: tcell.Color255,
+		Danger:       tcell.ColorDarkRed,
+		Strong:       "white",
+		Accent:       "#5FAFFF",
+		Error:        "#FF6B6B",
+		Success:      "#A0FFA0",
+		Warning:      "#FFAF00",
+		Dim:          "#888888",
+		Subtle:       "#AAAAAA",
+		ChipText:     "black",
+	}
+}
+
+func lightTheme() Theme {
+	return Theme{
+		Name:         string(domain.ThemeLight),
+		Background:   tcell.Color255,
+		Bar:          tcell.Color253,
+		Header:       tcell.Color254,
+		Field:        tcell.Color254,
+		Border:       tcell.Color248,
+		Title:        tcell.Color238,
+		Text:         tcell.Color235,
+		Muted:        tcell.Color242,
+		Selection:    tcell.Color153,
+		SelectedText: tcell.Color16,
+		Danger:       tcell.Color224,
+		Strong:       "black",
+		Accent:       "#005FAF",
+		Error:        "#D70000",
+		Success:      "#008700",
+		Warning:      "#AF5F00",
+		Dim:          "#767676",
+		Subtle:       "#4E4E4E",
+		ChipText:     "white",
+	}
+}
+
+func highContrastTheme() Theme {
+	return Theme{
+		Name:         string(domain.ThemeHighContrast),
+		Background:   tcell.Color16,
+		Bar:          tcell.Color236,
+		Header:       tcell.Color16,
+		Field:        tcell.Color234,
+		Border:       tcell.Color252,
+		Title:        tcell.Color231,
+		Text:         tcell.Color231,
+		Muted:        tcell.Color252,
+		Selection:    tcell.Color226,
+		SelectedText: tcell.Color16,
+		Danger:       tcell.Color160,
+		Strong:       "#FFFF00",
+		Accent:       "#5FD7FF",
+		Error:        "#FF5F5F",
+		Success:      "#5FFF5F",
+		Warning:      "#FFD700",
+		Dim:          "#D0D0D0",
+		Subtle:       "#E4E4E4",
+		ChipText:     "black",
+	}
+}
+
+func monochromeTheme() Theme {
+	return Theme{
+		Name:         string(domain.ThemeMonochrome),
+		Background:   tcell.ColorDefault,
+		Bar:          tcell.ColorDefault,
+		Header:       tcell.ColorDefault,
+		Field:        tcell.ColorDefault,
+		Border:       tcell.ColorDefault,
+		Title:        tcell.ColorDefault,
+		Text:         tcell.ColorDefault,
+		Muted:        tcell.ColorDefault,
+		Selection:    tcell.ColorDefault,
+		SelectedText: tcell.ColorDefault,
+		Danger:       tcell.ColorDefault,
+		Strong:       "-",
+		Accent:       "-",
+		Error:        "-",
+		Success:      "-",
+		Warning:      "-",
+		Dim:          "-",
+		Subtle:       "-",
+		ChipText:     "-",
+		monochrome:   true,
+	}
+}
+
+// loadTheme returns the theme selected in settings with its color overrides applied.
+// noColor (the NO_COLOR convention) forces the monochrome theme. Invalid overrides are
+// skipped and returned.
+func loadTheme(settings domain.ThemeSettings, noColor bool) (Theme, []error) {
+	if noColor {
+		return monochromeTheme(), nil
+	}
+	var th Theme
+	switch settings.Preset {
+	case domain.ThemeLight:
+		th = lightTheme()
+	case domain.ThemeHighContrast:
+		th = highContrastTheme()
+	case domain.ThemeMonochrome:
+		return monochromeTheme(), nil
+	default:
+		th = darkTheme()
+	}
+
+	panels := map[string]*tcell.Color{
+		"background": &th.Background, "bar": &th.Bar, "header": &th.Header, "field": &th.Field,
+		"border": &th.Border, "title": &th.Title, "text": &th.Text, "muted": &th.Muted,
+		"selection": &th.Selection, "selected_text": &th.SelectedText, "danger": &th.Danger,
+	}
+	texts := map[string]*string{
+		"strong": &th.Strong, "accent": &th.Accent, "error": &th.Error, "success": &th.Success,
+		"warning": &th.Warning, "dim": &th.Dim, "subtle": &th.Subtle, "chip_text": &th.ChipText,
+	}
+
+	names := make([]string, 0, len(settings.Colors))
+	for name := range settings.Colors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		value := strings.TrimSpace(settings.Colors[name])
+		color, ok := parseColor(value)
+		if !ok {
+			errs = append(errs, fmt.Errorf("theme color %s: invalid color %q", name, value))
+			continue
+		}
+		if field, ok := panels[name]; ok {
+			*field = color
+		} else if field, ok := texts[name]; ok {
+			*field = colorTag(color)
+		} else {
+			errs = append(errs, fmt.Errorf("unknown theme color %q", name))
+		}
+	}
+	return th, errs
+}
+
+// parseColor accepts a color name, #RRGGBB, a 256-color palette index or "default".
+func parseColor(value string) (tcell.Color, bool) {
+	if value == "default" || value == "-" {
+		return tcell.ColorDefault, true
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 0 || n > 255 {
+			return tcell.ColorDefault, false
+		}
+		return tcell.PaletteColor(n), true
+	}
+	color := tcell.GetColor(strings.ToLower(value))
+	return color, color != tcell.ColorDefault
+}
+
+// colorTag returns the tview tag spelling of color.
+func colorTag(color tcell.Color) string {
+	if hex := color.Hex(); hex >= 0 {
+		return fmt.Sprintf("#%06X", hex)
+	}
+	return "-"
+}
+
+// color converts a text color of the theme for widgets that take a tcell color.
+func (th Theme) color(tag string) tcell.Color {
+	color, _ := parseColor(tag)
+	return color
+}
+
+// SelectedStyle is the style of the selected row in lists and tables.
+func (th Theme) SelectedStyle() tcell.Style {
+	if th.monochrome {
+		return tcell.StyleDefault.Reverse(true)
+	}
+	return tcell.StyleDefault.Background(th.Selection).Foreground(th.SelectedText)
+}
+
+// chip opens a tag chip drawn on background; close it with "[-:-:-]".
+func (th Theme) chip(background, attrs string) string {
+	if th.monochrome {
+		return "[::r" + attrs + "]"
+	}
+	return "[" + th.ChipText + ":" + background + ":" + attrs + "]"
+}
@@ -0,0 +1,156 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+func (t *tui) handleTransfersShow() {
+	if t.transfers.Active() || t.transferSeq > 0 {
+		t.showTransfers()
+		return
+	}
+	if len(t.serverList.Servers()) == 0 {
+		return
+	}
+	t.showTransferForm()
+}
+
+func (t *tui) showTransfers() {
+	t.app.SetRoot(t.transfers, true)
+	t.app.SetFocus(t.transfers)
+}
+
+func (t *tui) handleTransfersClose() {
+	t.returnToMain()
+	t.app.SetFocus(t.serverList)
+}
+
+// handleTransfersCancel stops every running batch; running jobs end as failed.
+func (t *tui) handleTransfersCancel() {
+	for _, cancel := range t.transferCancels {
+		cancel()
+	}
+	t.transferCancels = nil
+}
+
+// showTransferForm asks for a push or pull and the servers to run it on.
+func (t *tui) showTransferForm() {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle("New Transfer").
+		SetTitleAlign(tview.AlignLeft)
+
+	scopes := []string{t.selectedScopeLabel(), fmt.Sprintf("All listed servers (%d)", len(t.serverList.Servers()))}
+	form.AddDropDown("Direction:", []string{"Push (local → servers)", "Pull (servers → local)"}, 0, nil)
+	form.AddInputField("Source:", "", 50, nil, nil)
+	form.AddInputField("Destination dir:", "", 50, nil, nil)
+	form.AddDropDown("Run on:", scopes, runScopeSelected, nil)
+	form.AddInputField("Only tag (optional):", "", 20, nil, nil)
+	form.AddInputField("Concurrency:", "4", 6, nil, nil)
+	form.AddInputField("Retries:", "2", 6, nil, nil)
+	form.AddCheckbox("Verify checksums:", true, nil)
+
+	back := func() {
+		if t.transferSeq > 0 {
+			t.showTransfers()
+			return
+		}
+		t.returnToMain()
+	}
+	form.AddButton("Queue", func() {
+		direction, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		source := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		dest := strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText())
+		scope, _ := form.GetFormItem(3).(*tview.DropDown).GetCurrentOption()
+		tag := strings.TrimSpace(form.GetFormItem(4).(*tview.InputField).GetText())
+		concurrency, errC := strconv.Atoi(strings.TrimSpace(form.GetFormItem(5).(*tview.InputField).GetText()))
+		retries, errR := strconv.Atoi(strings.TrimSpace(form.GetFormItem(6).(*tview.InputField).GetText()))
+		verify := form.GetFormItem(7).(*tview.Checkbox).IsChecked()
+
+		var aliases []string
+		if scope == runScopeSelected {
+			aliases = t.selectedAliases(tag)
+		} else {
+			aliases = clusterAliases(t.serverList.Servers(), tag)
+		}
+
+		switch {
+		case source == "" || dest == "":
+			form.SetTitle("New Transfer — [" + theme.Error + "::b]source and destination are required[-]")
+		case errC != nil || concurrency < 1:
+			form.SetTitle("New Transfer — [" + theme.Error + "::b]concurrency must be a positive number[-]")
+		case errR != nil || retries < 0:
+			form.SetTitle("New Transfer — [" + theme.Error + "::b]retries must be zero or more[-]")
+		case len(aliases) == 0:
+			form.SetTitle("New Transfer — [" + theme.Error + "::b]no matching servers[-]")
+		default:
+			var jobs []domain.TransferJob
+			if direction == 0 {
+				jobs = domain.PushJobs(aliases, source, dest)
+			} else {
+				jobs = domain.PullJobs(aliases, source, dest)
+			}
+			t.queueTransfers(jobs, domain.TransferOptions{Concurrency: concurrency, Retries: retries, Verify: verify})
+		}
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// queueTransfers adds jobs to the queue panel and runs them in the background.
+func (t *tui) queueTransfers(jobs []domain.TransferJob, opts domain.TransferOptions) {
+	for i := range jobs {
+		t.transferSeq++
+		jobs[i].ID = t.transferSeq
+	}
+	t.transfers.Add(jobs)
+	t.showTransfers()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.transferCancels = append(t.transferCancels, cancel)
+
+	go func() {
+		results := t.transferService.Run(ctx, jobs, opts, func(job domain.TransferJob) {
+			t.app.QueueUpdateDraw(func() {
+				t.transfers.Update(job)
+			})
+		})
+		failed := 0
+		for _, job := range results {
+			if job.State == domain.TransferFailed {
+				failed++
+			}
+		}
+		t.app.QueueUpdateDraw(func() {
+			msg := fmt.Sprintf("Transfers finished: %d ok, %d failed", len(results)-failed, failed)
+			if failed > 0 {
+				t.showStatusTempColor(msg, theme.Error)
+			} else {
+				t.showStatusTemp(msg)
+			}
+		})
+	}()
+}
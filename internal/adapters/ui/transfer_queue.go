@@ -0,0 +1,194 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TransferQueueView lists push and pull jobs of this session with live progress.
+type TransferQueueView struct {
+	*tview.Flex
+	jobs     []domain.TransferJob
+	table    *tview.Table
+	footer   *tview.TextView
+	onNew    func()
+	onCancel func()
+	onClose  func()
+}
+
+func NewTransferQueueView() *TransferQueueView {
+	view := &TransferQueueView{
+		Flex:   tview.NewFlex(),
+		table:  tview.NewTable(),
+		footer: tview.NewTextView(),
+	}
+	view.build()
+	return view
+}
+
+func (v *TransferQueueView) build() {
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetTitle(" Transfers ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.Flex.SetInputCapture(v.handleKeys)
+	v.render()
+}
+
+func (v *TransferQueueView) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+		if v.onClose != nil {
+			v.onClose()
+		}
+		return nil
+	case event.Rune() == 'n':
+		if v.onNew != nil {
+			v.onNew()
+		}
+		return nil
+	case event.Rune() == 'X':
+		if v.onCancel != nil {
+			v.onCancel()
+		}
+		return nil
+	}
+	return event
+}
+
+// Add appends newly queued jobs.
+func (v *TransferQueueView) Add(jobs []domain.TransferJob) {
+	v.jobs = append(v.jobs, jobs...)
+	v.render()
+}
+
+// Update replaces the job with the same ID.
+func (v *TransferQueueView) Update(job domain.TransferJob) {
+	for i := range v.jobs {
+		if v.jobs[i].ID == job.ID {
+			v.jobs[i] = job
+			break
+		}
+	}
+	v.render()
+}
+
+// Active reports whether any job is still queued or running.
+func (v *TransferQueueView) Active() bool {
+	for _, job := range v.jobs {
+		if !job.Finished() {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *TransferQueueView) render() {
+	v.table.Clear()
+	for col, header := range []string{"#", "Host", "Dir", "Source", "Destination", "State", "Progress", "Tries", "Verified", "Time", "Error"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	counts := map[domain.TransferState]int{}
+	for i, job := range v.jobs {
+		counts[job.State]++
+		row := i + 1
+
+		progress := ""
+		switch {
+		case job.State == domain.TransferDone:
+			progress = formatBytes(job.Bytes)
+		case job.State == domain.TransferRunning && job.Progress.Percent() >= 0:
+			progress = fmt.Sprintf("%3d%% %s", job.Progress.Percent(), formatBytes(job.Progress.Done))
+		case job.State == domain.TransferRunning:
+			progress = formatBytes(job.Progress.Done)
+		}
+		verified := "-"
+		if job.Files >= 0 {
+			verified = fmt.Sprintf("%d files", job.Files)
+		}
+		duration := "-"
+		if job.Duration > 0 {
+			duration = job.Duration.Round(time.Millisecond).String()
+		}
+
+		v.table.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("%d", job.ID)).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 1, tview.NewTableCell(job.Alias))
+		v.table.SetCell(row, 2, tview.NewTableCell(string(job.Direction)))
+		v.table.SetCell(row, 3, tview.NewTableCell(tview.Escape(job.Source)).SetMaxWidth(30))
+		v.table.SetCell(row, 4, tview.NewTableCell(tview.Escape(job.Dest)).SetMaxWidth(30))
+		v.table.SetCell(row, 5, tview.NewTableCell(string(job.State)).SetTextColor(transferStateColor(job.State)))
+		v.table.SetCell(row, 6, tview.NewTableCell(progress).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 7, tview.NewTableCell(fmt.Sprintf("%d", job.Attempts)).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 8, tview.NewTableCell(verified).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 9, tview.NewTableCell(duration).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 10, tview.NewTableCell(tview.Escape(job.Error)).SetTextColor(theme.color(theme.Error)).SetExpansion(1))
+	}
+
+	v.footer.SetText(fmt.Sprintf(" %d queued  ["+theme.Warning+"]%d running[-]  ["+theme.Success+"]%d done[-]  ["+theme.Error+"]%d failed[-]  •  ["+theme.Strong+"]n[-] New transfer  • ["+theme.Strong+"]X[-] Cancel running  • ["+theme.Strong+"]Esc[-] Back",
+		counts[domain.TransferQueued], counts[domain.TransferRunning], counts[domain.TransferDone], counts[domain.TransferFailed]))
+
+	if len(v.jobs) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(min(max(row, 1), len(v.jobs)), 0)
+	}
+}
+
+func transferStateColor(state domain.TransferState) tcell.Color {
+	switch state {
+	case domain.TransferDone:
+		return theme.color(theme.Success)
+	case domain.TransferRunning:
+		return theme.color(theme.Warning)
+	case domain.TransferFailed:
+		return theme.color(theme.Error)
+	default:
+		return theme.Muted
+	}
+}
+
+func (v *TransferQueueView) OnNew(fn func()) *TransferQueueView {
+	v.onNew = fn
+	return v
+}
+
+func (v *TransferQueueView) OnCancel(fn func()) *TransferQueueView {
+	v.onCancel = fn
+	return v
+}
+
+func (v *TransferQueueView) OnClose(fn func()) *TransferQueueView {
+	v.onClose = fn
+	return v
+}
@@ -15,9 +15,15 @@
 package ui
 
 import (
-	"github.com/gdamore/tcell/v2"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"go.uber.org/zap"
 
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
 	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
 	"github.com/rivo/tview"
 )
@@ -32,8 +38,35 @@ type tui struct {
 	version string
 	commit  string
 
-	app           *tview.Application
-	serverService ports.ServerService
+	app               *tview.Application
+	serverService     ports.ServerService
+	tmuxService       ports.TmuxService
+	tunnelService     ports.TunnelService
+	fileService       ports.FileService
+	transferService   ports.TransferService
+	keyService        ports.KeyService
+	rotationService   ports.RotationService
+	knownHostsService ports.KnownHostsService
+	agentService      ports.AgentService
+	certService       ports.CertificateService
+	viewService       ports.ViewService
+	bulkEditService   ports.BulkEditService
+	commandHistory    ports.CommandHistoryService
+	uiState           ports.UIStateService
+	settings          domain.Settings
+	// settingsErr holds the problems found in config.yaml; the valid settings still apply.
+	settingsErr error
+
+	// actions are the commands of the server list; keymap binds them to keys and
+	// keyActions looks them up by key name.
+	actions    []action
+	keymap     map[string][]string
+	keyActions map[string]action
+	// settingsWarning reports invalid settings, theme colors or key bindings once the UI is up.
+	settingsWarning string
+	// commands are the commands of the ":" command line.
+	commands   []paletteCommand
+	commandBar *CommandBar
 
 	header     *AppHeader
 	searchBar  *SearchBar
@@ -41,22 +74,62 @@ type tui struct {
 	serverList *ServerList
 	details    *ServerDetails
 	statusBar  *tview.TextView
+	sessions   *SessionTabs
+	// tunnelsView is the open Tunnels panel, if any.
+	tunnelsView *TunnelsView
+	// keysView is the open Keys panel, if any.
+	keysView *KeysView
+	// knownHostsView is the open known_hosts panel, if any.
+	knownHostsView *KnownHostsView
+	// agentView is the open ssh-agent panel, if any.
+	agentView *AgentView
+	// viewsPanel is the saved views sidebar; views caches its entries in key order.
+	viewsPanel   *ViewsPanel
+	viewsVisible bool
+	views        []domain.SavedView
+	// activeView is the saved view filtering the server list, if any.
+	activeView domain.SavedView
+	// reachability holds the last ping result of each server, keyed by alias.
+	reachability map[string]domain.Reachability
+	// transfers keeps the push/pull queue of this session.
+	transfers       *TransferQueueView
+	transferSeq     int
+	transferCancels []context.CancelFunc
+	// lastEditPath prefills the remote edit form.
+	lastEditPath string
 
 	root    *tview.Flex
 	left    *tview.Flex
+	right   *tview.Flex
 	content *tview.Flex
 
-	sortMode      SortMode
+	sortOrder     domain.SortOrder
 	searchVisible bool
 }
 
-func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, version, commit string) App {
+func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, ts ports.TmuxService, tns ports.TunnelService, fs ports.FileService, trs ports.TransferService, ks ports.KeyService, rs ports.RotationService, khs ports.KnownHostsService, as ports.AgentService, cs ports.CertificateService, vs ports.ViewService, bes ports.BulkEditService, chs ports.CommandHistoryService, uss ports.UIStateService, settings domain.Settings, settingsErr error, version, commit string) App {
 	return &tui{
-		logger:        logger,
-		app:           tview.NewApplication(),
-		serverService: ss,
-		version:       version,
-		commit:        commit,
+		logger:            logger,
+		app:               tview.NewApplication(),
+		serverService:     ss,
+		tmuxService:       ts,
+		tunnelService:     tns,
+		fileService:       fs,
+		transferService:   trs,
+		keyService:        ks,
+		rotationService:   rs,
+		knownHostsService: khs,
+		agentService:      as,
+		certService:       cs,
+		viewService:       vs,
+		bulkEditService:   bes,
+		commandHistory:    chs,
+		uiState:           uss,
+		reachability:      make(map[string]domain.Reachability),
+		settings:          settings,
+		settingsErr:       settingsErr,
+		version:           version,
+		commit:            commit,
 	}
 }
 
@@ -70,38 +143,106 @@ func (t *tui) Run() error {
 	t.initializeTheme().buildComponents().buildLayout().bindEvents().loadInitialData()
 	t.app.SetRoot(t.root, true)
 	t.logger.Infow("starting TUI application", "version", t.version, "commit", t.commit)
+	// tview stops the application on Ctrl+C without going through handleQuit, so the
+	// cleanup runs here however the event loop ends.
+	defer t.shutdown()
 	if err := t.app.Run(); err != nil {
 		t.logger.Errorw("application run error", "error", err)
 		return err
 	}
+	t.saveUIState()
 	return nil
 }
 
+// shutdown closes embedded sessions, stops non-persistent tunnels and cancels transfers.
+func (t *tui) shutdown() {
+	t.sessions.CloseAll()
+	t.tunnelService.Shutdown()
+	t.handleTransfersCancel()
+}
+
 func (t *tui) initializeTheme() *tui {
-	tview.Styles.PrimitiveBackgroundColor = tcell.Color232
-	tview.Styles.ContrastBackgroundColor = tcell.Color235
-	tview.Styles.BorderColor = tcell.Color238
-	tview.Styles.TitleColor = tcell.Color250
-	tview.Styles.PrimaryTextColor = tcell.Color252
-	tview.Styles.TertiaryTextColor = tcell.Color245
-	tview.Styles.SecondaryTextColor = tcell.Color245
-	tview.Styles.GraphicsColor = tcell.Color238
+	if t.settingsErr != nil {
+		t.settingsWarning = settingsWarning(t.settingsErr)
+	}
+	th, errs := loadTheme(t.settings.Theme, os.Getenv("NO_COLOR") != "")
+	for _, err := range errs {
+		t.logger.Warnw("invalid theme setting", "error", err)
+	}
+	if len(errs) > 0 && t.settingsWarning == "" {
+		t.settingsWarning = "Theme: " + errs[0].Error()
+	}
+	theme = th
+
+	tview.Styles.PrimitiveBackgroundColor = theme.Background
+	tview.Styles.ContrastBackgroundColor = theme.Bar
+	tview.Styles.BorderColor = theme.Border
+	tview.Styles.TitleColor = theme.Title
+	tview.Styles.PrimaryTextColor = theme.Text
+	tview.Styles.TertiaryTextColor = theme.Muted
+	tview.Styles.SecondaryTextColor = theme.Muted
+	tview.Styles.GraphicsColor = theme.Border
+	tview.Styles.MoreContrastBackgroundColor = theme.Selection
+	tview.Styles.InverseTextColor = theme.Background
+	tview.Styles.ContrastSecondaryTextColor = theme.Muted
 	return t
 }
 
+// settingsWarning summarizes the problems found in config.yaml for the status bar.
+func settingsWarning(err error) string {
+	lines := strings.Split(err.Error(), "\n")
+	warning := "Settings: " + lines[0]
+	if len(lines) > 1 {
+		warning += fmt.Sprintf(" (and %d more, see the log)", len(lines)-1)
+	}
+	return warning
+}
+
 func (t *tui) buildComponents() *tui {
 	t.header = NewAppHeader(t.version, t.commit, RepoURL)
 	t.searchBar = NewSearchBar().
 		OnSearch(t.handleSearchInput).
 		OnEscape(t.hideSearchBar)
-	t.hintBar = NewHintBar()
+	t.buildActions()
+	if errs := t.loadKeymap(); len(errs) > 0 && t.settingsWarning == "" {
+		t.settingsWarning = "Key bindings: " + errs[0].Error() + " (press ? for the keymap)"
+	}
+	t.buildCommands()
+	t.hintBar = NewHintBar(t.hintText())
 	t.serverList = NewServerList().
-		OnSelectionChange(t.handleServerSelectionChange)
-	t.details = NewServerDetails()
-	t.statusBar = NewStatusBar()
+		OnSelectionChange(t.handleServerSelectionChange).
+		OnGroupChange(t.handleGroupSelectionChange).
+		SetColumns(t.settings.List.Columns).
+		SetWidths(t.settings.List.Widths).
+		OnSort(t.handleColumnSort).
+		SetCertificateStatus(t.certService.Status).
+		SetReachability(t.reachabilityOf)
+	t.details = NewServerDetails().
+		SetCommands(t.commandsText(false), t.commandsText(true))
+	t.viewsPanel = NewViewsPanel().
+		OnApply(t.applyView).
+		OnNew(func() { t.showViewForm(nil) }).
+		OnEdit(func(view domain.SavedView) { t.showViewForm(&view) }).
+		OnDelete(t.showViewDeleteModal).
+		OnClose(t.hideViewsPanel).
+		OnLeave(func() { t.app.SetFocus(t.serverList) })
+	t.statusBar = NewStatusBar(t.defaultStatusText())
+	t.commandBar = NewCommandBar().
+		OnComplete(t.completeCommand).
+		OnSubmit(t.handleCommandSubmit).
+		OnCancel(t.hideCommandBar)
+	t.sessions = NewSessionTabs().
+		OnDetach(t.handleSessionsDetach)
+	t.transfers = NewTransferQueueView().
+		OnNew(t.showTransferForm).
+		OnCancel(t.handleTransfersCancel).
+		OnClose(t.handleTransfersClose)
+	t.tunnelService.OnChange(func() {
+		// QueueUpdateDraw blocks until the update ran, and the change may come from the UI goroutine itself.
+		go t.app.QueueUpdateDraw(t.refreshTunnels)
+	})
 
-	// default sort mode
-	t.sortMode = SortByAliasAsc
+	t.sortOrder = t.settings.List.Sort
 
 	return t
 }
@@ -111,12 +252,11 @@ func (t *tui) buildLayout() *tui {
 		AddItem(t.hintBar, 1, 0, false).
 		AddItem(t.serverList, 0, 1, true)
 
-	right := tview.NewFlex().SetDirection(tview.FlexRow).
+	t.right = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(t.details, 0, 1, false)
 
-	t.content = tview.NewFlex().SetDirection(tview.FlexColumn).
-		AddItem(t.left, 0, 3, true).
-		AddItem(right, 0, 2, false)
+	t.content = tview.NewFlex().SetDirection(tview.FlexColumn)
+	t.layoutContent()
 
 	t.root = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(t.header, 2, 0, false).
@@ -127,20 +267,43 @@ func (t *tui) buildLayout() *tui {
 
 func (t *tui) bindEvents() *tui {
 	t.root.SetInputCapture(t.handleGlobalKeys)
+	t.app.SetInputCapture(t.handleAppKeys)
 	return t
 }
 
 func (t *tui) loadInitialData() *tui {
+	state := t.loadUIState()
+	if state.Sort.Field != "" {
+		t.sortOrder = state.Sort
+	}
 	servers, _ := t.serverService.ListServers("")
-	sortServersForUI(servers, t.sortMode)
+	domain.SortServers(servers, t.sortOrder, t.reachability)
 	t.updateListTitle()
 	t.serverList.UpdateServers(servers)
+	t.refreshViews()
+	t.applyStartupView()
+	t.restoreUIState(state)
+	if t.settingsWarning != "" {
+		t.showStatusTempColor(t.settingsWarning, theme.Error)
+	}
 
 	return t
 }
 
 func (t *tui) updateListTitle() {
 	if t.serverList != nil {
-		t.serverList.SetTitle("Servers — Sort: " + t.sortMode.String())
+		title := "Servers — "
+		if t.activeView.Name != "" {
+			title += "View: " + t.activeView.Name + " — "
+		}
+		if marked := len(t.serverList.Marked()); marked > 0 {
+			title += strconv.Itoa(marked) + " marked — "
+		}
+		if mode := t.serverList.GroupMode(); mode != domain.GroupNone {
+			title += "Group: " + mode.String() + " — "
+		}
+		title += "Sort: " + sortLabel(t.sortOrder)
+		t.serverList.SetTitle(title)
+		t.serverList.SetSortOrder(t.sortOrder)
 	}
 }
@@ -0,0 +1,258 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+var tunnelTypes = []domain.TunnelType{domain.TunnelLocal, domain.TunnelRemote, domain.TunnelDynamic}
+
+func (t *tui) handleTunnelsShow() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	t.showTunnels(server.Alias)
+}
+
+// showTunnels opens the Tunnels panel for alias and keeps it refreshed while visible.
+func (t *tui) showTunnels(alias string) {
+	view := NewTunnelsView(alias)
+	stop := make(chan struct{})
+
+	view.OnClose(func() {
+		close(stop)
+		t.tunnelsView = nil
+		t.returnToMain()
+		t.refreshServerList()
+	}).OnToggle(func(st domain.TunnelStatus) {
+		t.handleTunnelToggle(st)
+	}).OnAdd(func() {
+		t.showTunnelForm(alias, nil)
+	}).OnEdit(func(st domain.TunnelStatus) {
+		tunnel := st.Tunnel
+		t.showTunnelForm(alias, &tunnel)
+	}).OnDelete(func(st domain.TunnelStatus) {
+		t.showTunnelDeleteModal(alias, st.Tunnel)
+	})
+
+	t.tunnelsView = view
+	t.refreshTunnels()
+	t.app.SetRoot(view, true)
+	t.app.SetFocus(view)
+
+	// Connection counts and uptime change without a status event, so poll while open.
+	go func() {
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				t.app.QueueUpdateDraw(t.refreshTunnels)
+			}
+		}
+	}()
+}
+
+// refreshTunnels lists the selected server's tunnels followed by active tunnels of other servers.
+func (t *tui) refreshTunnels() {
+	view := t.tunnelsView
+	if view == nil {
+		return
+	}
+
+	var rows []domain.TunnelStatus
+	for _, tunnel := range t.serverTunnels(view.Alias()) {
+		rows = append(rows, t.tunnelService.Status(view.Alias(), tunnel))
+	}
+	for _, st := range t.tunnelService.Active() {
+		if st.Alias != view.Alias() {
+			rows = append(rows, st)
+		}
+	}
+	view.SetRows(rows)
+}
+
+func (t *tui) handleTunnelToggle(st domain.TunnelStatus) {
+	if st.State == domain.TunnelRunning || st.State == domain.TunnelRestarting {
+		if err := t.tunnelService.Stop(st.Alias, st.Tunnel.Name); err != nil {
+			t.showStatusTempColor(fmt.Sprintf("Tunnel %s: %v", st.Tunnel.Name, err), theme.Error)
+			return
+		}
+		t.showStatusTemp(fmt.Sprintf("Stopped tunnel %s", st.Tunnel.Name))
+		t.refreshTunnels()
+		return
+	}
+
+	name := st.Tunnel.Name
+	t.showStatusTemp(fmt.Sprintf("Starting tunnel %s…", name))
+	// ssh may take a moment to fail the forward, so don't block the UI.
+	go func() {
+		err := t.tunnelService.Start(st.Alias, st.Tunnel)
+		t.app.QueueUpdateDraw(func() {
+			if err != nil {
+				t.logger.Errorw("failed to start tunnel", "alias", st.Alias, "tunnel", name, "error", err)
+				t.showStatusTempColor(fmt.Sprintf("Tunnel %s: %v", name, err), theme.Error)
+			}
+			t.refreshTunnels()
+		})
+	}()
+}
+
+// serverTunnels returns the current tunnel definitions of alias.
+func (t *tui) serverTunnels(alias string) []domain.Tunnel {
+	servers, err := t.serverService.ListServers("")
+	if err != nil {
+		return nil
+	}
+	for _, server := range servers {
+		if server.Alias == alias {
+			return server.Tunnels
+		}
+	}
+	return nil
+}
+
+// saveTunnel adds tunnel to alias or replaces the definition named original.
+func (t *tui) saveTunnel(alias string, tunnel domain.Tunnel, original string) error {
+	current := t.serverTunnels(alias)
+	tunnels := make([]domain.Tunnel, 0, len(current)+1)
+	replaced := false
+	for _, existing := range current {
+		if original != "" && existing.Name == original {
+			tunnels = append(tunnels, tunnel)
+			replaced = true
+			continue
+		}
+		tunnels = append(tunnels, existing)
+	}
+	if !replaced {
+		tunnels = append(tunnels, tunnel)
+	}
+	return t.serverService.SetTunnels(alias, tunnels)
+}
+
+// showTunnelForm adds a tunnel to alias, or edits original when it is not nil.
+func (t *tui) showTunnelForm(alias string, original *domain.Tunnel) {
+	tunnel := domain.Tunnel{Type: domain.TunnelLocal, AutoRestart: true}
+	title := "Add Tunnel: " + alias
+	if original != nil {
+		tunnel = *original
+		title = "Edit Tunnel: " + alias
+	}
+
+	typeLabels := make([]string, len(tunnelTypes))
+	typeIndex := 0
+	for i, tt := range tunnelTypes {
+		typeLabels[i] = string(tt)
+		if tt == tunnel.Type {
+			typeIndex = i
+		}
+	}
+	port := func(p int) string {
+		if p == 0 {
+			return ""
+		}
+		return strconv.Itoa(p)
+	}
+
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	form.AddInputField("Name:", tunnel.Name, 30, nil, nil)
+	form.AddDropDown("Type:", typeLabels, typeIndex, nil)
+	form.AddInputField("Bind address (optional):", tunnel.BindAddress, 20, nil, nil)
+	form.AddInputField("Listen port:", port(tunnel.ListenPort), 6, nil, nil)
+	form.AddInputField("Target host:", tunnel.TargetHost, 30, nil, nil)
+	form.AddInputField("Target port:", port(tunnel.TargetPort), 6, nil, nil)
+	form.AddCheckbox("Auto-restart on drop:", tunnel.AutoRestart, nil)
+	form.AddCheckbox("Keep running after exit:", tunnel.Persistent, nil)
+
+	back := func() {
+		t.app.SetRoot(t.tunnelsView, true)
+		t.app.SetFocus(t.tunnelsView)
+	}
+	form.AddButton("Save", func() {
+		typeIdx, _ := form.GetFormItem(1).(*tview.DropDown).GetCurrentOption()
+		listenPort, _ := strconv.Atoi(strings.TrimSpace(form.GetFormItem(3).(*tview.InputField).GetText()))
+		targetPort, _ := strconv.Atoi(strings.TrimSpace(form.GetFormItem(5).(*tview.InputField).GetText()))
+
+		updated := domain.Tunnel{
+			Name:        strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()),
+			Type:        tunnelTypes[max(typeIdx, 0)],
+			BindAddress: strings.TrimSpace(form.GetFormItem(2).(*tview.InputField).GetText()),
+			ListenPort:  listenPort,
+			TargetHost:  strings.TrimSpace(form.GetFormItem(4).(*tview.InputField).GetText()),
+			TargetPort:  targetPort,
+			AutoRestart: form.GetFormItem(6).(*tview.Checkbox).IsChecked(),
+			Persistent:  form.GetFormItem(7).(*tview.Checkbox).IsChecked(),
+		}
+		if updated.Type == domain.TunnelDynamic {
+			updated.TargetHost, updated.TargetPort = "", 0
+		}
+
+		originalName := ""
+		if original != nil {
+			originalName = original.Name
+		}
+		if err := t.saveTunnel(alias, updated, originalName); err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%v[-]", title, err))
+			return
+		}
+		back()
+		t.refreshTunnels()
+		t.showStatusTemp(fmt.Sprintf("Saved tunnel %s", updated.Name))
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) showTunnelDeleteModal(alias string, tunnel domain.Tunnel) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete tunnel %s (%s) from %s?\n\nA running tunnel is stopped first.", tunnel.Name, tunnel.String(), alias)).
+		AddButtons([]string{"Cancel", "Confirm"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				_ = t.tunnelService.Stop(alias, tunnel.Name)
+				var tunnels []domain.Tunnel
+				for _, existing := range t.serverTunnels(alias) {
+					if existing.Name != tunnel.Name {
+						tunnels = append(tunnels, existing)
+					}
+				}
+				if err := t.serverService.SetTunnels(alias, tunnels); err != nil {
+					t.showStatusTempColor(fmt.Sprintf("Delete tunnel: %v", err), theme.Error)
+				}
+			}
+			t.app.SetRoot(t.tunnelsView, true)
+			t.app.SetFocus(t.tunnelsView)
+			t.refreshTunnels()
+		})
+
+	t.app.SetRoot(modal, true)
+}
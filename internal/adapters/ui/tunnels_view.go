@@ -0,0 +1,216 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TunnelsView lists the tunnels defined on a server together with every
+// active tunnel and lets the user start, stop and edit them.
+type TunnelsView struct {
+	*tview.Flex
+	alias    string
+	rows     []domain.TunnelStatus
+	table    *tview.Table
+	footer   *tview.TextView
+	onToggle func(domain.TunnelStatus)
+	onAdd    func()
+	onEdit   func(domain.TunnelStatus)
+	onDelete func(domain.TunnelStatus)
+	onClose  func()
+}
+
+func NewTunnelsView(alias string) *TunnelsView {
+	view := &TunnelsView{
+		Flex:   tview.NewFlex(),
+		alias:  alias,
+		table:  tview.NewTable(),
+		footer: tview.NewTextView(),
+	}
+	view.build()
+	return view
+}
+
+func (v *TunnelsView) build() {
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetTitle(" Tunnels: " + tview.Escape(v.alias) + " ").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+
+	v.footer.SetDynamicColors(true)
+	v.footer.SetBackgroundColor(theme.Bar)
+	v.footer.SetText(" [" + theme.Strong + "]Enter[-] Start/Stop  • [" + theme.Strong + "]a[-] Add  • [" + theme.Strong + "]e[-] Edit  • [" + theme.Strong + "]d[-] Delete  • [" + theme.Strong + "]Esc[-] Back")
+
+	v.Flex.SetDirection(tview.FlexRow).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.footer, 1, 0, false)
+
+	v.Flex.SetInputCapture(v.handleKeys)
+	v.render()
+}
+
+func (v *TunnelsView) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case event.Key() == tcell.KeyEsc || event.Rune() == 'q':
+		if v.onClose != nil {
+			v.onClose()
+		}
+		return nil
+	case event.Rune() == 'a':
+		if v.onAdd != nil {
+			v.onAdd()
+		}
+		return nil
+	case event.Key() == tcell.KeyEnter || event.Rune() == ' ':
+		if st, ok := v.Selected(); ok && v.onToggle != nil {
+			v.onToggle(st)
+		}
+		return nil
+	case event.Rune() == 'e':
+		if st, ok := v.Selected(); ok && st.Alias == v.alias && v.onEdit != nil {
+			v.onEdit(st)
+		}
+		return nil
+	case event.Rune() == 'd':
+		if st, ok := v.Selected(); ok && st.Alias == v.alias && v.onDelete != nil {
+			v.onDelete(st)
+		}
+		return nil
+	}
+	return event
+}
+
+// Alias returns the server whose tunnel definitions are shown.
+func (v *TunnelsView) Alias() string {
+	return v.alias
+}
+
+// Selected returns the highlighted tunnel.
+func (v *TunnelsView) Selected() (domain.TunnelStatus, bool) {
+	row, _ := v.table.GetSelection()
+	if row < 1 || row > len(v.rows) {
+		return domain.TunnelStatus{}, false
+	}
+	return v.rows[row-1], true
+}
+
+// SetRows replaces the listed tunnels and keeps the selection on the same tunnel.
+func (v *TunnelsView) SetRows(rows []domain.TunnelStatus) {
+	selected, hadSelection := v.Selected()
+	v.rows = rows
+	v.render()
+
+	if !hadSelection {
+		return
+	}
+	for i, r := range v.rows {
+		if r.Alias == selected.Alias && r.Tunnel.Name == selected.Tunnel.Name {
+			v.table.Select(i+1, 0)
+			break
+		}
+	}
+}
+
+func (v *TunnelsView) render() {
+	v.table.Clear()
+	for col, header := range []string{"Server", "Name", "Forward", "State", "PID", "Conns", "Restarts", "Uptime", "Last error"} {
+		v.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.Title).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+	}
+
+	for i, st := range v.rows {
+		row := i + 1
+		pid, conns, uptime := "-", "-", "-"
+		if st.PID > 0 {
+			pid = fmt.Sprintf("%d", st.PID)
+		}
+		if st.Connections >= 0 {
+			conns = fmt.Sprintf("%d", st.Connections)
+		}
+		if st.State == domain.TunnelRunning && !st.StartedAt.IsZero() {
+			uptime = time.Since(st.StartedAt).Round(time.Second).String()
+		}
+		flags := ""
+		if st.Tunnel.AutoRestart {
+			flags += " ↻"
+		}
+		if st.Tunnel.Persistent {
+			flags += " ⚓"
+		}
+
+		v.table.SetCell(row, 0, tview.NewTableCell(st.Alias))
+		v.table.SetCell(row, 1, tview.NewTableCell(st.Tunnel.Name+flags))
+		v.table.SetCell(row, 2, tview.NewTableCell(st.Tunnel.String()))
+		v.table.SetCell(row, 3, tview.NewTableCell(string(st.State)).SetTextColor(tunnelStateColor(st.State)))
+		v.table.SetCell(row, 4, tview.NewTableCell(pid).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 5, tview.NewTableCell(conns).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 6, tview.NewTableCell(fmt.Sprintf("%d", st.Restarts)).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 7, tview.NewTableCell(uptime).SetAlign(tview.AlignRight))
+		v.table.SetCell(row, 8, tview.NewTableCell(st.LastError).SetTextColor(theme.color(theme.Error)).SetExpansion(1))
+	}
+
+	if len(v.rows) > 0 {
+		row, _ := v.table.GetSelection()
+		v.table.Select(min(max(row, 1), len(v.rows)), 0)
+	}
+}
+
+func tunnelStateColor(state domain.TunnelState) tcell.Color {
+	switch state {
+	case domain.TunnelRunning:
+		return theme.color(theme.Success)
+	case domain.TunnelRestarting:
+		return theme.color(theme.Warning)
+	case domain.TunnelFailed:
+		return theme.color(theme.Error)
+	default:
+		return theme.Muted
+	}
+}
+
+func (v *TunnelsView) OnToggle(fn func(domain.TunnelStatus)) *TunnelsView {
+	v.onToggle = fn
+	return v
+}
+
+func (v *TunnelsView) OnAdd(fn func()) *TunnelsView {
+	v.onAdd = fn
+	return v
+}
+
+func (v *TunnelsView) OnEdit(fn func(domain.TunnelStatus)) *TunnelsView {
+	v.onEdit = fn
+	return v
+}
+
+func (v *TunnelsView) OnDelete(fn func(domain.TunnelStatus)) *TunnelsView {
+	v.onDelete = fn
+	return v
+}
+
+func (v *TunnelsView) OnClose(fn func()) *TunnelsView {
+	v.onClose = fn
+	return v
+}
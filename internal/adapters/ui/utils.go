@@ -16,17 +16,20 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
 	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
 )
 
 // renderTagBadgesForList renders up to two colored tag chips for the server list.
 // If there are more tags, it appends a subtle gray "+N" badge. Returns an empty
 // string when there are no tags to avoid cluttering the list.
-func renderTagBadgesForList(tags []string) string {
+func renderTagBadgesForList(tags []string, highlights map[string][]int) string {
 	if len(tags) == 0 {
 		return ""
 	}
@@ -37,11 +40,15 @@ func renderTagBadgesForList(tags []string) string {
 	}
 	parts := make([]string, 0, len(shown)+1)
 	for _, t := range shown {
-		// Light blue background chip, similar to details view.
-		parts = append(parts, fmt.Sprintf("[black:#5FAFFF] %s [-:-:-]", t))
+		// Accent-colored chip, similar to details view.
+		label := tview.Escape(t)
+		if offsets := highlights[t]; len(offsets) > 0 {
+			label = highlightMatches(t, offsets, "["+theme.Strong+"::bu]", "["+theme.ChipText+"::BU]", 0)
+		}
+		parts = append(parts, theme.chip(theme.Accent, "")+" "+label+" [-:-:-]")
 	}
 	if extra := len(tags) - len(shown); extra > 0 {
-		parts = append(parts, fmt.Sprintf("[#8A8A8A]+%d[-]", extra))
+		parts = append(parts, fmt.Sprintf("["+theme.Dim+"]+%d[-]", extra))
 	}
 	return strings.Join(parts, " ")
 }
@@ -64,12 +71,35 @@ func pinnedIcon(pinnedAt time.Time) string {
 	return "📌" // pinned
 }
 
-func formatServerLine(s domain.Server) (primary, secondary string) {
-	icon := cellPad(pinnedIcon(s.PinnedAt), 2)
-	// Use a consistent color for alias; the icon reflects pinning
-	primary = fmt.Sprintf("%s [white::b]%-12s[-] [#AAAAAA]%-18s[-] [#888888]Last SSH: %s[-]  %s", icon, s.Alias, s.Host, humanizeDuration(s.LastSeen), renderTagBadgesForList(s.Tags))
-	secondary = ""
-	return
+// highlightMatches wraps the runs of runes of text at offsets in the style tag hl,
+// switching back with restore after each run, and pads the result to width runes.
+// Each run is escaped as a whole, since tview.Escape only recognizes complete [...]
+// groups.
+func highlightMatches(text string, offsets []int, hl, restore string, width int) string {
+	runes := []rune(text)
+	matched := make([]bool, len(runes))
+	for _, offset := range offsets {
+		if offset >= 0 && offset < len(runes) {
+			matched[offset] = true
+		}
+	}
+	var b strings.Builder
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && matched[end] == matched[start] {
+			end++
+		}
+		segment := tview.Escape(string(runes[start:end]))
+		if matched[start] {
+			segment = hl + segment + restore
+		}
+		b.WriteString(segment)
+		start = end
+	}
+	if pad := width - len(runes); pad > 0 {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+	return b.String()
 }
 
 func humanizeDuration(t time.Time) string {
@@ -107,7 +137,7 @@ func humanizeDuration(t time.Time) string {
 }
 
 // BuildSSHCommand constructs a ready-to-run ssh command for the given server.
-// Format: ssh [user@]host [-p PORT if not 22] [-i KEY if provided]
+// Format: ssh [user@]host [-p PORT if not 22] [-i KEY if provided] [-o CertificateFile=CERT if provided]
 func BuildSSHCommand(s domain.Server) string {
 	parts := []string{"ssh"}
 	userHost := ""
@@ -127,6 +157,9 @@ func BuildSSHCommand(s domain.Server) string {
 	if len(s.IdentityFiles) > 0 {
 		parts = append(parts, "-i", quoteIfNeeded(s.IdentityFiles[0]))
 	}
+	if s.CertificateFile != "" {
+		parts = append(parts, "-o", quoteIfNeeded("CertificateFile="+s.CertificateFile))
+	}
 	return strings.Join(parts, " ")
 }
 
@@ -137,3 +170,15 @@ func quoteIfNeeded(val string) string {
 	}
 	return val
 }
+
+// expandHome replaces a leading ~ in a local path typed by the user with the home directory.
+func expandHome(p string) string {
+	if p != "~" && !strings.HasPrefix(p, "~/") {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	return filepath.Join(home, strings.TrimPrefix(p, "~"))
+}
@@ -0,0 +1,60 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+// displayed returns the text tview shows for s once style tags are applied.
+func displayed(s string) string {
+	view := tview.NewTextView().SetDynamicColors(true)
+	view.SetText(s)
+	return view.GetText(true)
+}
+
+func TestHighlightMatchesEscapesText(t *testing.T) {
+	tests := []struct {
+		text    string
+		offsets []int
+	}{
+		{"[red]web", []int{5, 6}},
+		{"[red]web", []int{1, 2, 3}},
+		{"db[::b]", []int{0}},
+		{"[red]", []int{0, 4}},
+		{"x[y]z", []int{2}},
+		{"plain", nil},
+	}
+	for _, tt := range tests {
+		got := highlightMatches(tt.text, tt.offsets, "[yellow::u]", "[white::U]", 0)
+		if shown := displayed(got); shown != tt.text {
+			t.Errorf("highlightMatches(%q, %v) = %q, shown as %q", tt.text, tt.offsets, got, shown)
+		}
+	}
+	if got := highlightMatches("ab", []int{1}, "<", ">", 4); got != "a<b>  " {
+		t.Errorf("highlightMatches padding = %q", got)
+	}
+}
+
+func TestTagBadgesEscapeTags(t *testing.T) {
+	for _, highlights := range []map[string][]int{nil, {"[red]": {1}}} {
+		got := displayed(renderTagBadgesForList([]string{"[red]"}, highlights))
+		if got != " [red] " {
+			t.Errorf("badge with highlights %v shown as %q", highlights, got)
+		}
+	}
+}
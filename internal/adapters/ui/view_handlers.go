@@ -0,0 +1,202 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/rivo/tview"
+)
+
+// viewsPanelWidth is the width of the saved views sidebar.
+const viewsPanelWidth = 30
+
+// handleViewsToggle opens the views sidebar, focuses it when open, or closes it when focused.
+func (t *tui) handleViewsToggle() {
+	switch {
+	case !t.viewsVisible:
+		t.viewsVisible = true
+		t.layoutContent()
+		t.refreshViews()
+		t.app.SetFocus(t.viewsPanel)
+	case t.app.GetFocus() != t.viewsPanel:
+		t.app.SetFocus(t.viewsPanel)
+	default:
+		t.hideViewsPanel()
+	}
+}
+
+func (t *tui) hideViewsPanel() {
+	t.viewsVisible = false
+	t.layoutContent()
+	t.app.SetFocus(t.serverList)
+}
+
+// layoutContent arranges the sidebar, server list and details in the content row.
+func (t *tui) layoutContent() {
+	t.content.Clear()
+	if t.viewsVisible {
+		t.content.AddItem(t.viewsPanel, viewsPanelWidth, 0, false)
+	}
+	t.content.AddItem(t.left, 0, 3, true).
+		AddItem(t.right, 0, 2, false)
+}
+
+// refreshViews reloads the saved views and recounts their matches.
+func (t *tui) refreshViews() {
+	views, err := t.viewService.List()
+	if err != nil {
+		t.logger.Warnw("failed to load saved views", "error", err)
+		t.showStatusTempColor(fmt.Sprintf("Saved views: %v", err), theme.Error)
+	}
+	t.views = views
+	servers, err := t.serverService.ListServers("")
+	if err != nil {
+		return
+	}
+	t.viewsPanel.SetViews(views, t.viewService.Counts(views, servers), len(servers))
+}
+
+// applyView filters the server list with saved view index, counted from 1; 0 shows all servers.
+func (t *tui) applyView(index int) {
+	if index < 0 || index > len(t.views) {
+		return
+	}
+	if index == 0 {
+		t.activeView = domain.SavedView{}
+		t.viewsPanel.SetActive("")
+		t.searchBar.SetText("")
+		if t.searchVisible {
+			t.hideSearchBar()
+		}
+		t.updateListTitle()
+		return
+	}
+
+	view := t.views[index-1]
+	t.activeView = view
+	t.viewsPanel.SetActive(view.Name)
+	t.revealSearchBar()
+	t.searchBar.SetText(view.Query)
+	t.updateListTitle()
+	if t.app.GetFocus() == t.searchBar {
+		t.app.SetFocus(t.serverList)
+	}
+}
+
+// applyViewByName applies the saved view called name and reports whether it exists.
+func (t *tui) applyViewByName(name string) bool {
+	for i, view := range t.views {
+		if strings.EqualFold(view.Name, name) {
+			t.applyView(i + 1)
+			return true
+		}
+	}
+	return false
+}
+
+// showViewForm creates a view from the last search, or edits previous when it is set.
+func (t *tui) showViewForm(previous *domain.SavedView) {
+	title := "New View"
+	name, query := "", t.searchBar.GetText()
+	if previous != nil {
+		title = "Edit View"
+		name, query = previous.Name, previous.Query
+	}
+
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(title).
+		SetTitleAlign(tview.AlignLeft)
+	form.AddInputField("Name:", name, 30, nil, nil)
+	form.AddInputField("Query:", query, 60, nil, nil)
+
+	back := func() {
+		t.returnToMain()
+		t.app.SetFocus(t.viewsPanel)
+	}
+	form.AddButton("Save", func() {
+		view := domain.SavedView{
+			Name:  form.GetFormItem(0).(*tview.InputField).GetText(),
+			Query: form.GetFormItem(1).(*tview.InputField).GetText(),
+		}
+		previousName := ""
+		if previous != nil {
+			previousName = previous.Name
+		}
+		if err := t.viewService.Save(previousName, view); err != nil {
+			form.SetTitle(fmt.Sprintf("%s — ["+theme.Error+"::b]%s[-]", title, tview.Escape(err.Error())))
+			return
+		}
+		back()
+		t.refreshViews()
+		if previous != nil && strings.EqualFold(t.activeView.Name, previous.Name) {
+			t.applyViewByName(strings.TrimSpace(view.Name))
+		}
+		t.showStatusTemp(fmt.Sprintf("Saved view %s", strings.TrimSpace(view.Name)))
+	})
+	form.AddButton("Cancel", back)
+	form.SetCancelFunc(back)
+
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) showViewDeleteModal(view domain.SavedView) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete view %s?\n\n%s", view.Name, view.Query)).
+		AddButtons([]string{"Cancel", "Delete"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.returnToMain()
+			t.app.SetFocus(t.viewsPanel)
+			if buttonIndex != 1 {
+				return
+			}
+			if err := t.viewService.Delete(view.Name); err != nil {
+				t.showStatusTempColor(fmt.Sprintf("Delete view: %v", err), theme.Error)
+				return
+			}
+			if strings.EqualFold(t.activeView.Name, view.Name) {
+				t.applyView(0)
+				t.app.SetFocus(t.viewsPanel)
+			}
+			t.refreshViews()
+			t.showStatusTemp(fmt.Sprintf("Deleted view %s", view.Name))
+		})
+	t.app.SetRoot(modal, true)
+}
+
+// applyStartupView applies the view named in the settings, if any.
+func (t *tui) applyStartupView() {
+	name := t.settings.Views.Startup
+	if name == "" {
+		return
+	}
+	if !t.applyViewByName(name) {
+		t.logger.Warnw("startup view not found", "view", name)
+		t.showStatusTempColor(fmt.Sprintf("Startup view %q not found", name), theme.Error)
+	}
+}
+
+// currentQuery returns the search applied to the server list: the search bar text while
+// it is visible or a saved view is active.
+func (t *tui) currentQuery() string {
+	if t.searchVisible || t.activeView.Name != "" {
+		return t.searchBar.GetText()
+	}
+	return ""
+}
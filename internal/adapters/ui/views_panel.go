@@ -0,0 +1,189 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ViewsPanel is the sidebar listing saved views with their match counts.
+// Row 0 stands for all servers; row n is the view reached with number key n.
+type ViewsPanel struct {
+	*tview.Table
+	views    []domain.SavedView
+	counts   []int
+	total    int
+	active   string
+	onApply  func(index int)
+	onNew    func()
+	onEdit   func(domain.SavedView)
+	onDelete func(domain.SavedView)
+	onClose  func()
+	onLeave  func()
+}
+
+func NewViewsPanel() *ViewsPanel {
+	panel := &ViewsPanel{
+		Table: tview.NewTable(),
+	}
+	panel.build()
+	return panel
+}
+
+func (p *ViewsPanel) build() {
+	p.Table.SetSelectable(true, false).
+		SetBorder(true).
+		SetTitle("Views").
+		SetBorderColor(theme.Border).
+		SetTitleColor(theme.Title)
+	p.Table.SetSelectedStyle(theme.SelectedStyle())
+	p.Table.SetSelectedFunc(func(row, _ int) {
+		if p.onApply != nil {
+			p.onApply(row)
+		}
+	})
+	p.Table.SetInputCapture(p.handleKeys)
+	p.render()
+}
+
+func (p *ViewsPanel) handleKeys(event *tcell.EventKey) *tcell.EventKey {
+	switch r := event.Rune(); {
+	case event.Key() == tcell.KeyEsc || r == 'v':
+		if p.onClose != nil {
+			p.onClose()
+		}
+		return nil
+	case event.Key() == tcell.KeyTab:
+		if p.onLeave != nil {
+			p.onLeave()
+		}
+		return nil
+	case r >= '0' && r <= '9':
+		if index := int(r - '0'); index <= len(p.views) && p.onApply != nil {
+			p.onApply(index)
+		}
+		return nil
+	case r == 'n':
+		if p.onNew != nil {
+			p.onNew()
+		}
+		return nil
+	case r == 'e':
+		if view, ok := p.Selected(); ok && p.onEdit != nil {
+			p.onEdit(view)
+		}
+		return nil
+	case r == 'd':
+		if view, ok := p.Selected(); ok && p.onDelete != nil {
+			p.onDelete(view)
+		}
+		return nil
+	}
+	return event
+}
+
+// Selected returns the highlighted saved view; false on the "All servers" row.
+func (p *ViewsPanel) Selected() (domain.SavedView, bool) {
+	row, _ := p.Table.GetSelection()
+	if row < 1 || row > len(p.views) {
+		return domain.SavedView{}, false
+	}
+	return p.views[row-1], true
+}
+
+// SetViews replaces the listed views; counts holds their matches and total the number of servers.
+func (p *ViewsPanel) SetViews(views []domain.SavedView, counts []int, total int) {
+	p.views = views
+	p.counts = counts
+	p.total = total
+	p.render()
+}
+
+// SetActive marks the view currently applied to the server list; "" means all servers.
+func (p *ViewsPanel) SetActive(name string) {
+	p.active = name
+	p.render()
+}
+
+func (p *ViewsPanel) render() {
+	p.Table.Clear()
+	p.setRow(0, "0", "All servers", fmt.Sprint(p.total), p.active == "")
+	for i, view := range p.views {
+		count := "?"
+		if i < len(p.counts) {
+			if p.counts[i] < 0 {
+				count = "[" + theme.Error + "]err[-]"
+			} else {
+				count = fmt.Sprint(p.counts[i])
+			}
+		}
+		key := ""
+		if i < 9 {
+			key = fmt.Sprint(i + 1)
+		}
+		p.setRow(i+1, key, view.Name, count, strings.EqualFold(view.Name, p.active))
+	}
+
+	row, _ := p.Table.GetSelection()
+	p.Table.Select(min(max(row, 0), len(p.views)), 0)
+}
+
+func (p *ViewsPanel) setRow(row int, key, name, count string, active bool) {
+	marker := " "
+	nameColor := theme.Text
+	if active {
+		marker = "▸"
+		nameColor = theme.color(theme.Accent)
+	}
+	p.Table.SetCell(row, 0, tview.NewTableCell(marker+key).SetTextColor(theme.Muted))
+	p.Table.SetCell(row, 1, tview.NewTableCell(tview.Escape(name)).SetTextColor(nameColor).SetExpansion(1).SetMaxWidth(20))
+	p.Table.SetCell(row, 2, tview.NewTableCell(count).SetTextColor(theme.Muted).SetAlign(tview.AlignRight))
+}
+
+func (p *ViewsPanel) OnApply(fn func(index int)) *ViewsPanel {
+	p.onApply = fn
+	return p
+}
+
+func (p *ViewsPanel) OnNew(fn func()) *ViewsPanel {
+	p.onNew = fn
+	return p
+}
+
+func (p *ViewsPanel) OnEdit(fn func(domain.SavedView)) *ViewsPanel {
+	p.onEdit = fn
+	return p
+}
+
+func (p *ViewsPanel) OnDelete(fn func(domain.SavedView)) *ViewsPanel {
+	p.onDelete = fn
+	return p
+}
+
+func (p *ViewsPanel) OnClose(fn func()) *ViewsPanel {
+	p.onClose = fn
+	return p
+}
+
+// OnLeave is called when Tab moves focus back to the server list, keeping the panel open.
+func (p *ViewsPanel) OnLeave(fn func()) *ViewsPanel {
+	p.onLeave = fn
+	return p
+}
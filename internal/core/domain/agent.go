@@ -0,0 +1,38 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "errors"
+
+// ErrAgentUnavailable is returned when SSH_AUTH_SOCK is unset or nothing listens on it.
+var ErrAgentUnavailable = errors.New("ssh-agent is not running (SSH_AUTH_SOCK is not set)")
+
+// AgentIdentity is a key held by the running ssh-agent.
+type AgentIdentity struct {
+	Type        string
+	Fingerprint string
+	Comment     string
+	// Blob is the wire encoding of the public key, used to remove it again.
+	Blob []byte
+}
+
+// AgentKeyStatus tells whether one of a server's identity files is loaded in ssh-agent.
+type AgentKeyStatus struct {
+	Path      string
+	Loaded    bool
+	Encrypted bool
+	// Unreadable is set when the key's public half could not be determined.
+	Unreadable bool
+}
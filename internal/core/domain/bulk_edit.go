@@ -0,0 +1,54 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// BulkEdit is a find-and-replace over one option of many servers.
+type BulkEdit struct {
+	// Option is an ssh_config keyword such as HostName, User, Port, IdentityFile or ProxyJump.
+	Option  string
+	Find    string
+	Replace string
+	// Regex treats Find as a regular expression; Replace may then refer to groups as $1.
+	Regex bool
+}
+
+// BulkChange is the effect of a BulkEdit on one server.
+type BulkChange struct {
+	Alias  string
+	Before []string
+	After  []string
+	// Error tells why the new values cannot be written; empty when they can.
+	Error string
+}
+
+// BulkPreview lists what a BulkEdit would change before anything is written.
+type BulkPreview struct {
+	Edit    BulkEdit
+	Changes []BulkChange
+	// Unchanged counts the servers in scope that the edit does not affect.
+	Unchanged int
+	// Skipped lists servers in scope defined in included files, which are not edited.
+	Skipped []string
+}
+
+// Valid reports whether every change of the preview can be applied.
+func (p BulkPreview) Valid() bool {
+	for _, change := range p.Changes {
+		if change.Error != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// CertificateStatus summarizes whether an SSH certificate can be used right now.
+type CertificateStatus string
+
+const (
+	// CertificateValid means the certificate is inside its validity window.
+	CertificateValid CertificateStatus = "valid"
+	// CertificateExpiring means the certificate expires within the warning period.
+	CertificateExpiring CertificateStatus = "expiring"
+	// CertificateExpired means the certificate's validity window has passed.
+	CertificateExpired CertificateStatus = "expired"
+	// CertificateNotYetValid means the certificate's validity window has not started.
+	CertificateNotYetValid CertificateStatus = "not yet valid"
+	// CertificateMissing means the configured certificate file does not exist.
+	CertificateMissing CertificateStatus = "missing"
+	// CertificateInvalid means the file could not be parsed as a certificate.
+	CertificateInvalid CertificateStatus = "invalid"
+)
+
+// NeedsRenewal reports whether a renew command should run before connecting.
+func (s CertificateStatus) NeedsRenewal() bool {
+	return s == CertificateExpired || s == CertificateMissing
+}
+
+// Certificate is a parsed OpenSSH user or host certificate.
+type Certificate struct {
+	Path       string
+	Type       string // "user" or "host"
+	KeyID      string
+	Serial     uint64
+	Principals []string
+	// ValidAfter is zero when the certificate is valid from the beginning of time.
+	ValidAfter time.Time
+	// ValidBefore is zero when the certificate never expires.
+	ValidBefore   time.Time
+	CAFingerprint string
+	// Missing is set when the certificate file does not exist.
+	Missing bool
+	// Error explains why an existing file could not be parsed as a certificate.
+	Error string
+}
+
+// Status returns the state of the certificate at now, treating the last warnBefore as expiring.
+func (c Certificate) Status(now time.Time, warnBefore time.Duration) CertificateStatus {
+	switch {
+	case c.Missing:
+		return CertificateMissing
+	case c.Error != "":
+		return CertificateInvalid
+	case !c.ValidAfter.IsZero() && now.Before(c.ValidAfter):
+		return CertificateNotYetValid
+	case c.ValidBefore.IsZero():
+		return CertificateValid
+	case !now.Before(c.ValidBefore):
+		return CertificateExpired
+	case c.ValidBefore.Sub(now) <= warnBefore:
+		return CertificateExpiring
+	default:
+		return CertificateValid
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ListColumn names a field shown for each server in the list.
+type ListColumn string
+
+const (
+	ColumnAlias        ListColumn = "alias"
+	ColumnHost         ListColumn = "host"
+	ColumnUser         ListColumn = "user"
+	ColumnPort         ListColumn = "port"
+	ColumnLastSeen     ListColumn = "lastseen"
+	ColumnSSHCount     ListColumn = "sshcount"
+	ColumnTags         ListColumn = "tags"
+	ColumnReachability ListColumn = "reachability"
+	ColumnLatency      ListColumn = "latency"
+	ColumnJump         ListColumn = "jump"
+	ColumnNotes        ListColumn = "notes"
+)
+
+// ListColumns lists every column that can be configured.
+var ListColumns = []ListColumn{
+	ColumnAlias, ColumnHost, ColumnUser, ColumnPort, ColumnLastSeen, ColumnSSHCount,
+	ColumnTags, ColumnReachability, ColumnLatency, ColumnJump, ColumnNotes,
+}
+
+// DefaultListColumns are the columns shown when none are configured.
+var DefaultListColumns = []ListColumn{ColumnAlias, ColumnHost, ColumnLastSeen, ColumnTags}
+
+func (c ListColumn) Valid() bool {
+	for _, column := range ListColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// SortField returns the sort field ordering the list by this column, if there is one.
+func (c ListColumn) SortField() (SortField, bool) {
+	switch c {
+	case ColumnAlias:
+		return SortFieldAlias, true
+	case ColumnHost:
+		return SortFieldHost, true
+	case ColumnLastSeen:
+		return SortFieldLastSeen, true
+	case ColumnSSHCount:
+		return SortFieldSSHCount, true
+	case ColumnReachability:
+		return SortFieldReachability, true
+	case ColumnLatency:
+		return SortFieldLatency, true
+	default:
+		return "", false
+	}
+}
+
+// ParseListColumns validates column names, rejecting unknown and repeated ones.
+func ParseListColumns(names []string) ([]ListColumn, error) {
+	columns := make([]ListColumn, 0, len(names))
+	seen := make(map[ListColumn]bool, len(names))
+	for _, name := range names {
+		column, err := ParseListColumn(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("column %q is listed twice", name)
+		}
+		seen[column] = true
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// ParseListColumn returns the column called name, ignoring case.
+func ParseListColumn(name string) (ListColumn, error) {
+	column := ListColumn(strings.ToLower(strings.TrimSpace(name)))
+	if !column.Valid() {
+		valid := make([]string, len(ListColumns))
+		for i, c := range ListColumns {
+			valid[i] = string(c)
+		}
+		return "", fmt.Errorf("unknown column %q (want %s)", name, strings.Join(valid, ", "))
+	}
+	return column, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around each change.
+	diffContext = 3
+	// diffMaxCells bounds the LCS table; larger changes are shown as a full replacement.
+	diffMaxCells = 16_000_000
+)
+
+// diffOp is one line of an edit script: ' ' keeps, '-' deletes and '+' inserts.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// UnifiedDiff returns the changes from a to b in unified diff format, or ""
+// when both are equal.
+func UnifiedDiff(fromName, toName, a, b string) string {
+	if a == b {
+		return ""
+	}
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	for start := 0; start < len(ops); {
+		// Find the next change and extend the hunk while changes are close together.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		hunkStart := max(first-diffContext, start)
+		end := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		hunkEnd := min(end+diffContext, len(ops))
+
+		aStart, bStart := lineNumbers(ops[:hunkStart])
+		aLen, bLen := lineNumbers(ops[hunkStart:hunkEnd])
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+		for _, op := range ops[hunkStart:hunkEnd] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			out.WriteByte('\n')
+		}
+		start = hunkEnd
+	}
+	return out.String()
+}
+
+// diffLines computes a line edit script using an LCS over the part between
+// the common prefix and suffix.
+func diffLines(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if (len(midA)+1)*(len(midB)+1) > diffMaxCells {
+		for _, line := range midA {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range midB {
+			ops = append(ops, diffOp{'+', line})
+		}
+	} else {
+		ops = append(ops, lcsOps(midA, midB)...)
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+func lcsOps(a, b []string) []diffOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// lineNumbers counts the lines of a and b covered by ops.
+func lineNumbers(ops []diffOp) (aLines, bLines int) {
+	for _, op := range ops {
+		if op.kind != '+' {
+			aLines++
+		}
+		if op.kind != '-' {
+			bLines++
+		}
+	}
+	return aLines, bLines
+}
+
+// hunkRange formats a hunk header range from the number of preceding lines and the hunk length.
+func hunkRange(before, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, length)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,58 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "changed line keeps context",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "append to empty file",
+			a:    "",
+			b:    "x\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1 @@\n+x\n",
+		},
+		{
+			name: "distant changes make two hunks",
+			a:    "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			b:    "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", tt.a, tt.b); got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ExecOptions controls a non-interactive command run across servers.
+type ExecOptions struct {
+	// Concurrency is the maximum number of hosts contacted at once.
+	Concurrency int
+	// Timeout bounds each host's run; zero means no limit.
+	Timeout time.Duration
+}
+
+// ExecResult is the outcome of running a command on one server.
+type ExecResult struct {
+	Alias    string        `json:"alias"`
+	Command  string        `json:"command"`
+	Stdout   string        `json:"stdout"`
+	Stderr   string        `json:"stderr"`
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Failed reports whether the command did not complete with exit code 0.
+func (r ExecResult) Failed() bool {
+	return r.ExitCode != 0 || r.Error != ""
+}
+
+// SortExecResults orders results with failures first, then by alias.
+func SortExecResults(results []ExecResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		fi, fj := results[i].Failed(), results[j].Failed()
+		if fi != fj {
+			return fi
+		}
+		return results[i].Alias < results[j].Alias
+	})
+}
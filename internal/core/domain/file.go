@@ -0,0 +1,61 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrRemoteFileChanged is returned when a remote file no longer matches the copy that was edited.
+var ErrRemoteFileChanged = errors.New("remote file changed since it was fetched")
+
+// FileEntry is a file or directory on the local machine or a server.
+type FileEntry struct {
+	Name    string
+	Path    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+	IsDir   bool
+	// IsLink is set for symlinks; IsDir then describes the link target.
+	IsLink bool
+}
+
+// TransferProgress reports how far a transfer has come.
+type TransferProgress struct {
+	// File is the file currently being copied, relative to the transfer root.
+	File string
+	Done int64
+	// Total is the expected number of bytes, or 0 when unknown.
+	Total int64
+}
+
+// Percent returns the completed share in the range 0-100, or -1 when the total is unknown.
+func (p TransferProgress) Percent() int {
+	if p.Total <= 0 {
+		return -1
+	}
+	return int(min(p.Done*100/p.Total, 100))
+}
+
+// RemoteFile is the content of a remote file fetched for editing.
+type RemoteFile struct {
+	Alias   string
+	Path    string
+	Mode    os.FileMode
+	Content []byte
+}
@@ -0,0 +1,168 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"net"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GroupMode selects how the server list groups servers into a tree.
+type GroupMode int
+
+const (
+	GroupNone GroupMode = iota
+	GroupByTag
+	GroupByDomain
+	GroupByFile
+)
+
+func (m GroupMode) String() string {
+	switch m {
+	case GroupByTag:
+		return "Tag"
+	case GroupByDomain:
+		return "Domain"
+	case GroupByFile:
+		return "File"
+	default:
+		return "None"
+	}
+}
+
+// Next cycles through the grouping modes, returning to GroupNone after the last one.
+func (m GroupMode) Next() GroupMode {
+	if m >= GroupByFile {
+		return GroupNone
+	}
+	return m + 1
+}
+
+// ServerGroup is a named set of servers shown as one node of the tree.
+type ServerGroup struct {
+	Name    string
+	Servers []Server
+}
+
+// Reachability is the result of the last ping of a server.
+type Reachability struct {
+	Up        bool
+	Latency   time.Duration
+	CheckedAt time.Time
+}
+
+// Names of the groups collecting servers that have no tag, domain or file.
+const (
+	GroupUntagged  = "(untagged)"
+	GroupIPAddress = "(IP address)"
+	GroupNoDomain  = "(no domain)"
+	GroupNoFile    = "(unknown file)"
+)
+
+// GroupServers splits servers into groups for the tree view. Servers keep their order
+// within a group; groups are sorted by name with the catch-all groups last. In tag mode
+// a server appears once under each of its tags; in file mode files under home are
+// named relative to ~.
+func GroupServers(servers []Server, mode GroupMode, home string) []ServerGroup {
+	if mode == GroupNone {
+		return nil
+	}
+
+	index := make(map[string]int)
+	var groups []ServerGroup
+	add := func(name string, server Server) {
+		key := strings.ToLower(name)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ServerGroup{Name: name})
+		}
+		groups[i].Servers = append(groups[i].Servers, server)
+	}
+
+	for _, server := range servers {
+		switch mode {
+		case GroupByTag:
+			if len(server.Tags) == 0 {
+				add(GroupUntagged, server)
+			}
+			for _, tag := range server.Tags {
+				add(tag, server)
+			}
+		case GroupByDomain:
+			add(domainGroup(server), server)
+		case GroupByFile:
+			if server.SourceFile == "" {
+				add(GroupNoFile, server)
+			} else {
+				add(homeRelative(server.SourceFile, home), server)
+			}
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		ci, cj := catchAllGroup(groups[i].Name), catchAllGroup(groups[j].Name)
+		if ci != cj {
+			return cj
+		}
+		return strings.ToLower(groups[i].Name) < strings.ToLower(groups[j].Name)
+	})
+	return groups
+}
+
+// domainGroup returns the DNS domain of the server's host: the name without its first
+// label, or the whole name when it has only two labels.
+func domainGroup(server Server) string {
+	host := server.Host
+	if host == "" {
+		host = server.Alias
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if net.ParseIP(host) != nil {
+		return GroupIPAddress
+	}
+	labels := strings.Split(host, ".")
+	switch {
+	case len(labels) < 2:
+		return GroupNoDomain
+	case len(labels) == 2:
+		return host
+	default:
+		return strings.Join(labels[1:], ".")
+	}
+}
+
+func catchAllGroup(name string) bool {
+	switch name {
+	case GroupUntagged, GroupIPAddress, GroupNoDomain, GroupNoFile:
+		return true
+	}
+	return false
+}
+
+// homeRelative returns p with a leading home directory replaced by ~.
+func homeRelative(p, home string) string {
+	if home == "" {
+		return p
+	}
+	if rel, err := filepath.Rel(home, p); err == nil && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return p
+}
@@ -0,0 +1,97 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func groupSummary(groups []ServerGroup) map[string][]string {
+	summary := make(map[string][]string, len(groups))
+	for _, group := range groups {
+		for _, server := range group.Servers {
+			summary[group.Name] = append(summary[group.Name], server.Alias)
+		}
+	}
+	return summary
+}
+
+func groupNames(groups []ServerGroup) []string {
+	names := make([]string, 0, len(groups))
+	for _, group := range groups {
+		names = append(names, group.Name)
+	}
+	return names
+}
+
+func TestGroupServers(t *testing.T) {
+	servers := []Server{
+		{Alias: "web1", Host: "web1.prod.example.com", Tags: []string{"prod", "web"}, SourceFile: "/home/u/.ssh/config"},
+		{Alias: "db1", Host: "10.0.0.5", Tags: []string{"Prod"}, SourceFile: "/home/u/.ssh/conf.d/db"},
+		{Alias: "box", Host: "example.org"},
+		{Alias: "local"},
+	}
+
+	tests := []struct {
+		mode   GroupMode
+		names  []string
+		groups map[string][]string
+	}{
+		{
+			mode:  GroupByTag,
+			names: []string{"prod", "web", GroupUntagged},
+			groups: map[string][]string{
+				"prod":        {"web1", "db1"},
+				"web":         {"web1"},
+				GroupUntagged: {"box", "local"},
+			},
+		},
+		{
+			mode:  GroupByDomain,
+			names: []string{"example.org", "prod.example.com", GroupIPAddress, GroupNoDomain},
+			groups: map[string][]string{
+				"example.org":      {"box"},
+				"prod.example.com": {"web1"},
+				GroupIPAddress:     {"db1"},
+				GroupNoDomain:      {"local"},
+			},
+		},
+		{
+			mode:  GroupByFile,
+			names: []string{"~/.ssh/conf.d/db", "~/.ssh/config", GroupNoFile},
+			groups: map[string][]string{
+				"~/.ssh/conf.d/db": {"db1"},
+				"~/.ssh/config":    {"web1"},
+				GroupNoFile:        {"box", "local"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			groups := GroupServers(servers, tt.mode, "/home/u")
+			if got := groupNames(groups); !reflect.DeepEqual(got, tt.names) {
+				t.Fatalf("names = %v, want %v", got, tt.names)
+			}
+			if got := groupSummary(groups); !reflect.DeepEqual(got, tt.groups) {
+				t.Fatalf("groups = %v, want %v", got, tt.groups)
+			}
+		})
+	}
+
+	if groups := GroupServers(servers, GroupNone, "/home/u"); groups != nil {
+		t.Fatalf("GroupNone returned %v", groups)
+	}
+}
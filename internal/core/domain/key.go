@@ -0,0 +1,103 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// SSHKey describes a private key found in the user's ssh directory.
+type SSHKey struct {
+	Path          string
+	PublicKeyPath string
+	Type          string
+	Bits          int
+	Fingerprint   string
+	Comment       string
+	// Encrypted is set for passphrase-protected keys.
+	Encrypted bool
+	// Servers lists the aliases whose IdentityFile points at this key.
+	Servers []string
+	// Problem describes why the key could not be read or is unsafe, if at all.
+	Problem string
+}
+
+// KeyGenOptions controls how a key is created.
+type KeyGenOptions struct {
+	// Type is "ed25519", "rsa" or "ecdsa", like the ssh-keygen -t values.
+	Type string
+	// Bits is only used for rsa and ecdsa keys; zero keeps the ssh-keygen default.
+	Bits       int
+	Path       string
+	Comment    string
+	Passphrase string
+}
+
+// KeyInstallResult is the outcome of installing a public key on one server.
+type KeyInstallResult struct {
+	Alias string
+	// KeyPath is the private key whose public half was installed.
+	KeyPath string
+	// Added is false when authorized_keys already contained the key.
+	Added bool
+	// Verified reports that a batch-mode login with the key succeeded.
+	Verified bool
+	// HasPassword reports that a password is still stored for the server.
+	HasPassword bool
+	Error       string
+	Duration    time.Duration
+}
+
+// Failed reports whether the key could not be installed or verified.
+func (r KeyInstallResult) Failed() bool {
+	return r.Error != "" || !r.Verified
+}
+
+// RotationHost tracks the rotation steps completed on one server.
+type RotationHost struct {
+	Alias string `json:"alias"`
+	// Installed means the new key was added to authorized_keys and login with it succeeded.
+	Installed bool `json:"installed"`
+	// ConfigUpdated means the Host block's IdentityFile now points at the new key.
+	ConfigUpdated bool `json:"config_updated"`
+	// OldKeyRemoved means the old public key is no longer in authorized_keys.
+	OldKeyRemoved bool      `json:"old_key_removed"`
+	Error         string    `json:"error,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Done reports whether every rotation step succeeded on the server.
+func (h RotationHost) Done() bool {
+	return h.Installed && h.ConfigUpdated && h.OldKeyRemoved
+}
+
+// KeyRotation replaces OldKey with NewKey on every server that used OldKey.
+// It is saved after each step so an interrupted rotation can be resumed.
+type KeyRotation struct {
+	ID         string         `json:"id"`
+	OldKey     string         `json:"old_key"`
+	NewKey     string         `json:"new_key"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at,omitzero"`
+	Hosts      []RotationHost `json:"hosts"`
+}
+
+// Done reports whether the rotation finished on every server.
+func (r KeyRotation) Done() bool {
+	for _, host := range r.Hosts {
+		if !host.Done() {
+			return false
+		}
+	}
+	return true
+}
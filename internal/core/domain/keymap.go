@@ -0,0 +1,209 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeymapPreset names a built-in set of key bindings.
+type KeymapPreset string
+
+const (
+	// KeymapDefault keeps the single-letter bindings dogssh always had.
+	KeymapDefault KeymapPreset = "default"
+	// KeymapVim adds vim-style motions such as g/G and Ctrl+D/Ctrl+U.
+	KeymapVim KeymapPreset = "vim"
+	// KeymapEmacs adds emacs-style motions such as Ctrl+N/Ctrl+P.
+	KeymapEmacs KeymapPreset = "emacs"
+)
+
+// Valid reports whether p is one of the known presets.
+func (p KeymapPreset) Valid() bool {
+	switch p {
+	case KeymapDefault, KeymapVim, KeymapEmacs:
+		return true
+	default:
+		return false
+	}
+}
+
+// KeySettings selects the key bindings of the server list.
+type KeySettings struct {
+	Preset KeymapPreset
+	// Bindings maps action names to the keys that trigger them, replacing the preset's keys for those actions.
+	Bindings map[string][]string
+}
+
+// namedKeys maps the lower-case spellings of non-printable keys to their canonical names.
+var namedKeys = map[string]string{
+	"enter": "Enter", "return": "Enter",
+	"esc": "Esc", "escape": "Esc",
+	"tab": "Tab", "backtab": "Backtab",
+	"backspace": "Backspace",
+	"delete":    "Delete", "del": "Delete",
+	"insert": "Insert",
+	"home":   "Home", "end": "End",
+	"pgup": "PgUp", "pageup": "PgUp",
+	"pgdn": "PgDn", "pagedown": "PgDn",
+	"up": "Up", "down": "Down", "left": "Left", "right": "Right",
+	"space": "Space",
+	"f1":    "F1", "f2": "F2", "f3": "F3", "f4": "F4", "f5": "F5", "f6": "F6",
+	"f7": "F7", "f8": "F8", "f9": "F9", "f10": "F10", "f11": "F11", "f12": "F12",
+}
+
+// NormalizeKey returns the canonical name of a key such as "g", "G", "enter", "C-d" or
+// "alt+v": modifiers are spelled Ctrl+ and Alt+, named keys are capitalized and single
+// characters keep their case.
+func NormalizeKey(name string) (string, error) {
+	rest := strings.TrimSpace(name)
+	if rest == "" {
+		if name == " " {
+			return "Space", nil
+		}
+		return "", fmt.Errorf("empty key")
+	}
+
+	ctrl, alt := false, false
+	for utf8.RuneCountInString(rest) > 1 {
+		lower := strings.ToLower(rest)
+		if prefix, ok := cutPrefix(lower, "ctrl+", "ctrl-", "c-"); ok {
+			ctrl, rest = true, rest[len(prefix):]
+		} else if prefix, ok := cutPrefix(lower, "alt+", "alt-", "meta+", "m-"); ok {
+			alt, rest = true, rest[len(prefix):]
+		} else {
+			break
+		}
+	}
+
+	var key string
+	if r, size := utf8.DecodeRuneInString(rest); size == len(rest) {
+		switch {
+		case r == ' ':
+			key = "Space"
+		case ctrl && !unicode.IsLetter(r):
+			return "", fmt.Errorf("invalid key %q: Ctrl only combines with letters and named keys", name)
+		case ctrl:
+			key = string(unicode.ToUpper(r))
+			if named, ok := ctrlAliases[key]; ok {
+				return "", fmt.Errorf("invalid key %q: terminals send it as %s; bind %s instead", name, named, named)
+			}
+		default:
+			key = rest
+		}
+	} else if named, ok := namedKeys[strings.ToLower(rest)]; ok {
+		key = named
+	} else {
+		return "", fmt.Errorf("invalid key %q", name)
+	}
+
+	if alt {
+		key = "Alt+" + key
+	}
+	if ctrl {
+		key = "Ctrl+" + key
+	}
+	return key, nil
+}
+
+// ctrlAliases are the Ctrl+letter keys a terminal cannot tell apart from a named key.
+var ctrlAliases = map[string]string{
+	"H": "Backspace",
+	"I": "Tab",
+	"M": "Enter",
+}
+
+func cutPrefix(s string, prefixes ...string) (string, bool) {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) && len(s) > len(prefix) {
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// ResolveKeymap applies layers of action-to-keys bindings onto base and returns the
+// result. A layer replaces all keys of the actions it names and takes those keys away
+// from any other action, so every key triggers at most one action. Unknown actions,
+// invalid keys and keys claimed twice within a layer are reported and skipped.
+func ResolveKeymap(base map[string][]string, layers ...map[string][]string) (map[string][]string, []error) {
+	bindings := make(map[string][]string, len(base))
+	for action, keys := range base {
+		bindings[action] = append([]string(nil), keys...)
+	}
+
+	var errs []error
+	for _, layer := range layers {
+		actions := make([]string, 0, len(layer))
+		for action := range layer {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+
+		claimed := make(map[string]string)
+		for _, action := range actions {
+			if _, ok := bindings[action]; !ok {
+				errs = append(errs, fmt.Errorf("unknown action %q", action))
+				continue
+			}
+			var keys []string
+			for _, name := range layer[action] {
+				key, err := NormalizeKey(name)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", action, err))
+					continue
+				}
+				if other, ok := claimed[key]; ok && other != action {
+					errs = append(errs, fmt.Errorf("%s: key %s is already bound to %s", action, key, other))
+					continue
+				}
+				if !containsString(keys, key) {
+					claimed[key] = action
+					keys = append(keys, key)
+				}
+			}
+			for other, otherKeys := range bindings {
+				if other != action {
+					bindings[other] = removeStrings(otherKeys, keys)
+				}
+			}
+			bindings[action] = keys
+		}
+	}
+	return bindings, errs
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeStrings(values, remove []string) []string {
+	kept := values[:0:0]
+	for _, v := range values {
+		if !containsString(remove, v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,102 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "g", want: "g"},
+		{in: "G", want: "G"},
+		{in: "?", want: "?"},
+		{in: " ", want: "Space"},
+		{in: "space", want: "Space"},
+		{in: "enter", want: "Enter"},
+		{in: "ESCAPE", want: "Esc"},
+		{in: "pagedown", want: "PgDn"},
+		{in: "ctrl+d", want: "Ctrl+D"},
+		{in: "C-n", want: "Ctrl+N"},
+		{in: "M-v", want: "Alt+v"},
+		{in: "alt+<", want: "Alt+<"},
+		{in: "Ctrl-Left", want: "Ctrl+Left"},
+		{in: "ctrl++", err: true},
+		{in: "Ctrl+H", err: true},
+		{in: "c-i", err: true},
+		{in: "ctrl+m", err: true},
+		{in: "hyper+x", err: true},
+		{in: "", err: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeKey(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("NormalizeKey(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeKey(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestResolveKeymap(t *testing.T) {
+	base := map[string][]string{
+		"ping":  {"g"},
+		"first": {"Home"},
+		"group": {"G"},
+		"last":  {"End"},
+	}
+	preset := map[string][]string{
+		"first": {"g", "Home"},
+		"last":  {"G", "End"},
+		"group": {"z"},
+	}
+	user := map[string][]string{
+		"ping":    {"P", "bogus-key"},
+		"restart": {"X"},
+	}
+
+	got, errs := ResolveKeymap(base, preset, user)
+	want := map[string][]string{
+		"ping":  {"P"},
+		"first": {"g", "Home"},
+		"group": {"z"},
+		"last":  {"G", "End"},
+	}
+	for action, keys := range want {
+		if !reflect.DeepEqual(got[action], keys) {
+			t.Errorf("%s = %v, want %v", action, got[action], keys)
+		}
+	}
+	if len(errs) != 2 {
+		t.Errorf("errors = %v, want an invalid key and an unknown action", errs)
+	}
+	if !reflect.DeepEqual(base["ping"], []string{"g"}) {
+		t.Errorf("base was modified: %v", base)
+	}
+
+	_, errs = ResolveKeymap(base, map[string][]string{"first": {"x"}, "last": {"x"}})
+	if len(errs) != 1 {
+		t.Errorf("duplicate key errors = %v, want one", errs)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// KnownHost is one entry of a known_hosts file.
+type KnownHost struct {
+	File string
+	Line int
+	// Hosts are the host patterns of the entry; hashed entries keep their |1|salt|hash form.
+	Hosts       []string
+	Hashed      bool
+	Marker      string
+	KeyType     string
+	Fingerprint string
+	Comment     string
+}
+
+// HostKey is a key presented by a server, as reported by ssh-keyscan.
+type HostKey struct {
+	Type        string
+	Fingerprint string
+	// Line is the key in known_hosts format, ready to be recorded.
+	Line string
+}
+
+// HostKeyStatus is the outcome of comparing a server's current host keys with known_hosts.
+type HostKeyStatus string
+
+const (
+	// HostKeyMatch means at least one scanned key is already recorded for the host.
+	HostKeyMatch HostKeyStatus = "match"
+	// HostKeyUnknown means known_hosts has no entry for the host.
+	HostKeyUnknown HostKeyStatus = "unknown"
+	// HostKeyChanged means entries exist but none of them matches what the server presents.
+	HostKeyChanged HostKeyStatus = "changed"
+)
+
+// HostKeyCheck compares the recorded and the current host keys of a server.
+type HostKeyCheck struct {
+	Alias string
+	// Address is the name ssh records the host under, e.g. "example.com" or "[example.com]:2222".
+	Address  string
+	Recorded []KnownHost
+	Scanned  []HostKey
+	Status   HostKeyStatus
+}
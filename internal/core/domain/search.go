@@ -0,0 +1,34 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// SearchMatch is a server that matched a search query.
+type SearchMatch struct {
+	Server Server
+	// Score ranks how well the plain words of the query matched; it is zero when the
+	// query has only field filters.
+	Score int
+	// Highlights holds the rune offsets matched by plain words, per displayed field.
+	Highlights SearchHighlights
+}
+
+// SearchHighlights lists matched rune offsets in the fields shown in the server list.
+type SearchHighlights struct {
+	Alias []int
+	Host  []int
+	User  []int
+	// Tags maps a tag to the offsets matched in it.
+	Tags map[string][]int
+}
@@ -14,18 +14,53 @@
 
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type Server struct {
-	Alias         string
-	Aliases       []string
-	Host          string
-	User          string
-	Port          int
-	IdentityFiles []string
-	Password      string // Used for storing encrypted passwords
-	Tags          []string
-	LastSeen      time.Time
-	PinnedAt      time.Time
-	SSHCount      int
+	Alias           string
+	Aliases         []string
+	Host            string
+	User            string
+	Port            int
+	IdentityFiles   []string
+	CertificateFile string // OpenSSH certificate presented with the identity, if any
+	Password        string // Used for storing encrypted passwords
+	Tags            []string
+	LastSeen        time.Time
+	PinnedAt        time.Time
+	SSHCount        int
+	Tunnels         []Tunnel
+	SourceFile      string // SSH config file the host is defined in
+	ProxyJump       string // ProxyJump of the host; kept as is when the server is edited
+	Notes           string // Free text kept in the dogssh metadata
+}
+
+// ServerUpdate pairs a server with its edited version for batch updates.
+type ServerUpdate struct {
+	Old Server
+	New Server
+}
+
+// EditTags adds the tags in add that are missing from tags and drops those in remove,
+// comparing case-insensitively and keeping the original order.
+func EditTags(tags, add, remove []string) []string {
+	result := make([]string, 0, len(tags)+len(add))
+	has := func(list []string, tag string) bool {
+		for _, t := range list {
+			if strings.EqualFold(t, tag) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, tag := range append(append([]string{}, tags...), add...) {
+		if has(remove, tag) || has(result, tag) {
+			continue
+		}
+		result = append(result, tag)
+	}
+	return result
 }
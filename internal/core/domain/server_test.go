@@ -0,0 +1,42 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		add, remove []string
+		want        []string
+	}{
+		{"add", []string{"prod"}, []string{"web", "db"}, nil, []string{"prod", "web", "db"}},
+		{"add existing", []string{"prod"}, []string{"PROD"}, nil, []string{"prod"}},
+		{"remove", []string{"prod", "web"}, nil, []string{"Web"}, []string{"prod"}},
+		{"add and remove", []string{"old", "prod"}, []string{"new"}, []string{"old"}, []string{"prod", "new"}},
+		{"empty", nil, nil, nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EditTags(tt.tags, tt.add, tt.remove); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("EditTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// TmuxMode controls how a connection is opened when dogssh runs inside tmux.
+type TmuxMode string
+
+const (
+	// TmuxModeOff disables tmux integration; the TUI is suspended while connected.
+	TmuxModeOff TmuxMode = "off"
+	// TmuxModeWindow opens each connection in a new tmux window.
+	TmuxModeWindow TmuxMode = "window"
+	// TmuxModeHSplit splits the current window side by side.
+	TmuxModeHSplit TmuxMode = "hsplit"
+	// TmuxModeVSplit splits the current window top and bottom.
+	TmuxModeVSplit TmuxMode = "vsplit"
+)
+
+// Valid reports whether m is one of the known tmux modes.
+func (m TmuxMode) Valid() bool {
+	switch m {
+	case TmuxModeOff, TmuxModeWindow, TmuxModeHSplit, TmuxModeVSplit:
+		return true
+	default:
+		return false
+	}
+}
+
+// ConnectionSettings groups options that affect how sessions are started.
+type ConnectionSettings struct {
+	TmuxMode TmuxMode
+}
+
+// CertificateSettings controls how SSH certificates are checked and renewed.
+type CertificateSettings struct {
+	// RenewCommand runs through the shell before connecting when a server's certificate has expired.
+	RenewCommand string
+	// WarnBefore is how long before expiry a certificate is flagged as expiring.
+	WarnBefore time.Duration
+}
+
+// ViewSettings controls the saved views.
+type ViewSettings struct {
+	// Startup is the name of the saved view applied when dogssh starts.
+	Startup string
+}
+
+// FormSettings holds the values prefilled when adding a server.
+type FormSettings struct {
+	User         string
+	Port         int
+	IdentityFile string
+}
+
+// ListSettings configures the server list.
+type ListSettings struct {
+	// Sort is the order used until the user picks another one.
+	Sort SortOrder
+	// Columns are the fields shown for each server, in order.
+	Columns []ListColumn
+	// Widths caps the width of columns; the others fit their content and the terminal.
+	Widths map[ListColumn]int
+}
+
+// LogLevel is the least severe level written to the log file.
+type LogLevel string
+
+const (
+	// LogLevelDebug logs everything, including routine details such as state saves.
+	LogLevelDebug LogLevel = "debug"
+	// LogLevelInfo logs completed operations such as edits and transfers.
+	LogLevelInfo LogLevel = "info"
+	// LogLevelWarn logs only problems dogssh recovered from and errors.
+	LogLevelWarn LogLevel = "warn"
+	// LogLevelError logs only failed operations.
+	LogLevelError LogLevel = "error"
+)
+
+// Valid reports whether l is one of the known log levels.
+func (l LogLevel) Valid() bool {
+	switch l {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return true
+	default:
+		return false
+	}
+}
+
+// LogSettings controls where dogssh logs and how much.
+type LogSettings struct {
+	// Path is the log file; ~ expands to the home directory.
+	Path  string
+	Level LogLevel
+}
+
+// Settings holds user preferences loaded from ~/.dogssh/config.yaml.
+type Settings struct {
+	Form         FormSettings
+	List         ListSettings
+	Log          LogSettings
+	Connection   ConnectionSettings
+	Certificates CertificateSettings
+	Views        ViewSettings
+	Keys         KeySettings
+	Theme        ThemeSettings
+}
+
+// DefaultSettings returns the settings used when no config file exists.
+func DefaultSettings() Settings {
+	return Settings{
+		Form: FormSettings{
+			User:         "root",
+			Port:         22,
+			IdentityFile: "~/.ssh/id_ed25519",
+		},
+		List: ListSettings{
+			Sort:    SortOrder{Field: SortFieldAlias},
+			Columns: DefaultListColumns,
+		},
+		Log: LogSettings{
+			Path:  "~/.dogssh/dogssh.log",
+			Level: LogLevelDebug,
+		},
+		Connection: ConnectionSettings{
+			TmuxMode: TmuxModeWindow,
+		},
+		Certificates: CertificateSettings{
+			WarnBefore: time.Hour,
+		},
+		Keys: KeySettings{
+			Preset: KeymapDefault,
+		},
+		Theme: ThemeSettings{
+			Preset: ThemeDark,
+		},
+	}
+}
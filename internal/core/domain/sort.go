@@ -0,0 +1,275 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortField names a field the server list can be sorted by.
+type SortField string
+
+const (
+	SortFieldAlias        SortField = "alias"
+	SortFieldHost         SortField = "host"
+	SortFieldLastSeen     SortField = "lastseen"
+	SortFieldSSHCount     SortField = "sshcount"
+	SortFieldLatency      SortField = "latency"
+	SortFieldReachability SortField = "reachability"
+	// SortFieldFrecency combines how often and how recently a server was used.
+	SortFieldFrecency SortField = "frecency"
+)
+
+// SortFields lists the sort fields in the order they are cycled through.
+var SortFields = []SortField{
+	SortFieldAlias, SortFieldLastSeen, SortFieldFrecency, SortFieldSSHCount,
+	SortFieldHost, SortFieldReachability, SortFieldLatency,
+}
+
+func (f SortField) Valid() bool {
+	for _, field := range SortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultDescending reports whether field sorts descending when no direction is given:
+// the most recent, most used and highest scoring servers come first, names go A to Z,
+// and reachable and fast servers come before the others.
+func (f SortField) DefaultDescending() bool {
+	switch f {
+	case SortFieldLastSeen, SortFieldSSHCount, SortFieldFrecency:
+		return true
+	default:
+		return false
+	}
+}
+
+// SortOrder is a sort field and its direction.
+type SortOrder struct {
+	Field      SortField `json:"field"`
+	Descending bool      `json:"descending,omitempty"`
+}
+
+// DefaultSortOrder sorts by field in its default direction.
+func DefaultSortOrder(field SortField) SortOrder {
+	return SortOrder{Field: field, Descending: field.DefaultDescending()}
+}
+
+// Reversed returns the order with the opposite direction.
+func (o SortOrder) Reversed() SortOrder {
+	o.Descending = !o.Descending
+	return o
+}
+
+// NextField returns the default order of the field after o's in SortFields.
+func (o SortOrder) NextField() SortOrder {
+	for i, field := range SortFields {
+		if field == o.Field {
+			return DefaultSortOrder(SortFields[(i+1)%len(SortFields)])
+		}
+	}
+	return DefaultSortOrder(SortFieldAlias)
+}
+
+func (o SortOrder) String() string {
+	if o.Descending {
+		return string(o.Field) + " desc"
+	}
+	return string(o.Field) + " asc"
+}
+
+// ParseSortOrder parses a sort field optionally followed by asc or desc, e.g. "lastseen asc".
+func ParseSortOrder(value string) (SortOrder, error) {
+	parts := strings.Fields(strings.ToLower(value))
+	if len(parts) == 0 || len(parts) > 2 {
+		return SortOrder{}, fmt.Errorf("invalid sort %q (want a field and an optional asc or desc)", value)
+	}
+	field := SortField(parts[0])
+	if !field.Valid() {
+		names := make([]string, len(SortFields))
+		for i, f := range SortFields {
+			names[i] = string(f)
+		}
+		return SortOrder{}, fmt.Errorf("unknown sort field %q (want %s)", parts[0], strings.Join(names, ", "))
+	}
+	order := DefaultSortOrder(field)
+	if len(parts) == 2 {
+		switch parts[1] {
+		case "asc":
+			order.Descending = false
+		case "desc":
+			order.Descending = true
+		default:
+			return SortOrder{}, fmt.Errorf("unknown sort direction %q (want asc or desc)", parts[1])
+		}
+	}
+	return order, nil
+}
+
+// frecencyHalfLife is how long it takes for past connections to count half as much in
+// the frecency score.
+const frecencyHalfLife = 7 * 24 * time.Hour
+
+// SortServers orders servers for the list. Pinned servers come first, most recently
+// pinned on top, and the others follow order. Servers without a value for the field
+// (never connected, not pinged) go to the bottom in either direction. reachability
+// holds the last ping results by alias; only the latency and reachability fields use
+// it. Ties break by alias, so the result does not depend on the input order.
+func SortServers(servers []Server, order SortOrder, reachability map[string]Reachability) {
+	s := newServerSorter(order, reachability)
+	sort.SliceStable(servers, func(i, j int) bool {
+		return s.compare(servers[i], servers[j]) < 0
+	})
+}
+
+// SortMatches orders search results by fuzzy score, best first, and equal scores like
+// SortServers.
+func SortMatches(matches []SearchMatch, order SortOrder, reachability map[string]Reachability) {
+	s := newServerSorter(order, reachability)
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return s.compare(matches[i].Server, matches[j].Server) < 0
+	})
+}
+
+type serverSorter struct {
+	order        SortOrder
+	reachability map[string]Reachability
+	now          time.Time
+}
+
+func newServerSorter(order SortOrder, reachability map[string]Reachability) serverSorter {
+	return serverSorter{order: order, reachability: reachability, now: time.Now()}
+}
+
+// compare returns a negative number when a is listed before b and a positive one after.
+func (s serverSorter) compare(a, b Server) int {
+	pa, pb := !a.PinnedAt.IsZero(), !b.PinnedAt.IsZero()
+	if pa != pb {
+		return missingLast(!pa, !pb)
+	}
+	if pa {
+		if c := b.PinnedAt.Compare(a.PinnedAt); c != 0 {
+			return c
+		}
+		return compareIdentity(a, b)
+	}
+	if c := s.compareField(a, b); c != 0 {
+		return c
+	}
+	return compareIdentity(a, b)
+}
+
+func (s serverSorter) compareField(a, b Server) int {
+	switch s.order.Field {
+	case SortFieldHost:
+		return s.direct(compareFold(a.Host, b.Host))
+	case SortFieldLastSeen:
+		if c := missingLast(a.LastSeen.IsZero(), b.LastSeen.IsZero()); c != 0 {
+			return c
+		}
+		return s.direct(a.LastSeen.Compare(b.LastSeen))
+	case SortFieldSSHCount:
+		return s.direct(cmp.Compare(a.SSHCount, b.SSHCount))
+	case SortFieldFrecency:
+		fa, fb := s.frecency(a), s.frecency(b)
+		if c := missingLast(fa == 0, fb == 0); c != 0 {
+			return c
+		}
+		if c := s.direct(cmp.Compare(fa, fb)); c != 0 {
+			return c
+		}
+		return s.direct(a.LastSeen.Compare(b.LastSeen))
+	case SortFieldLatency:
+		ra, oka := s.reachability[a.Alias]
+		rb, okb := s.reachability[b.Alias]
+		if c := missingLast(!oka || !ra.Up, !okb || !rb.Up); c != 0 {
+			return c
+		}
+		return s.direct(cmp.Compare(ra.Latency, rb.Latency))
+	case SortFieldReachability:
+		ra, oka := s.reachability[a.Alias]
+		rb, okb := s.reachability[b.Alias]
+		if c := missingLast(!oka, !okb); c != 0 {
+			return c
+		}
+		if ra.Up != rb.Up {
+			return s.direct(missingLast(!ra.Up, !rb.Up))
+		}
+		// Among reachable servers the faster one comes first.
+		return cmp.Compare(ra.Latency, rb.Latency)
+	default:
+		return s.direct(compareFold(a.Alias, b.Alias))
+	}
+}
+
+// direct applies the direction of the order to an ascending comparison.
+func (s serverSorter) direct(c int) int {
+	if s.order.Descending {
+		return -c
+	}
+	return c
+}
+
+// frecency scores a server by its connection count, halving the weight of past use every
+// frecencyHalfLife. A server never connected to scores 0.
+func (s serverSorter) frecency(server Server) float64 {
+	if server.LastSeen.IsZero() {
+		return 0
+	}
+	age := max(s.now.Sub(server.LastSeen), 0)
+	return float64(max(server.SSHCount, 1)) * math.Exp2(-float64(age)/float64(frecencyHalfLife))
+}
+
+// missingLast orders a value that is present before one that is missing.
+func missingLast(missingA, missingB bool) int {
+	switch {
+	case missingA == missingB:
+		return 0
+	case missingA:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// compareIdentity is the final tie-break: alias ignoring case, then the exact alias,
+// host and source file.
+func compareIdentity(a, b Server) int {
+	if c := compareFold(a.Alias, b.Alias); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Alias, b.Alias); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Host, b.Host); c != 0 {
+		return c
+	}
+	return strings.Compare(a.SourceFile, b.SourceFile)
+}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
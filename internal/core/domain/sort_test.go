@@ -0,0 +1,118 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func serverAliases(servers []Server) []string {
+	aliases := make([]string, 0, len(servers))
+	for _, server := range servers {
+		aliases = append(aliases, server.Alias)
+	}
+	return aliases
+}
+
+func TestSortServers(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	servers := []Server{
+		{Alias: "web", Host: "b.example.com", SSHCount: 3, LastSeen: now.Add(-time.Hour)},
+		{Alias: "db", Host: "a.example.com", SSHCount: 40, LastSeen: now.Add(-60 * day)},
+		{Alias: "new", Host: "c.example.com"},
+		{Alias: "Cache", Host: "a.example.com", SSHCount: 3, LastSeen: now.Add(-2 * day)},
+		{Alias: "pin-old", PinnedAt: now.Add(-day)},
+		{Alias: "pin-new", PinnedAt: now},
+	}
+	reachability := map[string]Reachability{
+		"web":   {Up: true, Latency: 30 * time.Millisecond},
+		"db":    {Up: true, Latency: 5 * time.Millisecond},
+		"Cache": {Up: false},
+	}
+
+	tests := []struct {
+		order SortOrder
+		want  []string
+	}{
+		{SortOrder{Field: SortFieldAlias}, []string{"pin-new", "pin-old", "Cache", "db", "new", "web"}},
+		{SortOrder{Field: SortFieldAlias, Descending: true}, []string{"pin-new", "pin-old", "web", "new", "db", "Cache"}},
+		{SortOrder{Field: SortFieldHost}, []string{"pin-new", "pin-old", "Cache", "db", "web", "new"}},
+		{SortOrder{Field: SortFieldLastSeen, Descending: true}, []string{"pin-new", "pin-old", "web", "Cache", "db", "new"}},
+		{SortOrder{Field: SortFieldLastSeen}, []string{"pin-new", "pin-old", "db", "Cache", "web", "new"}},
+		{SortOrder{Field: SortFieldSSHCount, Descending: true}, []string{"pin-new", "pin-old", "db", "Cache", "web", "new"}},
+		// Heavy but old use decays below recent use; never connected stays last.
+		{SortOrder{Field: SortFieldFrecency, Descending: true}, []string{"pin-new", "pin-old", "web", "Cache", "db", "new"}},
+		{SortOrder{Field: SortFieldFrecency}, []string{"pin-new", "pin-old", "db", "Cache", "web", "new"}},
+		{SortOrder{Field: SortFieldLatency}, []string{"pin-new", "pin-old", "db", "web", "Cache", "new"}},
+		{SortOrder{Field: SortFieldLatency, Descending: true}, []string{"pin-new", "pin-old", "web", "db", "Cache", "new"}},
+		{SortOrder{Field: SortFieldReachability}, []string{"pin-new", "pin-old", "db", "web", "Cache", "new"}},
+		{SortOrder{Field: SortFieldReachability, Descending: true}, []string{"pin-new", "pin-old", "Cache", "db", "web", "new"}},
+	}
+	for _, tt := range tests {
+		got := append([]Server(nil), servers...)
+		SortServers(got, tt.order, reachability)
+		if aliases := serverAliases(got); !reflect.DeepEqual(aliases, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.order, aliases, tt.want)
+		}
+
+		// The result must not depend on the input order.
+		reversed := make([]Server, len(servers))
+		for i, server := range servers {
+			reversed[len(servers)-1-i] = server
+		}
+		SortServers(reversed, tt.order, reachability)
+		if aliases := serverAliases(reversed); !reflect.DeepEqual(aliases, tt.want) {
+			t.Errorf("%s reversed input: got %v, want %v", tt.order, aliases, tt.want)
+		}
+	}
+}
+
+func TestSortServersTies(t *testing.T) {
+	servers := []Server{
+		{Alias: "b", Host: "h2", SourceFile: "/z"},
+		{Alias: "B", Host: "h1"},
+		{Alias: "b", Host: "h1", SourceFile: "/y"},
+		{Alias: "a"},
+	}
+	SortServers(servers, SortOrder{Field: SortFieldSSHCount, Descending: true}, nil)
+	var got []string
+	for _, server := range servers {
+		got = append(got, server.Alias+"@"+server.Host+server.SourceFile)
+	}
+	want := []string{"a@", "B@h1", "b@h1/y", "b@h2/z"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestSortMatches(t *testing.T) {
+	matches := []SearchMatch{
+		{Server: Server{Alias: "b"}, Score: 5},
+		{Server: Server{Alias: "c"}, Score: 9},
+		{Server: Server{Alias: "a"}, Score: 5},
+		{Server: Server{Alias: "pinned", PinnedAt: time.Now()}, Score: 1},
+	}
+	SortMatches(matches, SortOrder{Field: SortFieldAlias}, nil)
+	var got []string
+	for _, match := range matches {
+		got = append(got, match.Server.Alias)
+	}
+	if want := []string{"c", "a", "b", "pinned"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// ThemePreset names a built-in color theme.
+type ThemePreset string
+
+const (
+	ThemeDark         ThemePreset = "dark"
+	ThemeLight        ThemePreset = "light"
+	ThemeHighContrast ThemePreset = "high-contrast"
+	// ThemeMonochrome uses no colors, for terminals without color support; NO_COLOR selects it too.
+	ThemeMonochrome ThemePreset = "monochrome"
+)
+
+// Valid reports whether p is one of the known presets.
+func (p ThemePreset) Valid() bool {
+	switch p {
+	case ThemeDark, ThemeLight, ThemeHighContrast, ThemeMonochrome:
+		return true
+	default:
+		return false
+	}
+}
+
+// ThemeSettings selects the color theme of the UI.
+type ThemeSettings struct {
+	Preset ThemePreset
+	// Colors overrides single colors of the preset by name, e.g. "error": "#FF0000".
+	Colors map[string]string
+}
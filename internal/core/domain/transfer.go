@@ -0,0 +1,109 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+)
+
+// ErrChecksumUnsupported is returned when a server cannot compute checksums.
+var ErrChecksumUnsupported = errors.New("sha256sum is not available on the server")
+
+// TransferDirection tells whether a job copies to or from a server.
+type TransferDirection string
+
+const (
+	TransferPush TransferDirection = "push"
+	TransferPull TransferDirection = "pull"
+)
+
+// TransferState is the lifecycle of a queued copy job.
+type TransferState string
+
+const (
+	TransferQueued  TransferState = "queued"
+	TransferRunning TransferState = "running"
+	TransferDone    TransferState = "done"
+	TransferFailed  TransferState = "failed"
+)
+
+// TransferOptions controls how a batch of copy jobs runs.
+type TransferOptions struct {
+	// Concurrency is the maximum number of hosts copied to at once.
+	Concurrency int
+	// Retries is how often a failed job is tried again.
+	Retries int
+	// Verify compares per-file SHA-256 checksums after copying.
+	Verify bool
+}
+
+// TransferJob copies one path to or from one server.
+type TransferJob struct {
+	ID        int               `json:"id"`
+	Alias     string            `json:"alias"`
+	Direction TransferDirection `json:"direction"`
+	// Source and Dest are a local path and a remote directory for pushes,
+	// and a remote path and a local directory for pulls.
+	Source   string           `json:"source"`
+	Dest     string           `json:"dest"`
+	State    TransferState    `json:"state"`
+	Attempts int              `json:"attempts"`
+	Progress TransferProgress `json:"-"`
+	// Files is the number of files whose checksums matched, or -1 when not verified.
+	Files    int           `json:"verified_files"`
+	Bytes    int64         `json:"bytes"`
+	Duration time.Duration `json:"duration_ns"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Finished reports whether the job will not change anymore.
+func (j TransferJob) Finished() bool {
+	return j.State == TransferDone || j.State == TransferFailed
+}
+
+// PushJobs plans copying localPath into remoteDir on every alias.
+func PushJobs(aliases []string, localPath, remoteDir string) []TransferJob {
+	jobs := make([]TransferJob, 0, len(aliases))
+	for _, alias := range aliases {
+		jobs = append(jobs, TransferJob{
+			Alias:     alias,
+			Direction: TransferPush,
+			Source:    localPath,
+			Dest:      remoteDir,
+			State:     TransferQueued,
+			Files:     -1,
+		})
+	}
+	return jobs
+}
+
+// PullJobs plans copying remotePath from every alias into its own subdirectory of localDir,
+// so files with the same name on different hosts do not collide.
+func PullJobs(aliases []string, remotePath, localDir string) []TransferJob {
+	jobs := make([]TransferJob, 0, len(aliases))
+	for _, alias := range aliases {
+		jobs = append(jobs, TransferJob{
+			Alias:     alias,
+			Direction: TransferPull,
+			Source:    remotePath,
+			Dest:      filepath.Join(localDir, alias),
+			State:     TransferQueued,
+			Files:     -1,
+		})
+	}
+	return jobs
+}
@@ -0,0 +1,123 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TunnelType selects which kind of ssh port forward a tunnel uses.
+type TunnelType string
+
+const (
+	// TunnelLocal forwards a local port to a host reachable from the server (-L).
+	TunnelLocal TunnelType = "local"
+	// TunnelRemote forwards a port on the server back to a local host (-R).
+	TunnelRemote TunnelType = "remote"
+	// TunnelDynamic opens a local SOCKS proxy through the server (-D).
+	TunnelDynamic TunnelType = "dynamic"
+)
+
+// Tunnel is a named port-forward definition stored with a server.
+type Tunnel struct {
+	Name        string
+	Type        TunnelType
+	BindAddress string
+	// ListenPort is the local port for local/dynamic tunnels and the server-side port for remote ones.
+	ListenPort int
+	// TargetHost and TargetPort are unused for dynamic tunnels.
+	TargetHost  string
+	TargetPort  int
+	AutoRestart bool
+	// Persistent tunnels keep running after dogssh exits.
+	Persistent bool
+}
+
+// Validate checks that the tunnel definition can be turned into an ssh flag.
+func (t Tunnel) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("tunnel name is required")
+	}
+	if t.ListenPort < 1 || t.ListenPort > 65535 {
+		return fmt.Errorf("listen port must be between 1 and 65535")
+	}
+	switch t.Type {
+	case TunnelDynamic:
+		return nil
+	case TunnelLocal, TunnelRemote:
+		if t.TargetHost == "" {
+			return fmt.Errorf("target host is required")
+		}
+		if t.TargetPort < 1 || t.TargetPort > 65535 {
+			return fmt.Errorf("target port must be between 1 and 65535")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown tunnel type %q", t.Type)
+	}
+}
+
+// ForwardArgs returns the ssh arguments that establish this forward.
+func (t Tunnel) ForwardArgs() []string {
+	listen := fmt.Sprintf("%d", t.ListenPort)
+	if t.BindAddress != "" {
+		listen = t.BindAddress + ":" + listen
+	}
+	switch t.Type {
+	case TunnelRemote:
+		return []string{"-R", fmt.Sprintf("%s:%s:%d", listen, t.TargetHost, t.TargetPort)}
+	case TunnelDynamic:
+		return []string{"-D", listen}
+	default:
+		return []string{"-L", fmt.Sprintf("%s:%s:%d", listen, t.TargetHost, t.TargetPort)}
+	}
+}
+
+// String renders the forward in the familiar ssh notation, e.g. "L 5432 → db:5432".
+func (t Tunnel) String() string {
+	switch t.Type {
+	case TunnelDynamic:
+		return fmt.Sprintf("D %d (SOCKS)", t.ListenPort)
+	case TunnelRemote:
+		return fmt.Sprintf("R %d → %s:%d", t.ListenPort, t.TargetHost, t.TargetPort)
+	default:
+		return fmt.Sprintf("L %d → %s:%d", t.ListenPort, t.TargetHost, t.TargetPort)
+	}
+}
+
+// TunnelState describes the lifecycle of a tunnel process.
+type TunnelState string
+
+const (
+	TunnelStopped    TunnelState = "stopped"
+	TunnelRunning    TunnelState = "running"
+	TunnelRestarting TunnelState = "restarting"
+	TunnelFailed     TunnelState = "failed"
+)
+
+// TunnelStatus is the live status of a tunnel owned by dogssh.
+type TunnelStatus struct {
+	Alias     string
+	Tunnel    Tunnel
+	State     TunnelState
+	PID       int
+	StartedAt time.Time
+	Restarts  int
+	LastError string
+	// Connections is the number of established connections on the local
+	// listen port, or -1 when it cannot be determined.
+	Connections int
+}
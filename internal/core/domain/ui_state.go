@@ -0,0 +1,25 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// UIState is the part of the TUI restored on the next start.
+type UIState struct {
+	// Selected is the alias of the server under the cursor.
+	Selected string `json:"selected,omitempty"`
+	// Sort is the sort order of the list; an empty field means the configured default.
+	Sort SortOrder `json:"sort"`
+	// Search is the ad-hoc search query, empty when the search bar was closed.
+	Search string `json:"search,omitempty"`
+}
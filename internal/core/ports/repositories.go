@@ -17,14 +17,37 @@ package ports
 import "github.com/ChengzeHsiao/dogssh/internal/core/domain"
 
 type ServerRepository interface {
-	ListServers(query string) ([]domain.Server, error)
+	// ListServers returns every server of the SSH config with its metadata.
+	ListServers() ([]domain.Server, error)
 	UpdateServer(server domain.Server, newServer domain.Server) error
+	// UpdateServers applies several edits, writing and backing up the SSH config once.
+	UpdateServers(updates []domain.ServerUpdate) error
 	AddServer(server domain.Server) error
 	DeleteServer(server domain.Server) error
+	// DeleteServers removes several servers, writing and backing up the SSH config once.
+	DeleteServers(servers []domain.Server) error
 	SetPinned(alias string, pinned bool) error
+	SetPinnedMany(aliases []string, pinned bool) error
+	// HostOptions returns the values of an ssh_config option for each host of the main config file.
+	HostOptions(option string) (map[string][]string, error)
+	// SetHostOptions replaces the values of an option on several hosts with one write of the SSH config.
+	SetHostOptions(option string, values map[string][]string) error
 	RecordSSH(alias string) error
+	// ListBackups returns the timestamped backups of the SSH config, newest first.
+	ListBackups() ([]domain.ConfigBackup, error)
+	// RestoreBackup replaces the SSH config with one of its backups.
+	RestoreBackup(path string) error
+	// SetTunnels replaces the tunnel definitions stored for a server.
+	SetTunnels(alias string, tunnels []domain.Tunnel) error
 	// HasPassword checks if a password is stored for the given server alias.
 	HasPassword(alias string) (bool, error)
 	// GetDecryptedPassword retrieves and decrypts the password for a server.
 	GetDecryptedPassword(alias string) (string, error)
+	// DeletePassword removes the stored password of a server, if any.
+	DeletePassword(alias string) error
+}
+
+// SettingsRepository loads application settings.
+type SettingsRepository interface {
+	Load() (domain.Settings, error)
 }
@@ -15,6 +15,7 @@
 package ports
 
 import (
+	"context"
 	"time"
 
 	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
@@ -22,12 +23,202 @@ import (
 
 type ServerService interface {
 	ListServers(query string) ([]domain.Server, error)
+	// SearchServers returns the servers matching query with fuzzy scores and highlights.
+	SearchServers(query string) ([]domain.SearchMatch, error)
 	UpdateServer(server domain.Server, newServer domain.Server) error
+	// UpdateServers validates and applies several edits with one write of the SSH config.
+	UpdateServers(updates []domain.ServerUpdate) error
 	AddServer(server domain.Server) error
 	DeleteServer(server domain.Server) error
+	// DeleteServers removes several servers with one write of the SSH config.
+	DeleteServers(servers []domain.Server) error
 	SetPinned(alias string, pinned bool) error
+	// SetPinnedMany sets or clears the pin of several servers at once.
+	SetPinnedMany(aliases []string, pinned bool) error
+	// SetTunnels validates and stores the tunnel definitions for a server.
+	SetTunnels(alias string, tunnels []domain.Tunnel) error
 	SSH(alias string) error
 	Ping(server domain.Server) (bool, time.Duration, error)
 	// HasPassword checks if a password is stored for the given server alias.
 	HasPassword(alias string) (bool, error)
+	// RunCommand runs a non-interactive command on many servers and collects the results.
+	RunCommand(ctx context.Context, aliases []string, command string, opts domain.ExecOptions, onResult func(domain.ExecResult)) []domain.ExecResult
+	// ListBackups returns the backups of the SSH config, newest first.
+	ListBackups() ([]domain.ConfigBackup, error)
+	// RestoreBackup replaces the SSH config with a backup after backing up the current file.
+	RestoreBackup(path string) error
+}
+
+// TmuxService opens connections in tmux windows or panes instead of suspending the TUI.
+type TmuxService interface {
+	// Available reports whether dogssh is running inside a tmux session.
+	Available() bool
+	// Open starts a session for alias using the given mode.
+	// If a window or pane for alias already exists, it is focused instead.
+	Open(alias string, mode domain.TmuxMode) error
+	// OpenCluster opens one window named name with a pane per alias and
+	// synchronized input, so keystrokes go to every server at once.
+	OpenCluster(name string, aliases []string) error
+}
+
+// TunnelService runs tunnels as background `ssh -N` processes.
+type TunnelService interface {
+	// Start launches the tunnel for alias. Starting a running tunnel is a no-op.
+	Start(alias string, tunnel domain.Tunnel) error
+	// Stop terminates the tunnel and disables auto-restart for it.
+	Stop(alias, name string) error
+	// Status returns the live status of a tunnel; unknown tunnels are reported as stopped.
+	Status(alias string, tunnel domain.Tunnel) domain.TunnelStatus
+	// Active returns every tunnel that is running or restarting.
+	Active() []domain.TunnelStatus
+	// OnChange registers a callback invoked from a background goroutine whenever a status changes.
+	OnChange(fn func())
+	// Shutdown stops all tunnels that are not marked persistent.
+	Shutdown()
+}
+
+// FileService browses servers and copies files to and from them.
+// Remote paths use forward slashes; local paths use the host's separator.
+type FileService interface {
+	// Home returns the login directory on the server.
+	Home(alias string) (string, error)
+	// List returns the entries of a remote directory, directories first.
+	List(alias, dir string) ([]domain.FileEntry, error)
+	// ListLocal returns the entries of a local directory, directories first.
+	ListLocal(dir string) ([]domain.FileEntry, error)
+	// Upload copies a local file or directory into remoteDir.
+	Upload(ctx context.Context, alias, localPath, remoteDir string, progress func(domain.TransferProgress)) error
+	// Download copies a remote file or directory into localDir.
+	Download(ctx context.Context, alias, remotePath, localDir string, progress func(domain.TransferProgress)) error
+	// DownloadVerified downloads like Download and checks the received archive against
+	// the SHA-256 the server computed while sending it. It returns the number of files
+	// received, or -1 when the server cannot compute checksums.
+	DownloadVerified(ctx context.Context, alias, remotePath, localDir string, progress func(domain.TransferProgress)) (int, error)
+	// Checksums returns the SHA-256 of every file under a remote path, keyed by the
+	// slash-separated path relative to its parent directory.
+	Checksums(ctx context.Context, alias, path string) (map[string]string, error)
+	// ReadFile fetches a remote file together with its permission bits.
+	ReadFile(ctx context.Context, alias, path string) (domain.RemoteFile, error)
+	// WriteFile atomically replaces the remote file with content, keeping original.Mode
+	// and the file's owner.
+	// It returns domain.ErrRemoteFileChanged if the file differs from original.Content.
+	WriteFile(ctx context.Context, original domain.RemoteFile, content []byte) error
+	Rename(alias, from, to string) error
+	// Remove deletes a remote file or directory recursively.
+	Remove(alias, path string) error
+	Mkdir(alias, path string) error
+}
+
+// TransferService runs batches of copy jobs across many servers.
+type TransferService interface {
+	// Run executes jobs with bounded concurrency and retries. onUpdate, if set,
+	// is called whenever a job changes state or makes progress. A leading ~ in
+	// the local path of a job is expanded to the home directory.
+	Run(ctx context.Context, jobs []domain.TransferJob, opts domain.TransferOptions, onUpdate func(domain.TransferJob)) []domain.TransferJob
+}
+
+// KeyService inspects and creates the user's SSH keys.
+type KeyService interface {
+	// List returns the private keys in the ssh directory and the servers referencing them.
+	List() ([]domain.SSHKey, error)
+	// Generate creates a new key pair in OpenSSH format.
+	Generate(opts domain.KeyGenOptions) (domain.SSHKey, error)
+	// IdentityIssues reports missing identity files or files with unsafe permissions.
+	IdentityIssues(server domain.Server) []string
+	// InstallKey appends a key's public half to a server's authorized_keys and verifies key login.
+	InstallKey(ctx context.Context, alias, keyPath string) domain.KeyInstallResult
+	// VerifyKey checks that a batch-mode login offering only keyPath succeeds.
+	VerifyKey(ctx context.Context, alias, keyPath string) error
+	// RemoveKey deletes a key's public half from a server's authorized_keys and reports whether it was there.
+	RemoveKey(ctx context.Context, alias, keyPath string) (bool, error)
+	// ReplaceKey swaps one identity file of a server for another.
+	ReplaceKey(alias, oldKeyPath, newKeyPath string) error
+	// UseKey makes keyPath the server's identity file and deletes its stored password.
+	UseKey(alias, keyPath string) error
+}
+
+// RotationService replaces an SSH key on every server that uses it.
+type RotationService interface {
+	// Pending returns unfinished rotations, newest first.
+	Pending() ([]domain.KeyRotation, error)
+	// Start generates the new key and plans the rotation of oldKey.
+	Start(oldKey string, opts domain.KeyGenOptions) (domain.KeyRotation, error)
+	// Run performs the remaining steps and reports progress after each host step.
+	Run(ctx context.Context, rotation domain.KeyRotation, onUpdate func(domain.KeyRotation)) (domain.KeyRotation, error)
+	// ReportPath returns the file holding the per-host report of a rotation.
+	ReportPath(id string) string
+}
+
+// KnownHostsService reads and edits ~/.ssh/known_hosts.
+type KnownHostsService interface {
+	// List returns every entry of the known_hosts file.
+	List() ([]domain.KnownHost, error)
+	// Lookup returns the entries ssh would use to verify a server, hashed ones included.
+	Lookup(server domain.Server) ([]domain.KnownHost, error)
+	// Check scans a server's current host keys and compares them with the recorded ones.
+	Check(ctx context.Context, server domain.Server) (domain.HostKeyCheck, error)
+	// Accept replaces the recorded entries of a check with its scanned keys.
+	Accept(check domain.HostKeyCheck) error
+	// Remove deletes entries from the known_hosts file.
+	Remove(entries []domain.KnownHost) error
+}
+
+// AgentService talks to the running ssh-agent.
+type AgentService interface {
+	// List returns the identities held by the agent.
+	List() ([]domain.AgentIdentity, error)
+	// Add loads a private key into the agent; a zero lifetime keeps it until removed.
+	Add(keyPath, passphrase string, lifetime time.Duration) error
+	// Remove unloads an identity from the agent.
+	Remove(identity domain.AgentIdentity) error
+	// KeyStatus reports whether each of a server's identity files is loaded in the agent.
+	KeyStatus(server domain.Server) ([]domain.AgentKeyStatus, error)
+}
+
+// CertificateService inspects and renews the SSH certificates servers log in with.
+type CertificateService interface {
+	// Inspect returns the certificate a server presents and false when it uses none.
+	Inspect(server domain.Server) (domain.Certificate, bool)
+	// Status returns the state of a server's certificate, or "" when it uses none.
+	Status(server domain.Server) domain.CertificateStatus
+	// CanRenew reports whether a renew command is configured.
+	CanRenew() bool
+	// RenewIfNeeded runs the renew command on the terminal when a server's certificate has expired.
+	RenewIfNeeded(alias string) (bool, error)
+}
+
+// CommandHistoryService remembers the commands entered in the command palette.
+type CommandHistoryService interface {
+	// List returns the remembered commands, most recent first.
+	List() ([]string, error)
+	// Add records a command as the most recent one.
+	Add(command string) error
+}
+
+// UIStateService remembers the selection, sort and search of the TUI between runs.
+type UIStateService interface {
+	// Load returns the state saved by the last run.
+	Load() (domain.UIState, error)
+	// Save replaces the stored state.
+	Save(state domain.UIState) error
+}
+
+// ViewService stores named search queries.
+type ViewService interface {
+	// List returns the saved views in the order they were created.
+	List() ([]domain.SavedView, error)
+	// Save stores a view, replacing the one called previous when it is set.
+	Save(previous string, view domain.SavedView) error
+	// Delete removes a saved view.
+	Delete(name string) error
+	// Counts returns how many of servers each view matches; -1 marks an invalid query.
+	Counts(views []domain.SavedView, servers []domain.Server) []int
+}
+
+// BulkEditService rewrites one ssh_config option across many servers.
+type BulkEditService interface {
+	// Preview computes the before and after values of every server in scope without writing.
+	Preview(servers []domain.Server, edit domain.BulkEdit) (domain.BulkPreview, error)
+	// Apply writes the changes of a valid preview as one backed-up SSH config write.
+	Apply(preview domain.BulkPreview) error
 }
@@ -0,0 +1,156 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+type agentService struct {
+	logger *zap.SugaredLogger
+	socket string
+}
+
+// NewAgentService creates a client for the agent listening on socket, usually $SSH_AUTH_SOCK.
+func NewAgentService(logger *zap.SugaredLogger, socket string) ports.AgentService {
+	return &agentService{
+		logger: logger,
+		socket: socket,
+	}
+}
+
+// List returns the identities held by the agent.
+func (s *agentService) List() ([]domain.AgentIdentity, error) {
+	var identities []domain.AgentIdentity
+	err := s.withAgent(func(client agent.ExtendedAgent) error {
+		keys, err := client.List()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			identities = append(identities, domain.AgentIdentity{
+				Type:        key.Format,
+				Fingerprint: ssh.FingerprintSHA256(key),
+				Comment:     key.Comment,
+				Blob:        key.Blob,
+			})
+		}
+		return nil
+	})
+	return identities, err
+}
+
+// Add loads the private key at keyPath into the agent, for lifetime if it is not zero.
+func (s *agentService) Add(keyPath, passphrase string, lifetime time.Duration) error {
+	keyPath = ExpandHome(keyPath)
+	data, err := os.ReadFile(keyPath) //nolint:gosec // G304: the user's own key
+	if err != nil {
+		return err
+	}
+
+	var privateKey any
+	if passphrase == "" {
+		privateKey, err = ssh.ParseRawPrivateKey(data)
+	} else {
+		privateKey, err = ssh.ParseRawPrivateKeyWithPassphrase(data, []byte(passphrase))
+	}
+	var missing *ssh.PassphraseMissingError
+	switch {
+	case errors.As(err, &missing):
+		return fmt.Errorf("%s is passphrase-protected; enter its passphrase", filepath.Base(keyPath))
+	case errors.Is(err, x509.IncorrectPasswordError):
+		return fmt.Errorf("wrong passphrase for %s", filepath.Base(keyPath))
+	case err != nil:
+		return fmt.Errorf("parse %s: %w", filepath.Base(keyPath), err)
+	}
+
+	comment := readKey(keyPath).Comment
+	if comment == "" {
+		comment = keyPath
+	}
+	return s.withAgent(func(client agent.ExtendedAgent) error {
+		if err := client.Add(agent.AddedKey{
+			PrivateKey:   privateKey,
+			Comment:      comment,
+			LifetimeSecs: uint32(lifetime / time.Second),
+		}); err != nil {
+			return err
+		}
+		s.logger.Infow("key added to agent", "key", keyPath, "lifetime", lifetime)
+		return nil
+	})
+}
+
+// Remove unloads identity from the agent.
+func (s *agentService) Remove(identity domain.AgentIdentity) error {
+	key, err := ssh.ParsePublicKey(identity.Blob)
+	if err != nil {
+		return err
+	}
+	return s.withAgent(func(client agent.ExtendedAgent) error {
+		return client.Remove(key)
+	})
+}
+
+// KeyStatus reports for each of the server's identity files whether the agent holds it.
+// It returns domain.ErrAgentUnavailable when there is no agent to ask.
+func (s *agentService) KeyStatus(server domain.Server) ([]domain.AgentKeyStatus, error) {
+	identities, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[string]bool, len(identities))
+	for _, identity := range identities {
+		loaded[identity.Fingerprint] = true
+	}
+
+	statuses := make([]domain.AgentKeyStatus, 0, len(server.IdentityFiles))
+	for _, identity := range server.IdentityFiles {
+		p := ExpandHome(identity)
+		key := readKey(p)
+		statuses = append(statuses, domain.AgentKeyStatus{
+			Path:       identity,
+			Loaded:     key.Fingerprint != "" && loaded[key.Fingerprint],
+			Encrypted:  key.Encrypted,
+			Unreadable: key.Fingerprint == "",
+		})
+	}
+	return statuses, nil
+}
+
+func (s *agentService) withAgent(fn func(agent.ExtendedAgent) error) error {
+	if s.socket == "" {
+		return domain.ErrAgentUnavailable
+	}
+	conn, err := net.DialTimeout("unix", s.socket, 2*time.Second)
+	if err != nil {
+		return fmt.Errorf("connect to ssh-agent: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	return fn(agent.NewClient(conn))
+}
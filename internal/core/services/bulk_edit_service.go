@@ -0,0 +1,159 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// optionKeyword matches the shape of an ssh_config keyword.
+var optionKeyword = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+type bulkEditService struct {
+	logger *zap.SugaredLogger
+	repo   ports.ServerRepository
+}
+
+// NewBulkEditService creates a service for find-and-replace across many servers.
+func NewBulkEditService(logger *zap.SugaredLogger, repo ports.ServerRepository) ports.BulkEditService {
+	return &bulkEditService{logger: logger, repo: repo}
+}
+
+// Preview computes what edit would change on servers without writing anything.
+func (s *bulkEditService) Preview(servers []domain.Server, edit domain.BulkEdit) (domain.BulkPreview, error) {
+	preview := domain.BulkPreview{Edit: edit}
+	replace, err := bulkReplacer(edit)
+	if err != nil {
+		return preview, err
+	}
+	options, err := s.repo.HostOptions(edit.Option)
+	if err != nil {
+		return preview, err
+	}
+
+	for _, server := range servers {
+		before, ok := options[server.Alias]
+		if !ok {
+			preview.Skipped = append(preview.Skipped, server.Alias)
+			continue
+		}
+		after := make([]string, 0, len(before))
+		changed := false
+		for _, value := range before {
+			next := replace(value)
+			changed = changed || next != value
+			if next != "" {
+				after = append(after, next)
+			}
+		}
+		if !changed {
+			preview.Unchanged++
+			continue
+		}
+		change := domain.BulkChange{Alias: server.Alias, Before: before, After: after}
+		if err := validateOptionValues(edit.Option, after); err != nil {
+			change.Error = err.Error()
+		}
+		preview.Changes = append(preview.Changes, change)
+	}
+	return preview, nil
+}
+
+// Apply writes every change of preview with a single backed-up write of the SSH config.
+// It refuses when a server's values no longer match the preview, so edits made to the
+// config in the meantime are not overwritten.
+func (s *bulkEditService) Apply(preview domain.BulkPreview) error {
+	if len(preview.Changes) == 0 {
+		return fmt.Errorf("nothing to change")
+	}
+	if !preview.Valid() {
+		return fmt.Errorf("some changes are invalid")
+	}
+	current, err := s.repo.HostOptions(preview.Edit.Option)
+	if err != nil {
+		return err
+	}
+	var stale []string
+	values := make(map[string][]string, len(preview.Changes))
+	for _, change := range preview.Changes {
+		if before, ok := current[change.Alias]; !ok || !slices.Equal(before, change.Before) {
+			stale = append(stale, change.Alias)
+		}
+		values[change.Alias] = change.After
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("%s of %s changed since the preview; preview again", preview.Edit.Option, strings.Join(stale, ", "))
+	}
+	if err := s.repo.SetHostOptions(preview.Edit.Option, values); err != nil {
+		s.logger.Errorw("bulk edit failed", "option", preview.Edit.Option, "count", len(values), "error", err)
+		return err
+	}
+	s.logger.Infow("bulk edit applied", "option", preview.Edit.Option, "count", len(values))
+	return nil
+}
+
+// bulkReplacer validates edit and returns the function rewriting one value.
+func bulkReplacer(edit domain.BulkEdit) (func(string) string, error) {
+	if !optionKeyword.MatchString(edit.Option) {
+		return nil, fmt.Errorf("option must be an ssh_config keyword such as HostName or ProxyJump")
+	}
+	switch strings.ToLower(edit.Option) {
+	case "host", "match", "include":
+		return nil, fmt.Errorf("%s cannot be bulk edited", edit.Option)
+	}
+	if edit.Find == "" {
+		return nil, fmt.Errorf("find is required")
+	}
+	if !edit.Regex {
+		return func(value string) string {
+			return strings.ReplaceAll(value, edit.Find, edit.Replace)
+		}, nil
+	}
+	re, err := regexp.Compile(edit.Find)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return func(value string) string {
+		return re.ReplaceAllString(value, edit.Replace)
+	}, nil
+}
+
+// validateOptionValues checks new values of the options dogssh itself relies on.
+func validateOptionValues(option string, values []string) error {
+	switch strings.ToLower(option) {
+	case "port":
+		for _, value := range values {
+			port, err := strconv.Atoi(value)
+			if err != nil || port < 1 || port > 65535 {
+				return fmt.Errorf("port must be a number between 1 and 65535")
+			}
+		}
+	case "hostname", "user":
+		for _, value := range values {
+			if strings.ContainsAny(value, " \t") {
+				return fmt.Errorf("%s cannot contain spaces", option)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// optionsRepo serves HostOptions from a map and records SetHostOptions calls.
+type optionsRepo struct {
+	ports.ServerRepository
+	options map[string][]string
+	writes  int
+	written map[string][]string
+}
+
+func (r *optionsRepo) HostOptions(string) (map[string][]string, error) {
+	return r.options, nil
+}
+
+func (r *optionsRepo) SetHostOptions(_ string, values map[string][]string) error {
+	r.writes++
+	r.written = values
+	return nil
+}
+
+func TestBulkEditPreviewAndApply(t *testing.T) {
+	repo := &optionsRepo{options: map[string][]string{
+		"web1": {"web1.old.example.com"},
+		"web2": {"web2.old.example.com"},
+		"db1":  {"db1.other.net"},
+		"bare": {},
+	}}
+	s := NewBulkEditService(zap.NewNop().Sugar(), repo)
+	servers := []domain.Server{{Alias: "web1"}, {Alias: "web2"}, {Alias: "db1"}, {Alias: "bare"}, {Alias: "included"}}
+
+	preview, err := s.Preview(servers, domain.BulkEdit{Option: "HostName", Find: `\.old\.example\.com$`, Replace: ".new.example.com", Regex: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preview.Changes) != 2 || preview.Unchanged != 2 || !reflect.DeepEqual(preview.Skipped, []string{"included"}) {
+		t.Fatalf("preview = %+v", preview)
+	}
+	if got := preview.Changes[0].After; !reflect.DeepEqual(got, []string{"web1.new.example.com"}) {
+		t.Fatalf("after = %v", got)
+	}
+
+	if err := s.Apply(preview); err != nil {
+		t.Fatal(err)
+	}
+	if repo.writes != 1 || len(repo.written) != 2 || repo.written["web2"][0] != "web2.new.example.com" {
+		t.Fatalf("writes = %d, written = %v", repo.writes, repo.written)
+	}
+}
+
+func TestBulkEditValidation(t *testing.T) {
+	repo := &optionsRepo{options: map[string][]string{"web1": {"2222"}}}
+	s := NewBulkEditService(zap.NewNop().Sugar(), repo)
+	servers := []domain.Server{{Alias: "web1"}}
+
+	for _, edit := range []domain.BulkEdit{
+		{Option: "Port", Find: ""},
+		{Option: "Host", Find: "a"},
+		{Option: "Proxy Jump", Find: "a"},
+		{Option: "Port", Find: "(", Regex: true},
+	} {
+		if _, err := s.Preview(servers, edit); err == nil {
+			t.Errorf("Preview(%+v) succeeded, want error", edit)
+		}
+	}
+
+	preview, err := s.Preview(servers, domain.BulkEdit{Option: "Port", Find: "2222", Replace: "ssh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Valid() {
+		t.Fatalf("expected an invalid port change: %+v", preview)
+	}
+	if err := s.Apply(preview); err == nil || repo.writes != 0 {
+		t.Fatalf("Apply of an invalid preview: err = %v, writes = %d", err, repo.writes)
+	}
+}
+
+func TestBulkEditApplyRefusesStalePreview(t *testing.T) {
+	repo := &optionsRepo{options: map[string][]string{
+		"web1": {"web1.old.example.com"},
+		"web2": {"web2.old.example.com"},
+	}}
+	s := NewBulkEditService(zap.NewNop().Sugar(), repo)
+	servers := []domain.Server{{Alias: "web1"}, {Alias: "web2"}}
+
+	preview, err := s.Preview(servers, domain.BulkEdit{Option: "HostName", Find: "old", Replace: "new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Someone edits web2 after the preview was shown.
+	repo.options = map[string][]string{
+		"web1": {"web1.old.example.com"},
+		"web2": {"web2.moved.example.com"},
+	}
+	if err := s.Apply(preview); err == nil || !strings.Contains(err.Error(), "web2") || repo.writes != 0 {
+		t.Fatalf("Apply of a stale preview: err = %v, writes = %d", err, repo.writes)
+	}
+
+	// A host removed from the config since the preview is stale as well.
+	delete(repo.options, "web2")
+	if err := s.Apply(preview); err == nil || repo.writes != 0 {
+		t.Fatalf("Apply after a host was removed: err = %v, writes = %d", err, repo.writes)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+type certificateService struct {
+	logger           *zap.SugaredLogger
+	serverRepository ports.ServerRepository
+	settings         domain.CertificateSettings
+
+	mu    sync.Mutex
+	cache map[string]cachedCertificate
+}
+
+// cachedCertificate avoids re-parsing a certificate file that has not changed.
+type cachedCertificate struct {
+	modTime time.Time
+	size    int64
+	cert    domain.Certificate
+}
+
+// NewCertificateService creates a service that inspects and renews SSH certificates.
+func NewCertificateService(logger *zap.SugaredLogger, sr ports.ServerRepository, settings domain.CertificateSettings) ports.CertificateService {
+	return &certificateService{
+		logger:           logger,
+		serverRepository: sr,
+		settings:         settings,
+		cache:            make(map[string]cachedCertificate),
+	}
+}
+
+// Inspect returns the certificate server presents and false when it uses none.
+func (s *certificateService) Inspect(server domain.Server) (domain.Certificate, bool) {
+	p := certificatePath(server)
+	if p == "" {
+		return domain.Certificate{}, false
+	}
+	return s.read(p), true
+}
+
+// Status returns the state of server's certificate, or "" when it uses none.
+func (s *certificateService) Status(server domain.Server) domain.CertificateStatus {
+	cert, ok := s.Inspect(server)
+	if !ok {
+		return ""
+	}
+	return cert.Status(time.Now(), s.settings.WarnBefore)
+}
+
+// CanRenew reports whether a renew command is configured.
+func (s *certificateService) CanRenew() bool {
+	return strings.TrimSpace(s.settings.RenewCommand) != ""
+}
+
+// RenewIfNeeded runs the renew command on the terminal when alias's certificate has expired.
+func (s *certificateService) RenewIfNeeded(alias string) (bool, error) {
+	if !s.CanRenew() {
+		return false, nil
+	}
+	server, err := findServer(s.serverRepository, alias)
+	if err != nil {
+		return false, err
+	}
+	if !s.Status(server).NeedsRenewal() {
+		return false, nil
+	}
+
+	certPath := certificatePath(server)
+	identity := ""
+	if len(server.IdentityFiles) > 0 {
+		identity = ExpandHome(server.IdentityFiles[0])
+	}
+	s.logger.Infow("renewing certificate", "alias", alias, "path", certPath)
+
+	cmd := shellCommand(s.settings.RenewCommand)
+	cmd.Env = append(os.Environ(),
+		"DOGSSH_ALIAS="+alias,
+		"DOGSSH_CERT="+certPath,
+		"DOGSSH_IDENTITY="+identity,
+	)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		s.logger.Warnw("certificate renew command failed", "alias", alias, "error", err)
+		return false, fmt.Errorf("renew command: %w", err)
+	}
+
+	if status := s.Status(server); status.NeedsRenewal() {
+		return true, fmt.Errorf("certificate is still %s after the renew command", status)
+	}
+	return true, nil
+}
+
+// read parses the certificate at p, reusing the cached result while the file is unchanged.
+func (s *certificateService) read(p string) domain.Certificate {
+	info, err := os.Stat(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return domain.Certificate{Path: p, Missing: true}
+		}
+		return domain.Certificate{Path: p, Error: err.Error()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached, ok := s.cache[p]; ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.cert
+	}
+	cert := parseCertificateFile(p)
+	s.cache[p] = cachedCertificate{modTime: info.ModTime(), size: info.Size(), cert: cert}
+	return cert
+}
+
+// certificatePath returns the certificate ssh would offer for server: the configured
+// CertificateFile, or the first existing <identity>-cert.pub that ssh loads implicitly.
+func certificatePath(server domain.Server) string {
+	if server.CertificateFile != "" {
+		return ExpandHome(server.CertificateFile)
+	}
+	for _, identity := range server.IdentityFiles {
+		if p := ExpandHome(identity) + "-cert.pub"; fileExists(p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// parseCertificateFile reads an OpenSSH certificate in authorized_keys format.
+func parseCertificateFile(p string) domain.Certificate {
+	result := domain.Certificate{Path: p}
+	data, err := os.ReadFile(p) //nolint:gosec // G304: path comes from the user's SSH config
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	cert, ok := publicKey.(*ssh.Certificate)
+	if !ok {
+		result.Error = "not a certificate (" + publicKey.Type() + ")"
+		return result
+	}
+
+	result.Type = "user"
+	if cert.CertType == ssh.HostCert {
+		result.Type = "host"
+	}
+	result.KeyID = cert.KeyId
+	result.Serial = cert.Serial
+	result.Principals = cert.ValidPrincipals
+	if cert.ValidAfter != 0 {
+		result.ValidAfter = certTime(cert.ValidAfter)
+	}
+	if cert.ValidBefore != ssh.CertTimeInfinity {
+		result.ValidBefore = certTime(cert.ValidBefore)
+	}
+	result.CAFingerprint = ssh.FingerprintSHA256(cert.SignatureKey)
+	return result
+}
+
+// certTime converts a certificate timestamp, clamping values beyond the range of time.Unix.
+func certTime(t uint64) time.Time {
+	if t > math.MaxInt64 {
+		t = math.MaxInt64
+	}
+	return time.Unix(int64(t), 0)
+}
+
+// shellCommand runs command through the platform shell.
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command) //nolint:gosec // G204: command comes from the user's config
+	}
+	return exec.Command("sh", "-c", command) //nolint:gosec // G204: command comes from the user's config
+}
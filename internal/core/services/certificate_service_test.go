@@ -0,0 +1,111 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+func writeTestCertificate(t *testing.T, path string, validAfter, validBefore uint64) {
+	t.Helper()
+	_, caKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := &ssh.Certificate{
+		Key:             testHostKey(t, 7),
+		Serial:          42,
+		CertType:        ssh.UserCert,
+		KeyId:           "alice@laptop",
+		ValidPrincipals: []string{"alice", "deploy"},
+		ValidAfter:      validAfter,
+		ValidBefore:     validBefore,
+	}
+	if err := cert.SignCert(rand.Reader, signer); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, ssh.MarshalAuthorizedKey(cert), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertificateInspect(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	s := NewCertificateService(zap.NewNop().Sugar(), nil, domain.CertificateSettings{WarnBefore: time.Hour})
+
+	explicit := filepath.Join(dir, "explicit-cert.pub")
+	writeTestCertificate(t, explicit, uint64(now.Add(-time.Hour).Unix()), uint64(now.Add(-time.Minute).Unix()))
+	cert, ok := s.Inspect(domain.Server{CertificateFile: explicit})
+	if !ok {
+		t.Fatal("Inspect() reported no certificate for an explicit CertificateFile")
+	}
+	if cert.KeyID != "alice@laptop" || cert.Type != "user" || cert.Serial != 42 || len(cert.Principals) != 2 {
+		t.Errorf("Inspect() = %+v", cert)
+	}
+	if got := s.Status(domain.Server{CertificateFile: explicit}); got != domain.CertificateExpired {
+		t.Errorf("Status() = %q, want %q", got, domain.CertificateExpired)
+	}
+
+	identity := filepath.Join(dir, "id_ed25519")
+	writeTestCertificate(t, identity+"-cert.pub", 0, uint64(now.Add(30*time.Minute).Unix()))
+	implicit := domain.Server{IdentityFiles: []string{identity}}
+	if got := s.Status(implicit); got != domain.CertificateExpiring {
+		t.Errorf("Status() of implicit certificate = %q, want %q", got, domain.CertificateExpiring)
+	}
+
+	if got := s.Status(domain.Server{CertificateFile: filepath.Join(dir, "missing-cert.pub")}); got != domain.CertificateMissing {
+		t.Errorf("Status() of missing file = %q, want %q", got, domain.CertificateMissing)
+	}
+	if _, ok := s.Inspect(domain.Server{IdentityFiles: []string{filepath.Join(dir, "other")}}); ok {
+		t.Error("Inspect() found a certificate for a server without one")
+	}
+}
+
+func TestCertificateStatus(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		cert domain.Certificate
+		want domain.CertificateStatus
+	}{
+		{"forever", domain.Certificate{Type: "user"}, domain.CertificateValid},
+		{"valid", domain.Certificate{ValidBefore: now.Add(2 * time.Hour)}, domain.CertificateValid},
+		{"expiring", domain.Certificate{ValidBefore: now.Add(time.Hour)}, domain.CertificateExpiring},
+		{"expired", domain.Certificate{ValidBefore: now}, domain.CertificateExpired},
+		{"not yet valid", domain.Certificate{ValidAfter: now.Add(time.Minute)}, domain.CertificateNotYetValid},
+		{"invalid", domain.Certificate{Error: "not a certificate"}, domain.CertificateInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cert.Status(now, time.Hour); got != tt.want {
+				t.Errorf("Status() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
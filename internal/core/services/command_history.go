@@ -0,0 +1,108 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// maxCommandHistory is how many palette commands are kept.
+const maxCommandHistory = 100
+
+type commandHistory struct {
+	logger   *zap.SugaredLogger
+	filePath string
+	mu       sync.Mutex
+}
+
+// NewCommandHistory creates a history of palette commands stored one per line in filePath.
+func NewCommandHistory(logger *zap.SugaredLogger, filePath string) ports.CommandHistoryService {
+	return &commandHistory{logger: logger, filePath: filePath}
+}
+
+// List returns the remembered commands, most recent first.
+func (h *commandHistory) List() ([]string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lines, err := h.load()
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines, nil
+}
+
+// Add remembers command as the most recent entry, dropping an earlier copy of it.
+func (h *commandHistory) Add(command string) error {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lines, err := h.load()
+	if err != nil {
+		return err
+	}
+	kept := make([]string, 0, len(lines)+1)
+	for _, line := range lines {
+		if line != command {
+			kept = append(kept, line)
+		}
+	}
+	kept = append(kept, command)
+	if len(kept) > maxCommandHistory {
+		kept = kept[len(kept)-maxCommandHistory:]
+	}
+	return h.write(kept)
+}
+
+func (h *commandHistory) load() ([]string, error) {
+	data, err := os.ReadFile(h.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read command history '%s': %w", h.filePath, err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func (h *commandHistory) write(lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(h.filePath), 0o700); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", filepath.Dir(h.filePath), err)
+	}
+	tmp := h.filePath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, h.filePath)
+}
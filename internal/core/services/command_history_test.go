@@ -0,0 +1,50 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestCommandHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "command_history")
+	h := NewCommandHistory(zap.NewNop().Sugar(), path)
+
+	for _, command := range []string{"sort lastseen", "connect web1", " ", "sort lastseen"} {
+		if err := h.Add(command); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := NewCommandHistory(zap.NewNop().Sugar(), path).List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"sort lastseen", "connect web1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+
+	for i := 0; i < maxCommandHistory+5; i++ {
+		_ = h.Add("exec echo " + strconv.Itoa(i))
+	}
+	got, _ = h.List()
+	if len(got) != maxCommandHistory || got[0] != "exec echo "+strconv.Itoa(maxCommandHistory+4) {
+		t.Fatalf("history has %d entries starting with %q", len(got), got[0])
+	}
+}
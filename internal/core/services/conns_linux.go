@@ -0,0 +1,60 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package services
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tcpEstablished is the state code for ESTABLISHED in /proc/net/tcp.
+const tcpEstablished = "01"
+
+// countEstablished counts established TCP connections whose local port is port.
+func countEstablished(port int) int {
+	count := 0
+	found := false
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		found = true
+		scanner := bufio.NewScanner(f)
+		scanner.Scan() // header
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 4 || fields[3] != tcpEstablished {
+				continue
+			}
+			_, portHex, ok := strings.Cut(fields[1], ":")
+			if !ok {
+				continue
+			}
+			if p, err := strconv.ParseInt(portHex, 16, 32); err == nil && int(p) == port {
+				count++
+			}
+		}
+		_ = f.Close()
+	}
+	if !found {
+		return -1
+	}
+	return count
+}
@@ -0,0 +1,138 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+)
+
+// defaultExecConcurrency is used when ExecOptions.Concurrency is not set.
+const defaultExecConcurrency = 10
+
+// execWaitDelay bounds how long a killed command may keep its output open. A ProxyCommand
+// or other child of ssh can hold stdout after ssh itself was killed on timeout.
+const execWaitDelay = 3 * time.Second
+
+// RunCommand runs command non-interactively on every alias with bounded concurrency.
+// onResult, if set, is called as each host finishes. The returned results list
+// failures first, then successes, each ordered by alias.
+func (s *serverService) RunCommand(ctx context.Context, aliases []string, command string, opts domain.ExecOptions, onResult func(domain.ExecResult)) []domain.ExecResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultExecConcurrency
+	}
+	s.logger.Infow("exec start", "command", command, "hosts", len(aliases), "concurrency", concurrency, "timeout", opts.Timeout)
+
+	results := make([]domain.ExecResult, len(aliases))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i, alias := range aliases {
+		wg.Add(1)
+		go func(i int, alias string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := s.runOne(ctx, alias, command, opts.Timeout)
+			results[i] = result
+			if onResult != nil {
+				mu.Lock()
+				onResult(result)
+				mu.Unlock()
+			}
+		}(i, alias)
+	}
+	wg.Wait()
+
+	domain.SortExecResults(results)
+	s.logger.Infow("exec end", "command", command, "hosts", len(aliases))
+	return results
+}
+
+// runOne executes command on a single host in batch mode.
+func (s *serverService) runOne(ctx context.Context, alias, command string, timeout time.Duration) domain.ExecResult {
+	result := domain.ExecResult{Alias: alias, Command: command}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := s.batchSSHCommand(ctx, alias, command)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result.Duration = time.Since(start)
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+		result.Error = err.Error()
+	}
+
+	if result.Failed() {
+		s.logger.Warnw("exec failed", "alias", alias, "exit_code", result.ExitCode, "error", result.Error)
+	}
+	return result
+}
+
+// batchSSHCommand builds a non-interactive ssh command for alias.
+// A stored password is supplied through sshpass when available; otherwise
+// BatchMode makes ssh fail instead of prompting.
+func (s *serverService) batchSSHCommand(ctx context.Context, alias string, remote ...string) *exec.Cmd {
+	return batchSSH(ctx, s.serverRepository, alias, append([]string{alias, "--"}, remote...)...)
+}
+
+// batchSSH returns `ssh -o BatchMode=yes <args>`, wrapped in sshpass when a
+// password is stored for alias. args must contain the destination itself.
+func batchSSH(ctx context.Context, repo ports.ServerRepository, alias string, args ...string) *exec.Cmd {
+	//nolint:gosec // G204: arguments are built from the user's own SSH config
+	cmd := exec.CommandContext(ctx, "ssh", append([]string{"-o", "BatchMode=yes"}, args...)...)
+	if hasPassword, err := repo.HasPassword(alias); err == nil && hasPassword {
+		if _, err := exec.LookPath("sshpass"); err == nil {
+			if password, err := repo.GetDecryptedPassword(alias); err == nil {
+				sshArgs := append([]string{"-p", password, "ssh", "-o", "BatchMode=no"}, args...)
+				//nolint:gosec // G204: arguments are built from the user's own SSH config
+				cmd = exec.CommandContext(ctx, "sshpass", sshArgs...)
+			}
+		}
+	}
+	cmd.WaitDelay = execWaitDelay
+	return cmd
+}
@@ -0,0 +1,149 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// fakeSSH runs commands as `ssh -o BatchMode=yes <alias> -- <command>` would, acting on
+// the alias: ok* succeeds, fail* exits 3, slow* hangs and hold* leaves a child holding
+// its output open. Every run is tracked in $EXEC_DIR to measure concurrency.
+const fakeSSH = `#!/bin/sh
+alias=$3
+touch "$EXEC_DIR/running.$$"
+ls "$EXEC_DIR" | grep -c '^running\.' >> "$EXEC_DIR/counts"
+trap 'rm -f "$EXEC_DIR/running.$$"' EXIT
+case $alias in
+ok*) sleep 0.2; echo "out $alias" ;;
+fail*) echo "boom" >&2; exit 3 ;;
+slow*) exec sleep 10 ;;
+hold*) sleep 10 & wait ;;
+esac
+`
+
+type noPasswordRepo struct {
+	ports.ServerRepository
+}
+
+func (noPasswordRepo) HasPassword(string) (bool, error) { return false, nil }
+
+func newExecFixture(t *testing.T) (*serverService, string) {
+	t.Helper()
+	for _, tool := range []string{"sh", "sleep", "grep", "ls"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+	bin, dir := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "ssh"), []byte(fakeSSH), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("EXEC_DIR", dir)
+	s := NewServerService(zap.NewNop().Sugar(), noPasswordRepo{}).(*serverService)
+	return s, dir
+}
+
+func TestRunCommandOrdersFailuresFirst(t *testing.T) {
+	s, _ := newExecFixture(t)
+
+	var seen []string
+	results := s.RunCommand(context.Background(), []string{"ok-b", "fail-b", "ok-a", "fail-a"}, "uptime",
+		domain.ExecOptions{}, func(r domain.ExecResult) { seen = append(seen, r.Alias) })
+
+	var aliases []string
+	for _, r := range results {
+		aliases = append(aliases, r.Alias)
+	}
+	if want := []string{"fail-a", "fail-b", "ok-a", "ok-b"}; !slices.Equal(aliases, want) {
+		t.Fatalf("order = %v, want %v", aliases, want)
+	}
+	if len(seen) != 4 {
+		t.Fatalf("onResult called for %v", seen)
+	}
+	if r := results[0]; r.ExitCode != 3 || strings.TrimSpace(r.Stderr) != "boom" || r.Command != "uptime" {
+		t.Fatalf("failed result = %+v", r)
+	}
+	if r := results[2]; r.Failed() || strings.TrimSpace(r.Stdout) != "out ok-a" {
+		t.Fatalf("ok result = %+v", r)
+	}
+}
+
+func TestRunCommandBoundsConcurrency(t *testing.T) {
+	s, dir := newExecFixture(t)
+
+	aliases := []string{"ok1", "ok2", "ok3", "ok4", "ok5", "ok6"}
+	results := s.RunCommand(context.Background(), aliases, "true", domain.ExecOptions{Concurrency: 2}, nil)
+	for _, r := range results {
+		if r.Failed() {
+			t.Fatalf("%s failed: %+v", r.Alias, r)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "counts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Fields(string(data)) {
+		if n, _ := strconv.Atoi(line); n > 2 {
+			t.Fatalf("%d hosts ran at once, want at most 2 (counts %q)", n, data)
+		}
+	}
+}
+
+func TestRunCommandTimesOutPerHost(t *testing.T) {
+	s, _ := newExecFixture(t)
+
+	start := time.Now()
+	results := s.RunCommand(context.Background(), []string{"slow", "ok"}, "true",
+		domain.ExecOptions{Timeout: 500 * time.Millisecond}, nil)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("RunCommand took %s", elapsed)
+	}
+	if r := results[0]; r.Alias != "slow" || r.ExitCode != -1 || !strings.Contains(r.Error, "timed out") {
+		t.Fatalf("slow result = %+v", r)
+	}
+	if r := results[1]; r.Alias != "ok" || r.Failed() {
+		t.Fatalf("ok result = %+v", r)
+	}
+}
+
+func TestRunCommandDoesNotWaitForOutputHolders(t *testing.T) {
+	s, _ := newExecFixture(t)
+
+	// The killed ssh leaves a child holding stdout; WaitDelay stops waiting for it.
+	start := time.Now()
+	results := s.RunCommand(context.Background(), []string{"hold"}, "true",
+		domain.ExecOptions{Timeout: 200 * time.Millisecond}, nil)
+	if elapsed := time.Since(start); elapsed > execWaitDelay+3*time.Second {
+		t.Fatalf("RunCommand took %s, want about %s", elapsed, execWaitDelay)
+	}
+	if r := results[0]; !r.Failed() {
+		t.Fatalf("hold result = %+v", r)
+	}
+}
@@ -0,0 +1,708 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// progressInterval throttles progress callbacks during transfers.
+const progressInterval = 100 * time.Millisecond
+
+// noChecksumMarker is printed instead of checksums when the server has no sha256sum.
+const noChecksumMarker = "\x02"
+
+// writeFileScript replaces the file %[1]s with stdin through a temporary file and gives it
+// the octal mode %[2]o, including setuid, setgid and sticky bits, and the original owner.
+const writeFileScript = `f=$(readlink -f -- %[1]s 2>/dev/null) || f=%[1]s
+owner=$({ stat -c %%u:%%g -- "$f" || stat -f %%u:%%g -- "$f"; } 2>/dev/null) || { echo "cannot stat $f" >&2; exit 1; }
+t=$(mktemp "$f.dogssh.XXXXXX") || exit 1
+trap 'rm -f -- "$t"' EXIT
+trap 'exit 1' HUP INT TERM
+cat > "$t" && chmod %[2]o "$t" &&
+{ [ "$owner" = "$(id -u):$(id -g)" ] || chown -- "$owner" "$t"; } &&
+mv -f -- "$t" "$f"`
+
+// streamSumMarker starts the stderr line carrying the SHA-256 of a verified download's archive.
+const streamSumMarker = "dogssh-sha256:"
+
+// lsMarker starts the listing output when the server has no GNU find.
+const lsMarker = "\x01"
+
+type fileService struct {
+	logger           *zap.SugaredLogger
+	serverRepository ports.ServerRepository
+}
+
+// NewFileService creates a file service that works through the system ssh
+// client, so each server's port, user, identity and jump host apply.
+func NewFileService(logger *zap.SugaredLogger, sr ports.ServerRepository) ports.FileService {
+	return &fileService{
+		logger:           logger,
+		serverRepository: sr,
+	}
+}
+
+// Home returns the login directory of alias.
+func (s *fileService) Home(alias string) (string, error) {
+	out, err := s.runRemote(context.Background(), alias, "pwd")
+	if err != nil {
+		return "", fmt.Errorf("home directory: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// List returns the entries of dir on alias, directories first.
+func (s *fileService) List(alias, dir string) ([]domain.FileEntry, error) {
+	script := fmt.Sprintf(`cd -- %s || exit 1; find . -mindepth 1 -maxdepth 1 -printf '%%y\t%%Y\t%%s\t%%m\t%%T@\t%%f\0' 2>/dev/null || { printf '%s'; ls -1Ap; }`,
+		shellQuote(dir), lsMarker)
+	out, err := s.runRemote(context.Background(), alias, script)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", dir, err)
+	}
+
+	var entries []domain.FileEntry
+	if rest, ok := bytes.CutPrefix(out, []byte(lsMarker)); ok {
+		entries = parseLsListing(dir, string(rest))
+	} else {
+		entries = parseFindListing(dir, string(out))
+	}
+	sortFileEntries(entries)
+	return entries, nil
+}
+
+// ListLocal returns the entries of a local directory, directories first.
+func (s *fileService) ListLocal(dir string) ([]domain.FileEntry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.FileEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		full := filepath.Join(dir, de.Name())
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		entry := domain.FileEntry{
+			Name:    de.Name(),
+			Path:    full,
+			Size:    info.Size(),
+			Mode:    info.Mode(),
+			ModTime: info.ModTime(),
+			IsDir:   info.IsDir(),
+			IsLink:  info.Mode()&os.ModeSymlink != 0,
+		}
+		if entry.IsLink {
+			if target, err := os.Stat(full); err == nil {
+				entry.IsDir = target.IsDir()
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sortFileEntries(entries)
+	return entries, nil
+}
+
+// Upload copies a local file or directory into remoteDir on alias.
+func (s *fileService) Upload(ctx context.Context, alias, localPath, remoteDir string, progress func(domain.TransferProgress)) error {
+	total, err := localSize(localPath)
+	if err != nil {
+		return err
+	}
+	s.logger.Infow("upload start", "alias", alias, "local", localPath, "remote", remoteDir, "bytes", total)
+
+	cmd := s.remoteCommand(ctx, alias, fmt.Sprintf("mkdir -p -- %[1]s && tar -xf - -C %[1]s", shellQuote(remoteDir)))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("upload %s: %w", localPath, err)
+	}
+
+	counter := newProgressCounter(total, progress)
+	writeErr := writeTar(stdin, localPath, counter)
+	_ = stdin.Close()
+	waitErr := cmd.Wait()
+
+	switch {
+	case waitErr != nil:
+		return fmt.Errorf("upload %s: %w", localPath, remoteError(waitErr, stderr.String()))
+	case writeErr != nil:
+		return fmt.Errorf("upload %s: %w", localPath, writeErr)
+	}
+	counter.finish()
+	s.logger.Infow("upload done", "alias", alias, "local", localPath, "remote", remoteDir)
+	return nil
+}
+
+// Download copies a remote file or directory into localDir.
+func (s *fileService) Download(ctx context.Context, alias, remotePath, localDir string, progress func(domain.TransferProgress)) error {
+	_, err := s.download(ctx, alias, remotePath, localDir, false, progress)
+	return err
+}
+
+// DownloadVerified copies like Download and checks the archive against the SHA-256 the
+// server computed while sending it, so files changing meanwhile do not fail the check.
+// It returns the number of files received, or -1 when the server has no sha256sum.
+func (s *fileService) DownloadVerified(ctx context.Context, alias, remotePath, localDir string, progress func(domain.TransferProgress)) (int, error) {
+	return s.download(ctx, alias, remotePath, localDir, true, progress)
+}
+
+func (s *fileService) download(ctx context.Context, alias, remotePath, localDir string, verify bool, progress func(domain.TransferProgress)) (int, error) {
+	total := s.remoteSize(ctx, alias, remotePath)
+	s.logger.Infow("download start", "alias", alias, "remote", remotePath, "local", localDir, "bytes", total)
+
+	if err := os.MkdirAll(localDir, 0o750); err != nil {
+		return 0, err
+	}
+
+	cmd := s.remoteCommand(ctx, alias, downloadScript(remotePath, verify))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return 0, err
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("download %s: %w", remotePath, err)
+	}
+
+	counter := newProgressCounter(total, progress)
+	hash := sha256.New()
+	stream := io.TeeReader(stdout, hash)
+	files, readErr := extractTar(stream, localDir, counter)
+	// Read the end-of-archive padding too, which belongs to the hashed stream, and
+	// drain after errors so ssh can exit instead of blocking on a full pipe.
+	_, _ = io.Copy(io.Discard, stream)
+	waitErr := cmd.Wait()
+	sum, errOutput, hasSum := cutStreamSum(stderr.String())
+
+	switch {
+	case waitErr != nil:
+		return 0, fmt.Errorf("download %s: %w", remotePath, remoteError(waitErr, errOutput))
+	case readErr != nil:
+		return 0, fmt.Errorf("download %s: %w", remotePath, readErr)
+	}
+	counter.finish()
+	if verify {
+		if !hasSum {
+			s.logger.Warnw("download not verified", "alias", alias, "remote", remotePath, "error", domain.ErrChecksumUnsupported)
+			files = -1
+		} else if got := hex.EncodeToString(hash.Sum(nil)); got != sum {
+			return 0, fmt.Errorf("checksum mismatch for %s", remotePath)
+		}
+	}
+	s.logger.Infow("download done", "alias", alias, "remote", remotePath, "local", localDir, "files", files)
+	return files, nil
+}
+
+// downloadScript writes a tar archive of remotePath to stdout. When verify is set and the
+// server has sha256sum, the archive is also hashed on its way out and the sum reported on
+// stderr after streamSumMarker, keeping tar's exit status.
+func downloadScript(remotePath string, verify bool) string {
+	tarCmd := "tar -cf - -- " + shellQuote(path.Base(remotePath))
+	script := "cd -- " + shellQuote(path.Dir(remotePath)) + " || exit 1; "
+	if !verify {
+		return script + tarCmd
+	}
+	return script + "command -v sha256sum >/dev/null || { " + tarCmd + "; exit; }; " +
+		`t=$(mktemp -d) && mkfifo "$t/p" || exit 1; ` +
+		`sha256sum < "$t/p" > "$t/sum" & ` +
+		"{ " + tarCmd + `; echo $? > "$t/rc"; } | tee "$t/p"; wait; ` +
+		`printf '%s%s\n' '` + streamSumMarker + `' "$(cut -d' ' -f1 < "$t/sum")" >&2; ` +
+		`rc=$(cat "$t/rc"); rm -rf -- "$t"; exit "$rc"`
+}
+
+// cutStreamSum removes the archive checksum line from the stderr of a download and
+// returns the sum and the remaining output.
+func cutStreamSum(stderr string) (sum, rest string, ok bool) {
+	lines := strings.Split(stderr, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if value, found := strings.CutPrefix(line, streamSumMarker); found {
+			sum, ok = value, value != ""
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return sum, strings.Join(kept, "\n"), ok
+}
+
+// Checksums returns the SHA-256 of every file under p, keyed like the entries of a transfer.
+func (s *fileService) Checksums(ctx context.Context, alias, p string) (map[string]string, error) {
+	script := fmt.Sprintf("command -v sha256sum >/dev/null || { printf '%s'; exit 0; }; cd -- %s && find %s -type f -exec sha256sum {} +",
+		noChecksumMarker, shellQuote(path.Dir(p)), shellQuote(path.Base(p)))
+	out, err := s.runRemote(ctx, alias, script)
+	if err != nil {
+		return nil, fmt.Errorf("checksums %s: %w", p, err)
+	}
+	if string(out) == noChecksumMarker {
+		return nil, domain.ErrChecksumUnsupported
+	}
+
+	sums := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		sum, name, ok := strings.Cut(line, "  ")
+		if ok {
+			sums[name] = sum
+		}
+	}
+	return sums, nil
+}
+
+// ReadFile fetches a remote file. The first output line carries the mode, the rest is the content.
+func (s *fileService) ReadFile(ctx context.Context, alias, p string) (domain.RemoteFile, error) {
+	script := fmt.Sprintf("{ stat -c %%a -- %[1]s 2>/dev/null || stat -f %%Mp%%Lp -- %[1]s; } && cat -- %[1]s", shellQuote(p))
+	out, err := s.runRemote(ctx, alias, script)
+	if err != nil {
+		return domain.RemoteFile{}, fmt.Errorf("read %s: %w", p, err)
+	}
+	modeLine, content, ok := bytes.Cut(out, []byte("\n"))
+	mode, parseErr := strconv.ParseUint(strings.TrimSpace(string(modeLine)), 8, 32)
+	if !ok || parseErr != nil {
+		return domain.RemoteFile{}, fmt.Errorf("read %s: unexpected stat output %q", p, modeLine)
+	}
+	return domain.RemoteFile{Alias: alias, Path: p, Mode: unixFileMode(mode), Content: content}, nil
+}
+
+// WriteFile uploads content to a temporary file next to the original and renames it over
+// the original, so a dropped connection never leaves a truncated file. The temporary file
+// gets the original mode and owner first; a symlink is followed and its target replaced.
+func (s *fileService) WriteFile(ctx context.Context, original domain.RemoteFile, content []byte) error {
+	current, err := s.ReadFile(ctx, original.Alias, original.Path)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current.Content, original.Content) {
+		return domain.ErrRemoteFileChanged
+	}
+
+	script := fmt.Sprintf(writeFileScript, shellQuote(original.Path), unixMode(original.Mode))
+	cmd := s.remoteCommand(ctx, original.Alias, script)
+	var stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("write %s: %w", original.Path, remoteError(err, stderr.String()))
+	}
+	s.logger.Infow("remote file written", "alias", original.Alias, "path", original.Path, "bytes", len(content))
+	return nil
+}
+
+func (s *fileService) Rename(alias, from, to string) error {
+	_, err := s.runRemote(context.Background(), alias, fmt.Sprintf("mv -- %s %s", shellQuote(from), shellQuote(to)))
+	if err != nil {
+		return fmt.Errorf("rename %s: %w", from, err)
+	}
+	return nil
+}
+
+// Remove deletes a remote file or directory recursively.
+func (s *fileService) Remove(alias, p string) error {
+	if clean := path.Clean(p); clean == "/" || clean == "." {
+		return fmt.Errorf("refusing to remove %q", p)
+	}
+	_, err := s.runRemote(context.Background(), alias, "rm -rf -- "+shellQuote(p))
+	if err != nil {
+		return fmt.Errorf("remove %s: %w", p, err)
+	}
+	s.logger.Infow("remote remove", "alias", alias, "path", p)
+	return nil
+}
+
+func (s *fileService) Mkdir(alias, p string) error {
+	_, err := s.runRemote(context.Background(), alias, "mkdir -p -- "+shellQuote(p))
+	if err != nil {
+		return fmt.Errorf("mkdir %s: %w", p, err)
+	}
+	return nil
+}
+
+// remoteCommand runs script through the remote user's shell.
+func (s *fileService) remoteCommand(ctx context.Context, alias, script string) *exec.Cmd {
+	return batchSSH(ctx, s.serverRepository, alias, alias, "--", script)
+}
+
+// runRemote runs script on alias and returns its stdout.
+func (s *fileService) runRemote(ctx context.Context, alias, script string) ([]byte, error) {
+	cmd := s.remoteCommand(ctx, alias, script)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, remoteError(err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// remoteSize returns the size of a remote path in bytes, or 0 when du is unavailable.
+func (s *fileService) remoteSize(ctx context.Context, alias, p string) int64 {
+	out, err := s.runRemote(ctx, alias, "du -sb -- "+shellQuote(p))
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return 0
+	}
+	size, _ := strconv.ParseInt(fields[0], 10, 64)
+	return size
+}
+
+// remoteError prefers the last line ssh or the remote command printed over the exit status.
+func remoteError(err error, stderr string) error {
+	if msg := lastLine(stderr); msg != "" {
+		return errors.New(msg)
+	}
+	return err
+}
+
+// unixFileMode converts octal permission bits as printed by stat or find into an os.FileMode.
+func unixFileMode(perm uint64) os.FileMode {
+	mode := os.FileMode(perm) & os.ModePerm
+	if perm&0o4000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if perm&0o2000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if perm&0o1000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+// unixMode returns the octal permission bits of mode for chmod, the reverse of unixFileMode.
+func unixMode(mode os.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		perm |= 0o4000
+	}
+	if mode&os.ModeSetgid != 0 {
+		perm |= 0o2000
+	}
+	if mode&os.ModeSticky != 0 {
+		perm |= 0o1000
+	}
+	return perm
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// parseFindListing parses NUL-separated `find -printf '%y\t%Y\t%s\t%m\t%T@\t%f\0'` output.
+func parseFindListing(dir, out string) []domain.FileEntry {
+	var entries []domain.FileEntry
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(record, "\t", 6)
+		if len(fields) != 6 {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[2], 10, 64)
+		perm, _ := strconv.ParseUint(fields[3], 8, 32)
+		secs, _ := strconv.ParseFloat(fields[4], 64)
+
+		entry := domain.FileEntry{
+			Name:    fields[5],
+			Path:    path.Join(dir, fields[5]),
+			Size:    size,
+			Mode:    unixFileMode(perm),
+			ModTime: time.Unix(int64(secs), 0),
+			IsDir:   fields[1] == "d",
+			IsLink:  fields[0] == "l",
+		}
+		if entry.IsDir {
+			entry.Mode |= os.ModeDir
+		}
+		if entry.IsLink {
+			entry.Mode |= os.ModeSymlink
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// parseLsListing parses `ls -1Ap` output, which only carries names and the directory flag.
+func parseLsListing(dir, out string) []domain.FileEntry {
+	var entries []domain.FileEntry
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		name, isDir := strings.CutSuffix(line, "/")
+		entry := domain.FileEntry{Name: name, Path: path.Join(dir, name), IsDir: isDir}
+		if isDir {
+			entry.Mode = os.ModeDir
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// sortFileEntries orders directories first, then by case-insensitive name.
+func sortFileEntries(entries []domain.FileEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsDir != entries[j].IsDir {
+			return entries[i].IsDir
+		}
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+}
+
+// localSize sums the sizes of the regular files under root.
+func localSize(root string) (int64, error) {
+	var total int64
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// writeTar streams root as a tar archive whose entries are relative to root's parent.
+func writeTar(w io.Writer, root string, counter *progressCounter) error {
+	tw := tar.NewWriter(w)
+	parent := filepath.Dir(root)
+
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(parent, p)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p) //nolint:gosec // G304: the user picked this path
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		counter.file = hdr.Name
+		_, err = io.Copy(tw, io.TeeReader(f, counter))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractTar unpacks a tar stream into dir and returns the number of regular files. It
+// rejects entries that escape dir, symlinks pointing outside it and entries that would be
+// written through a symlink.
+func extractTar(r io.Reader, dir string, counter *progressCounter) (int, error) {
+	tr := tar.NewReader(r)
+	files := 0
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || escapesDir(name) {
+			return files, fmt.Errorf("unsafe path in archive: %s", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := checkNoSymlinks(dir, name); err != nil {
+				return files, err
+			}
+			if err := os.MkdirAll(target, mode|0o700); err != nil {
+				return files, err
+			}
+		case tar.TypeReg:
+			if err := checkNoSymlinks(dir, filepath.Dir(name)); err != nil {
+				return files, err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+				return files, err
+			}
+			// Replace a link left by an earlier download instead of writing through it.
+			if info, err := os.Lstat(target); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(target); err != nil {
+					return files, err
+				}
+			}
+			counter.file = hdr.Name
+			if err := writeFile(target, io.TeeReader(tr, counter), mode); err != nil {
+				return files, err
+			}
+			files++
+		case tar.TypeSymlink:
+			link := filepath.FromSlash(hdr.Linkname)
+			if filepath.IsAbs(link) || !leadingParentsOnly(hdr.Linkname) || escapesDir(filepath.Join(filepath.Dir(name), link)) {
+				return files, fmt.Errorf("unsafe symlink in archive: %s -> %s", hdr.Name, hdr.Linkname)
+			}
+			if err := checkNoSymlinks(dir, filepath.Dir(name)); err != nil {
+				return files, err
+			}
+			_ = os.Remove(target)
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return files, err
+			}
+		}
+	}
+}
+
+// escapesDir reports whether the clean relative path name points above its base directory.
+func escapesDir(name string) bool {
+	return name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator))
+}
+
+// leadingParentsOnly reports whether ".." appears in the slash-separated link only before
+// other elements. Later ones could climb out of a directory reached through another
+// symlink, which the lexical check cannot see.
+func leadingParentsOnly(link string) bool {
+	named := false
+	for _, part := range strings.Split(link, "/") {
+		switch {
+		case part == "..":
+			if named {
+				return false
+			}
+		case part != "" && part != ".":
+			named = true
+		}
+	}
+	return true
+}
+
+// checkNoSymlinks fails when an existing component of the relative path name below dir
+// is a symlink, so an archive cannot create a link and then write through it.
+func checkNoSymlinks(dir, name string) error {
+	if name == "." {
+		return nil
+	}
+	current := dir
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("refusing to write through symlink in archive: %s", name)
+		}
+	}
+	return nil
+}
+
+// writeFile replaces target with the contents of r and sets mode.
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode) //nolint:gosec // G304: inside the chosen directory
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(target, mode)
+}
+
+// progressCounter counts bytes passing through it and reports them at most every progressInterval.
+type progressCounter struct {
+	total    int64
+	done     int64
+	file     string
+	last     time.Time
+	progress func(domain.TransferProgress)
+}
+
+func newProgressCounter(total int64, progress func(domain.TransferProgress)) *progressCounter {
+	return &progressCounter{total: total, progress: progress}
+}
+
+func (c *progressCounter) Write(p []byte) (int, error) {
+	c.done += int64(len(p))
+	if c.progress != nil && time.Since(c.last) >= progressInterval {
+		c.last = time.Now()
+		c.progress(domain.TransferProgress{File: c.file, Done: c.done, Total: c.total})
+	}
+	return len(p), nil
+}
+
+// finish reports the final state, so the last update is never throttled away.
+func (c *progressCounter) finish() {
+	if c.progress != nil {
+		c.progress(domain.TransferProgress{File: c.file, Done: c.done, Total: max(c.total, c.done)})
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// tarEntry is a file, directory or symlink for buildTar.
+type tarEntry struct {
+	name string
+	body string
+	link string
+	dir  bool
+}
+
+func buildTar(t *testing.T, entries ...tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(e.body))}
+		switch {
+		case e.dir:
+			hdr.Typeflag, hdr.Mode, hdr.Size = tar.TypeDir, 0o755, 0
+		case e.link != "":
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.link, 0
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(e.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTar(t *testing.T) {
+	dir := t.TempDir()
+	archive := buildTar(t,
+		tarEntry{name: "logs", dir: true},
+		tarEntry{name: "logs/access.log", body: "GET /"},
+		tarEntry{name: "logs/current", link: "access.log"},
+		tarEntry{name: "logs/up", link: "../logs"},
+	)
+	files, err := extractTar(archive, dir, newProgressCounter(0, nil))
+	if err != nil || files != 1 {
+		t.Fatalf("extractTar = %d, %v", files, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "logs", "current")); err != nil || string(data) != "GET /" {
+		t.Fatalf("symlink target = %q, %v", data, err)
+	}
+}
+
+func TestExtractTarRejectsEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []tarEntry
+		want    string
+	}{
+		{"parent path", []tarEntry{{name: "../evil", body: "x"}}, "unsafe path"},
+		{"absolute link", []tarEntry{{name: "a", link: "/etc"}}, "unsafe symlink"},
+		{"link outside", []tarEntry{{name: "a", link: "../.."}}, "unsafe symlink"},
+		{"climb after link", []tarEntry{{name: "l", link: "."}, {name: "m", link: "l/../outside"}}, "unsafe symlink"},
+		{"write through link", []tarEntry{{name: "a", link: "sub"}, {name: "sub", dir: true}, {name: "a/authorized_keys", body: "key"}}, "through symlink"},
+		{"directory through link", []tarEntry{{name: "a", link: "."}, {name: "a/b", dir: true}}, "through symlink"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dir := filepath.Join(parent, "download")
+			if err := os.Mkdir(dir, 0o750); err != nil {
+				t.Fatal(err)
+			}
+			_, err := extractTar(buildTar(t, tt.entries...), dir, newProgressCounter(0, nil))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want %q", err, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(parent, "evil")); err == nil {
+				t.Fatal("a file was written outside the directory")
+			}
+		})
+	}
+}
+
+func TestExtractTarReplacesExistingSymlink(t *testing.T) {
+	dir := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "target")
+	if err := os.WriteFile(outside, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "file")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := extractTar(buildTar(t, tarEntry{name: "file", body: "new"}), dir, newProgressCounter(0, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(outside); string(data) != "keep" {
+		t.Fatalf("file outside the directory was overwritten: %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "file")); string(data) != "new" {
+		t.Fatalf("file = %q", data)
+	}
+}
+
+func TestDownloadScriptReportsStreamSum(t *testing.T) {
+	for _, tool := range []string{"sh", "tar", "sha256sum", "mkfifo", "tee"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+	remote := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(remote, "logs"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(remote, "logs", "access.log"), []byte("GET /\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("sh", "-c", downloadScript(filepath.ToSlash(filepath.Join(remote, "logs")), true))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("script failed: %v: %s", err, stderr.String())
+	}
+	sum, rest, ok := cutStreamSum(stderr.String())
+	want := sha256.Sum256(stdout.Bytes())
+	if !ok || sum != hex.EncodeToString(want[:]) || strings.TrimSpace(rest) != "" {
+		t.Fatalf("cutStreamSum(%q) = %q, %q, %v", stderr.String(), sum, rest, ok)
+	}
+	files, err := extractTar(&stdout, t.TempDir(), newProgressCounter(0, nil))
+	if err != nil || files != 1 {
+		t.Fatalf("extractTar = %d, %v", files, err)
+	}
+
+	// tar's exit status survives the pipeline.
+	cmd = exec.Command("sh", "-c", downloadScript(filepath.ToSlash(filepath.Join(remote, "missing")), true))
+	if err := cmd.Run(); err == nil {
+		t.Fatal("archiving a missing path succeeded")
+	}
+}
+
+func TestWriteFileScript(t *testing.T) {
+	for _, tool := range []string{"sh", "readlink", "stat", "mktemp"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "nginx.conf")
+	if err := os.WriteFile(target, []byte("old"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "current.conf")
+	if err := os.Symlink("nginx.conf", link); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(writeFileScript, shellQuote(link), os.FileMode(0o644)))
+	cmd.Stdin = strings.NewReader("new")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v: %s", err, out)
+	}
+
+	if data, _ := os.ReadFile(target); string(data) != "new" {
+		t.Fatalf("content = %q", data)
+	}
+	if info, err := os.Stat(target); err != nil || info.Mode().Perm() != 0o644 {
+		t.Fatalf("mode = %v, %v", info.Mode(), err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatal("the symlink was replaced")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Fatalf("temporary file left behind: %v", entries)
+	}
+
+	// Setgid and sticky bits survive the edit.
+	mode := unixFileMode(0o3750)
+	cmd = exec.Command("sh", "-c", fmt.Sprintf(writeFileScript, shellQuote(target), unixMode(mode)))
+	cmd.Stdin = strings.NewReader("newer")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v: %s", err, out)
+	}
+	if info, err := os.Stat(target); err != nil || info.Mode()&(os.ModePerm|os.ModeSetgid|os.ModeSticky) != mode {
+		t.Fatalf("mode = %v, want %v (%v)", info.Mode(), mode, err)
+	}
+}
+
+func TestUnixMode(t *testing.T) {
+	for _, perm := range []uint64{0o644, 0o4755, 0o2750, 0o1777, 0o7777} {
+		mode := unixFileMode(perm)
+		if got := unixMode(mode); uint64(got) != perm {
+			t.Errorf("unixMode(unixFileMode(%o)) = %o (mode %v)", perm, got, mode)
+		}
+	}
+	if mode := unixFileMode(0o2755); mode&os.ModeSetgid == 0 || mode.Perm() != 0o755 {
+		t.Errorf("unixFileMode(2755) = %v", mode)
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"golang.org/x/crypto/ssh"
+)
+
+// installKeyScript appends a public key to authorized_keys unless a line with
+// the same key is already present. It prints "added" or "present".
+const installKeyScript = `umask 077
+mkdir -p ~/.ssh && chmod 700 ~/.ssh && touch ~/.ssh/authorized_keys && chmod 600 ~/.ssh/authorized_keys || exit 1
+if grep -qF %s ~/.ssh/authorized_keys; then echo present; exit 0; fi
+if [ -s ~/.ssh/authorized_keys ] && [ -n "$(tail -c 1 ~/.ssh/authorized_keys)" ]; then echo >> ~/.ssh/authorized_keys; fi
+printf '%%s\n' %s >> ~/.ssh/authorized_keys && echo added`
+
+// removeKeyScript drops every authorized_keys line containing a public key. The
+// remaining lines go to a temporary file next to it, which replaces the file only
+// if grep succeeded, so a read or write error never leaves it truncated. The mode
+// is kept. It prints "removed" or "absent".
+const removeKeyScript = `f=$(readlink -f -- ~/.ssh/authorized_keys 2>/dev/null) || f=~/.ssh/authorized_keys
+if [ ! -f "$f" ] || ! grep -qF %[1]s "$f"; then echo absent; exit 0; fi
+mode=$({ stat -c %%a -- "$f" || stat -f %%Lp -- "$f"; } 2>/dev/null) || mode=600
+tmp=$(mktemp "$f.XXXXXX") || exit 1
+grep -vF %[1]s "$f" > "$tmp"
+if [ $? -gt 1 ]; then rm -f -- "$tmp"; echo "cannot rewrite $f" >&2; exit 1; fi
+chmod "$mode" "$tmp" && mv -f -- "$tmp" "$f" && echo removed || { rm -f -- "$tmp"; exit 1; }`
+
+// verifyConfigOptions are the resolved ssh_config settings VerifyKey keeps: where and how
+// to connect, which algorithms the server needs and how long to wait. Everything else, in
+// particular every IdentityFile, is left out.
+var verifyConfigOptions = []string{
+	"hostname", "port", "user", "hostkeyalias", "userknownhostsfile",
+	"globalknownhostsfile", "stricthostkeychecking", "proxycommand",
+	"hostkeyalgorithms", "pubkeyacceptedalgorithms", "pubkeyacceptedkeytypes",
+	"casignaturealgorithms", "kexalgorithms", "ciphers", "macs", "certificatefile",
+	"connecttimeout", "connectionattempts", "addressfamily", "bindaddress",
+}
+
+// InstallKey appends the public half of keyPath to the server's authorized_keys,
+// logging in with the stored password if there is one, and then verifies that
+// the key alone is accepted.
+func (s *keyService) InstallKey(ctx context.Context, alias, keyPath string) domain.KeyInstallResult {
+	start := time.Now()
+	result := domain.KeyInstallResult{Alias: alias, KeyPath: ExpandHome(keyPath)}
+	if hasPassword, err := s.serverRepository.HasPassword(alias); err == nil {
+		result.HasPassword = hasPassword
+	}
+	defer func() { result.Duration = time.Since(start) }()
+
+	added, err := s.installPublicKey(ctx, alias, result.KeyPath)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Added = added
+
+	if err := s.VerifyKey(ctx, alias, result.KeyPath); err != nil {
+		result.Error = "key installed but login failed: " + err.Error()
+		return result
+	}
+	result.Verified = true
+	s.logger.Infow("key installed", "alias", alias, "key", result.KeyPath, "added", added)
+	return result
+}
+
+// VerifyKey logs in to alias in batch mode offering only keyPath. IdentitiesOnly does
+// not stop ssh from also offering the IdentityFile keys of the config, which could let
+// an old key pass the check, so the login runs without the config and with the
+// destination settings resolved by ssh -G instead.
+func (s *keyService) VerifyKey(ctx context.Context, alias, keyPath string) error {
+	//nolint:gosec // G204: the alias comes from the user's own SSH config
+	out, err := exec.CommandContext(ctx, "ssh", "-G", alias).Output()
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", alias, err)
+	}
+	//nolint:gosec // G204: arguments come from the user's own SSH config
+	cmd := exec.CommandContext(ctx, "ssh", verifyKeyArgs(parseSSHConfigDump(string(out)), ExpandHome(keyPath), alias)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return remoteError(err, stderr.String())
+	}
+	return nil
+}
+
+// RemoveKey deletes the public half of keyPath from the server's authorized_keys.
+// It reports whether the key was present.
+func (s *keyService) RemoveKey(ctx context.Context, alias, keyPath string) (bool, error) {
+	line, err := readPublicKeyLine(ExpandHome(keyPath))
+	if err != nil {
+		return false, err
+	}
+	match := authorizedKeyMatch(line)
+
+	cmd := batchSSH(ctx, s.serverRepository, alias, alias, "--", fmt.Sprintf(removeKeyScript, shellQuote(match)))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return false, remoteError(err, stderr.String())
+	}
+
+	switch strings.TrimSpace(stdout.String()) {
+	case "removed":
+		s.logger.Infow("key removed", "alias", alias, "key", keyPath)
+		return true, nil
+	case "absent":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected reply from %s: %q", alias, lastLine(stdout.String()))
+	}
+}
+
+// UseKey puts keyPath first among the server's identity files and deletes its stored password.
+func (s *keyService) UseKey(alias, keyPath string) error {
+	server, err := s.findServer(alias)
+	if err != nil {
+		return err
+	}
+
+	key := ExpandHome(keyPath)
+	updated := server
+	updated.IdentityFiles = []string{collapseHome(key)}
+	for _, identity := range server.IdentityFiles {
+		if ExpandHome(identity) != key {
+			updated.IdentityFiles = append(updated.IdentityFiles, identity)
+		}
+	}
+	updated.Password = ""
+	if err := s.serverRepository.UpdateServer(server, updated); err != nil {
+		return err
+	}
+	if err := s.serverRepository.DeletePassword(alias); err != nil {
+		return fmt.Errorf("delete password: %w", err)
+	}
+	s.logger.Infow("switched to key auth", "alias", alias, "key", keyPath)
+	return nil
+}
+
+// installPublicKey reports whether the key was appended (false if it was already there).
+func (s *keyService) installPublicKey(ctx context.Context, alias, keyPath string) (bool, error) {
+	line, err := readPublicKeyLine(keyPath)
+	if err != nil {
+		return false, err
+	}
+	match := authorizedKeyMatch(line)
+
+	cmd := batchSSH(ctx, s.serverRepository, alias, alias, "--", fmt.Sprintf(installKeyScript, shellQuote(match), shellQuote(line)))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return false, remoteError(err, stderr.String())
+	}
+
+	switch strings.TrimSpace(stdout.String()) {
+	case "added":
+		return true, nil
+	case "present":
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected reply from %s: %q", alias, lastLine(stdout.String()))
+	}
+}
+
+// ReplaceKey swaps oldKeyPath for newKeyPath in the server's identity files.
+func (s *keyService) ReplaceKey(alias, oldKeyPath, newKeyPath string) error {
+	server, err := s.findServer(alias)
+	if err != nil {
+		return err
+	}
+
+	oldKey, newKey := ExpandHome(oldKeyPath), ExpandHome(newKeyPath)
+	updated := server
+	updated.IdentityFiles = nil
+	seen := false
+	for _, identity := range server.IdentityFiles {
+		p := ExpandHome(identity)
+		if p == oldKey {
+			identity, p = collapseHome(newKey), newKey
+		}
+		if p == newKey {
+			if seen {
+				continue
+			}
+			seen = true
+		}
+		updated.IdentityFiles = append(updated.IdentityFiles, identity)
+	}
+	if !seen {
+		updated.IdentityFiles = append([]string{collapseHome(newKey)}, updated.IdentityFiles...)
+	}
+	updated.Password = ""
+	return s.serverRepository.UpdateServer(server, updated)
+}
+
+func (s *keyService) findServer(alias string) (domain.Server, error) {
+	return findServer(s.serverRepository, alias)
+}
+
+// findServer looks up a server by its primary alias.
+func findServer(repo ports.ServerRepository, alias string) (domain.Server, error) {
+	servers, err := repo.ListServers()
+	if err != nil {
+		return domain.Server{}, err
+	}
+	for _, server := range servers {
+		if server.Alias == alias {
+			return server, nil
+		}
+	}
+	return domain.Server{}, fmt.Errorf("server '%s' not found", alias)
+}
+
+// readPublicKeyLine returns the authorized_keys line for the private key at keyPath.
+func readPublicKeyLine(keyPath string) (string, error) {
+	data, err := os.ReadFile(keyPath + ".pub") //nolint:gosec // G304: the user's own key
+	if err != nil {
+		return "", fmt.Errorf("read public key: %w", err)
+	}
+	if _, _, _, _, err := ssh.ParseAuthorizedKey(data); err != nil {
+		return "", fmt.Errorf("parse %s.pub: %w", keyPath, err)
+	}
+	return strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0]), nil
+}
+
+// authorizedKeyMatch returns the type and blob of an authorized_keys line,
+// which identify the key regardless of its comment.
+func authorizedKeyMatch(line string) string {
+	fields := strings.Fields(line)
+	return fields[0] + " " + fields[1]
+}
+
+// collapseHome writes paths under the home directory as ~/..., the way ssh configs usually do.
+func collapseHome(p string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return p
+	}
+	if rel, err := filepath.Rel(home, p); err == nil && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return p
+}
+
+// parseSSHConfigDump reads `ssh -G` output into a map of lowercase keywords to values,
+// keeping the first value of repeated keywords.
+func parseSSHConfigDump(out string) map[string]string {
+	options := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if _, seen := options[key]; ok && !seen {
+			options[key] = strings.TrimSpace(value)
+		}
+	}
+	return options
+}
+
+// verifyKeyArgs returns ssh arguments that log in to alias with the resolved settings
+// in options, offering keyPath as the only key. A ProxyJump becomes a ProxyCommand so
+// the jump hosts still use the user's config.
+func verifyKeyArgs(options map[string]string, keyPath, alias string) []string {
+	args := []string{
+		"-F", os.DevNull,
+		"-o", "BatchMode=yes",
+		"-o", "PasswordAuthentication=no",
+		"-o", "PreferredAuthentications=publickey",
+		"-o", "IdentitiesOnly=yes",
+		"-i", keyPath,
+	}
+	for _, name := range verifyConfigOptions {
+		if value := options[name]; value != "" && value != "none" {
+			args = append(args, "-o", name+"="+value)
+		}
+	}
+	proxyCommand := options["proxycommand"]
+	if jump := options["proxyjump"]; jump != "" && jump != "none" && (proxyCommand == "" || proxyCommand == "none") {
+		hops := strings.Split(jump, ",")
+		command := "ssh "
+		if len(hops) > 1 {
+			command += "-J " + shellQuote(strings.Join(hops[:len(hops)-1], ",")) + " "
+		}
+		command += "-W '[%h]:%p' " + shellQuote("ssh://"+hops[len(hops)-1])
+		args = append(args, "-o", "ProxyCommand="+command)
+	}
+	return append(args, alias, "--", "true")
+}
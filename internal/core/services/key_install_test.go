@@ -0,0 +1,159 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestVerifyKeyArgsOfferOnlyTheKey(t *testing.T) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		t.Skip("ssh not available")
+	}
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config")
+	key := filepath.Join(dir, "id_new")
+	if err := os.WriteFile(key, []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := os.WriteFile(config, []byte(`Host web1
+  HostName 10.0.0.1
+  Port 2222
+  User deploy
+  ProxyJump bastion,admin@gw:2200
+  IdentityFile ~/.ssh/id_old
+  CertificateFile ~/.ssh/id_new-cert.pub
+  HostKeyAlgorithms ssh-rsa
+  PubkeyAcceptedAlgorithms +ssh-rsa
+  KexAlgorithms diffie-hellman-group14-sha1
+  ConnectTimeout 7
+  AddressFamily inet
+Host *
+  IdentityFile ~/.ssh/id_all
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("ssh", "-F", config, "-G", "web1").Output()
+	if err != nil {
+		t.Skipf("ssh -G: %v", err)
+	}
+	args := verifyKeyArgs(parseSSHConfigDump(string(out)), key, "web1")
+
+	// Resolve the verification command itself, without its remote command.
+	resolved, err := exec.Command("ssh", append(slices.Clone(args[:len(args)-2]), "-G")...).Output()
+	if err != nil {
+		t.Fatalf("ssh -G %v: %v", args, err)
+	}
+	options := parseSSHConfigDump(string(resolved))
+	var identities []string
+	for _, line := range strings.Split(string(resolved), "\n") {
+		if value, ok := strings.CutPrefix(line, "identityfile "); ok {
+			identities = append(identities, value)
+		}
+	}
+	if len(identities) != 1 || identities[0] != key {
+		t.Errorf("identity files = %v, want only %s", identities, key)
+	}
+	want := map[string]string{"hostname": "10.0.0.1", "port": "2222", "user": "deploy", "identitiesonly": "yes"}
+	// Per-host algorithm, timeout and address settings carry over.
+	original := parseSSHConfigDump(string(out))
+	for _, name := range []string{"hostkeyalgorithms", "pubkeyacceptedalgorithms", "kexalgorithms", "certificatefile", "connecttimeout", "addressfamily"} {
+		if original[name] == "" {
+			t.Fatalf("ssh -G did not print %s", name)
+		}
+		want[name] = original[name]
+	}
+	for name, value := range want {
+		if options[name] != value {
+			t.Errorf("%s = %q, want %q", name, options[name], value)
+		}
+	}
+	if command := options["proxycommand"]; !strings.Contains(command, "-J 'bastion'") || !strings.Contains(command, "ssh://admin@gw:2200") {
+		t.Errorf("proxycommand = %q", command)
+	}
+}
+
+func TestRemoveKeyScript(t *testing.T) {
+	for _, tool := range []string{"sh", "grep", "mktemp", "readlink"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not available", tool)
+		}
+	}
+	home := t.TempDir()
+	keys := filepath.Join(home, ".ssh", "authorized_keys")
+	if err := os.MkdirAll(filepath.Dir(keys), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	run := func(match string) (string, error) {
+		cmd := exec.Command("sh", "-c", fmt.Sprintf(removeKeyScript, shellQuote(match)))
+		cmd.Env = append(os.Environ(), "HOME="+home)
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+
+	if err := os.WriteFile(keys, []byte("ssh-ed25519 AAAAold old\nssh-ed25519 AAAAnew new\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := run("ssh-ed25519 AAAAold"); err != nil || out != "removed" {
+		t.Fatalf("remove = %q, %v", out, err)
+	}
+	if data, _ := os.ReadFile(keys); string(data) != "ssh-ed25519 AAAAnew new\n" {
+		t.Fatalf("authorized_keys = %q", data)
+	}
+	if info, _ := os.Stat(keys); info.Mode().Perm() != 0o640 {
+		t.Fatalf("mode = %v", info.Mode())
+	}
+	if out, err := run("ssh-ed25519 AAAAold"); err != nil || out != "absent" {
+		t.Fatalf("second remove = %q, %v", out, err)
+	}
+
+	// Removing the last key leaves an empty file rather than failing.
+	if out, err := run("ssh-ed25519 AAAAnew"); err != nil || out != "removed" {
+		t.Fatalf("remove last = %q, %v", out, err)
+	}
+	if data, err := os.ReadFile(keys); err != nil || len(data) != 0 {
+		t.Fatalf("authorized_keys = %q, %v", data, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(keys)); len(entries) != 1 {
+		t.Fatalf("temporary file left behind: %v", entries)
+	}
+
+	// A failing grep, e.g. on a full disk, leaves the file alone.
+	if err := os.WriteFile(keys, []byte("ssh-ed25519 AAAAold old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	realGrep, _ := exec.LookPath("grep")
+	shim := "#!/bin/sh\ncase \"$1\" in -vF) exit 2;; esac\nexec " + realGrep + " \"$@\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "grep"), []byte(shim), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(removeKeyScript, shellQuote("ssh-ed25519 AAAAold")))
+	cmd.Env = append(os.Environ(), "HOME="+home, "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("remove with a failing grep succeeded: %s", out)
+	}
+	if data, _ := os.ReadFile(keys); string(data) != "ssh-ed25519 AAAAold old\n" {
+		t.Fatalf("authorized_keys after a failed remove = %q", data)
+	}
+}
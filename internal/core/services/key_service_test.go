@@ -0,0 +1,136 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+// serversRepo serves a fixed server list.
+type serversRepo struct {
+	ports.ServerRepository
+	servers []domain.Server
+}
+
+func (r *serversRepo) ListServers() ([]domain.Server, error) {
+	return r.servers, nil
+}
+
+func TestGenerateKey(t *testing.T) {
+	dir := t.TempDir()
+	repo := &serversRepo{servers: []domain.Server{{Alias: "web1", IdentityFiles: []string{filepath.Join(dir, "id_rsa")}}}}
+	s := NewKeyService(zap.NewNop().Sugar(), repo, dir)
+
+	tests := []struct {
+		opts domain.KeyGenOptions
+		typ  string
+		bits int
+	}{
+		{domain.KeyGenOptions{Type: "ed25519", Comment: "me@laptop"}, "ed25519", 256},
+		{domain.KeyGenOptions{Type: "rsa", Bits: 2048}, "rsa", 2048},
+		{domain.KeyGenOptions{Type: "ecdsa", Bits: 384, Passphrase: "secret"}, "ecdsa-sha2-nistp384", 384},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(dir, "id_"+tt.opts.Type)
+		tt.opts.Path = p
+		key, err := s.Generate(tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.opts.Type, err)
+		}
+		if key.Type != tt.typ || key.Bits != tt.bits || key.Encrypted != (tt.opts.Passphrase != "") || key.Problem != "" {
+			t.Errorf("%s: key = %+v", tt.opts.Type, key)
+		}
+		if key.Comment != tt.opts.Comment {
+			t.Errorf("%s: comment = %q", tt.opts.Type, key.Comment)
+		}
+		if info, err := os.Stat(p); err != nil || info.Mode().Perm() != 0o600 {
+			t.Errorf("%s: private key mode = %v, %v", tt.opts.Type, info.Mode(), err)
+		}
+
+		data, _ := os.ReadFile(p)
+		if tt.opts.Passphrase != "" {
+			var missing *ssh.PassphraseMissingError
+			if _, err := ssh.ParsePrivateKey(data); !errors.As(err, &missing) {
+				t.Errorf("%s: key is not encrypted: %v", tt.opts.Type, err)
+			}
+			if _, err := ssh.ParsePrivateKeyWithPassphrase(data, []byte(tt.opts.Passphrase)); err != nil {
+				t.Errorf("%s: passphrase does not open the key: %v", tt.opts.Type, err)
+			}
+		}
+	}
+
+	keys, err := s.List()
+	if err != nil || len(keys) != 3 {
+		t.Fatalf("List = %v, %v", keys, err)
+	}
+	for _, key := range keys {
+		if strings.HasSuffix(key.Path, "id_rsa") && strings.Join(key.Servers, ",") != "web1" {
+			t.Errorf("id_rsa servers = %v", key.Servers)
+		}
+	}
+}
+
+func TestGenerateKeyRejects(t *testing.T) {
+	dir := t.TempDir()
+	s := NewKeyService(zap.NewNop().Sugar(), &serversRepo{}, dir)
+	existing := filepath.Join(dir, "id_ed25519")
+	if err := os.WriteFile(existing, []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []domain.KeyGenOptions{
+		{Type: "ed25519", Path: existing},
+		{Type: "dsa", Path: filepath.Join(dir, "id_dsa")},
+		{Type: "rsa", Bits: 512, Path: filepath.Join(dir, "id_rsa")},
+		{Type: "ecdsa", Bits: 200, Path: filepath.Join(dir, "id_ecdsa")},
+		{Type: "ed25519"},
+	} {
+		if _, err := s.Generate(opts); err == nil {
+			t.Errorf("Generate(%+v) succeeded", opts)
+		}
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "keep" {
+		t.Fatal("an existing key was overwritten")
+	}
+}
+
+func TestGeneratedKeyOpensWithSSHKeygen(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not available")
+	}
+	p := filepath.Join(t.TempDir(), "id_ed25519")
+	s := NewKeyService(zap.NewNop().Sugar(), &serversRepo{}, filepath.Dir(p))
+	if _, err := s.Generate(domain.KeyGenOptions{Type: "ed25519", Path: p, Passphrase: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command("ssh-keygen", "-y", "-P", "secret", "-f", p).Output()
+	if err != nil {
+		t.Fatalf("ssh-keygen -y: %v", err)
+	}
+	pub, _ := os.ReadFile(p + ".pub")
+	if !strings.HasPrefix(string(pub), strings.TrimSpace(string(out))) {
+		t.Fatalf("public key %q does not match %q", pub, out)
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// hostKeyScanTimeout bounds how long ssh-keyscan waits for a server.
+const hostKeyScanTimeout = 5 * time.Second
+
+type knownHostsService struct {
+	logger *zap.SugaredLogger
+	path   string
+}
+
+// NewKnownHostsService creates a service for the known_hosts file at path.
+func NewKnownHostsService(logger *zap.SugaredLogger, path string) ports.KnownHostsService {
+	return &knownHostsService{
+		logger: logger,
+		path:   path,
+	}
+}
+
+// List returns every entry of the known_hosts file.
+func (s *knownHostsService) List() ([]domain.KnownHost, error) {
+	lines, err := s.readLines()
+	if err != nil {
+		return nil, err
+	}
+	var entries []domain.KnownHost
+	for i, line := range lines {
+		if entry, ok := parseKnownHostLine(s.path, i+1, line); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Lookup returns the entries ssh would use to verify server.
+func (s *knownHostsService) Lookup(server domain.Server) ([]domain.KnownHost, error) {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	callback, err := knownhosts.New(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checking a key nobody has makes the callback report every key recorded for the host.
+	host, port := hostAndPort(server)
+	err = callback(net.JoinHostPort(host, strconv.Itoa(port)), &net.TCPAddr{IP: net.IPv4zero, Port: port}, probeKey)
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) || len(keyErr.Want) == 0 {
+		return nil, nil
+	}
+
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	byLine := make(map[int]domain.KnownHost, len(entries))
+	for _, entry := range entries {
+		byLine[entry.Line] = entry
+	}
+	recorded := make([]domain.KnownHost, 0, len(keyErr.Want))
+	for _, want := range keyErr.Want {
+		if entry, ok := byLine[want.Line]; ok {
+			recorded = append(recorded, entry)
+		}
+	}
+	return recorded, nil
+}
+
+// Check scans the server's current host keys and compares them with known_hosts.
+// Hosts behind a ProxyJump are scanned from the last jump host, which sees the
+// server the way ssh -W does.
+func (s *knownHostsService) Check(ctx context.Context, server domain.Server) (domain.HostKeyCheck, error) {
+	host, port := hostAndPort(server)
+	check := domain.HostKeyCheck{
+		Alias:   server.Alias,
+		Address: knownhosts.Normalize(net.JoinHostPort(host, strconv.Itoa(port))),
+	}
+
+	recorded, err := s.Lookup(server)
+	if err != nil {
+		return check, err
+	}
+	check.Recorded = recorded
+
+	options := resolveHostOptions(ctx, server)
+	if command := options["proxycommand"]; command != "" && command != "none" {
+		return check, fmt.Errorf("host key checks are not supported for hosts reached through ProxyCommand; connect once with ssh to record the key")
+	}
+	// Follow HashKnownHosts, and keep hashing a host whose entries are already hashed.
+	hash := options["hashknownhosts"] == "yes"
+	for _, entry := range recorded {
+		hash = hash || entry.Hashed
+	}
+
+	scanned, err := scanHostKeys(ctx, options["proxyjump"], host, port, check.Address, hash)
+	if err != nil {
+		return check, err
+	}
+	check.Scanned = scanned
+
+	check.Status = domain.HostKeyUnknown
+	if len(recorded) > 0 {
+		check.Status = domain.HostKeyChanged
+	}
+	for _, key := range scanned {
+		for _, entry := range recorded {
+			if key.Fingerprint == entry.Fingerprint {
+				check.Status = domain.HostKeyMatch
+			}
+		}
+	}
+	return check, nil
+}
+
+// Accept replaces the recorded entries of check with the scanned keys.
+func (s *knownHostsService) Accept(check domain.HostKeyCheck) error {
+	if len(check.Scanned) == 0 {
+		return fmt.Errorf("no host keys scanned for %s", check.Address)
+	}
+	lines, err := s.readLines()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	lines, err = removeKnownHostLines(s.path, lines, check.Recorded)
+	if err != nil {
+		return err
+	}
+	for _, key := range check.Scanned {
+		lines = append(lines, key.Line)
+	}
+	if err := s.writeLines(lines); err != nil {
+		return err
+	}
+	s.logger.Infow("host keys recorded", "alias", check.Alias, "address", check.Address,
+		"removed", len(check.Recorded), "added", len(check.Scanned))
+	return nil
+}
+
+// Remove deletes entries from the known_hosts file.
+func (s *knownHostsService) Remove(entries []domain.KnownHost) error {
+	lines, err := s.readLines()
+	if err != nil {
+		return err
+	}
+	lines, err = removeKnownHostLines(s.path, lines, entries)
+	if err != nil {
+		return err
+	}
+	if err := s.writeLines(lines); err != nil {
+		return err
+	}
+	s.logger.Infow("known_hosts entries removed", "count", len(entries))
+	return nil
+}
+
+func (s *knownHostsService) readLines() ([]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// writeLines rewrites the file, keeping the previous version as known_hosts.old like ssh-keygen -R.
+func (s *knownHostsService) writeLines(lines []string) error {
+	if data, err := os.ReadFile(s.path); err == nil {
+		if err := os.WriteFile(s.path+".old", data, 0o600); err != nil {
+			return fmt.Errorf("backup %s: %w", s.path, err)
+		}
+	}
+	content := strings.Join(lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// removeKnownHostLines drops the lines of entries, refusing if the file changed since they were read.
+func removeKnownHostLines(path string, lines []string, entries []domain.KnownHost) ([]string, error) {
+	drop := make(map[int]bool, len(entries))
+	for _, entry := range entries {
+		if entry.File != path {
+			continue
+		}
+		current, ok := domain.KnownHost{}, false
+		if entry.Line >= 1 && entry.Line <= len(lines) {
+			current, ok = parseKnownHostLine(path, entry.Line, lines[entry.Line-1])
+		}
+		if !ok || current.Fingerprint != entry.Fingerprint {
+			return nil, fmt.Errorf("%s changed since it was read; reload and try again", path)
+		}
+		drop[entry.Line] = true
+	}
+
+	kept := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if !drop[i+1] {
+			kept = append(kept, line)
+		}
+	}
+	return kept, nil
+}
+
+func parseKnownHostLine(path string, lineNo int, line string) (domain.KnownHost, bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return domain.KnownHost{}, false
+	}
+	marker, hosts, key, comment, _, err := ssh.ParseKnownHosts([]byte(trimmed))
+	if err != nil {
+		return domain.KnownHost{}, false
+	}
+	return domain.KnownHost{
+		File:        path,
+		Line:        lineNo,
+		Hosts:       hosts,
+		Hashed:      len(hosts) > 0 && strings.HasPrefix(hosts[0], "|1|"),
+		Marker:      marker,
+		KeyType:     key.Type(),
+		Fingerprint: ssh.FingerprintSHA256(key),
+		Comment:     comment,
+	}, true
+}
+
+// scanHostKeys asks the server for its host keys with ssh-keyscan, run on the last
+// hop of jump when the server is reached through a ProxyJump.
+func scanHostKeys(ctx context.Context, jump, host string, port int, address string, hash bool) ([]domain.HostKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*hostKeyScanTimeout)
+	defer cancel()
+
+	args := keyscanArgs(jump, host, port)
+	//nolint:gosec // G204: host, port and jump hosts come from the user's own SSH config
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if args[0] == "ssh" {
+			return nil, fmt.Errorf("ssh-keyscan via %s: %w", jump, remoteError(err, stderr.String()))
+		}
+		return nil, fmt.Errorf("ssh-keyscan: %w", remoteError(err, stderr.String()))
+	}
+
+	keys := parseKeyscan(stdout.Bytes(), address, hash)
+	if len(keys) == 0 {
+		if msg := lastLine(stderr.String()); msg != "" && !strings.HasPrefix(msg, "#") {
+			return nil, fmt.Errorf("ssh-keyscan: %s", msg)
+		}
+		return nil, fmt.Errorf("%s did not offer any host keys", address)
+	}
+	return keys, nil
+}
+
+// keyscanArgs returns the command line that scans host:port, directly or through
+// the last hop of jump, reaching that hop through the earlier ones like ssh -J does.
+func keyscanArgs(jump, host string, port int) []string {
+	keyscan := []string{"ssh-keyscan", "-T", strconv.Itoa(int(hostKeyScanTimeout / time.Second)), "-p", strconv.Itoa(port), host}
+	if jump == "" || jump == "none" {
+		return keyscan
+	}
+	args := []string{"ssh", "-o", "BatchMode=yes"}
+	hops := strings.Split(jump, ",")
+	if len(hops) > 1 {
+		args = append(args, "-J", strings.Join(hops[:len(hops)-1], ","))
+	}
+	quoted := make([]string, len(keyscan))
+	for i, arg := range keyscan {
+		quoted[i] = shellQuote(arg)
+	}
+	return append(args, "ssh://"+hops[len(hops)-1], "--", strings.Join(quoted, " "))
+}
+
+// parseKeyscan reads ssh-keyscan output into host keys recorded under address,
+// hashing the name when hash is set.
+func parseKeyscan(out []byte, address string, hash bool) []domain.HostKey {
+	name := address
+	if hash {
+		name = knownhosts.HashHostname(address)
+	}
+	var keys []domain.HostKey
+	for {
+		_, _, key, _, next, err := ssh.ParseKnownHosts(out)
+		if err != nil {
+			return keys
+		}
+		out = next
+		keys = append(keys, domain.HostKey{
+			Type:        key.Type(),
+			Fingerprint: ssh.FingerprintSHA256(key),
+			Line:        knownhosts.Line([]string{name}, key),
+		})
+	}
+}
+
+// resolveHostOptions returns the settings ssh -G resolves for server, falling back to
+// the server's own ProxyJump when ssh cannot resolve them.
+func resolveHostOptions(ctx context.Context, server domain.Server) map[string]string {
+	//nolint:gosec // G204: the alias comes from the user's own SSH config
+	out, err := exec.CommandContext(ctx, "ssh", "-G", server.Alias).Output()
+	if err != nil {
+		return map[string]string{"proxyjump": server.ProxyJump}
+	}
+	return parseSSHConfigDump(string(out))
+}
+
+// hostAndPort returns the name and port ssh connects to for server.
+func hostAndPort(server domain.Server) (string, int) {
+	host, port := server.Host, server.Port
+	if host == "" {
+		host = server.Alias
+	}
+	if port == 0 {
+		port = 22
+	}
+	return host, port
+}
+
+// probeKey is a key no server has; see Lookup.
+var probeKey = func() ssh.PublicKey {
+	key, err := ssh.NewPublicKey(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)).Public())
+	if err != nil {
+		panic(err)
+	}
+	return key
+}()
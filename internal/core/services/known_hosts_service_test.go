@@ -0,0 +1,154 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"crypto/ed25519"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func testHostKey(t *testing.T, seed byte) ssh.PublicKey {
+	t.Helper()
+	s := make([]byte, ed25519.SeedSize)
+	s[0] = seed
+	key, err := ssh.NewPublicKey(ed25519.NewKeyFromSeed(s).Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestKnownHostsLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	web, db, other := testHostKey(t, 1), testHostKey(t, 2), testHostKey(t, 3)
+	content := strings.Join([]string{
+		"# comment",
+		knownhosts.Line([]string{"web.example.com"}, web),
+		knownhosts.Line([]string{knownhosts.HashHostname("[db.example.com]:2222")}, db),
+		knownhosts.Line([]string{"other.example.com"}, other),
+	}, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewKnownHostsService(zap.NewNop().Sugar(), path)
+
+	tests := []struct {
+		name   string
+		server domain.Server
+		want   string
+		hashed bool
+	}{
+		{"plain", domain.Server{Alias: "web", Host: "web.example.com"}, ssh.FingerprintSHA256(web), false},
+		{"hashed with port", domain.Server{Alias: "db", Host: "db.example.com", Port: 2222}, ssh.FingerprintSHA256(db), true},
+		{"hashed wrong port", domain.Server{Alias: "db", Host: "db.example.com"}, "", false},
+		{"alias as host", domain.Server{Alias: "other.example.com"}, ssh.FingerprintSHA256(other), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Lookup(tt.server)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Fatalf("Lookup() = %v, want no entries", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Fingerprint != tt.want || got[0].Hashed != tt.hashed {
+				t.Fatalf("Lookup() = %+v, want fingerprint %s hashed=%v", got, tt.want, tt.hashed)
+			}
+		})
+	}
+
+	entries, err := s.Lookup(domain.Server{Alias: "web", Host: "web.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove(entries); err != nil {
+		t.Fatal(err)
+	}
+	remaining, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 {
+		t.Fatalf("List() after Remove = %d entries, want 2", len(remaining))
+	}
+	if _, err := os.Stat(path + ".old"); err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+}
+
+func TestKeyscanArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		jump string
+		want []string
+	}{
+		{"direct", "", []string{"ssh-keyscan", "-T", "5", "-p", "2222", "db.internal"}},
+		{"none", "none", []string{"ssh-keyscan", "-T", "5", "-p", "2222", "db.internal"}},
+		{"one hop", "bastion", []string{"ssh", "-o", "BatchMode=yes", "ssh://bastion", "--",
+			"'ssh-keyscan' '-T' '5' '-p' '2222' 'db.internal'"}},
+		{"two hops", "edge,admin@bastion:2200", []string{"ssh", "-o", "BatchMode=yes", "-J", "edge", "ssh://admin@bastion:2200", "--",
+			"'ssh-keyscan' '-T' '5' '-p' '2222' 'db.internal'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keyscanArgs(tt.jump, "db.internal", 2222); !slices.Equal(got, tt.want) {
+				t.Fatalf("keyscanArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeyscanHashed(t *testing.T) {
+	key := testHostKey(t, 4)
+	out := []byte("# db.internal:2222 SSH-2.0-OpenSSH_9.2\n" + knownhosts.Line([]string{"[db.internal]:2222"}, key) + "\n")
+	address := knownhosts.Normalize("db.internal:2222")
+
+	for _, hash := range []bool{false, true} {
+		keys := parseKeyscan(out, address, hash)
+		if len(keys) != 1 || keys[0].Fingerprint != ssh.FingerprintSHA256(key) {
+			t.Fatalf("parseKeyscan(hash=%v) = %+v", hash, keys)
+		}
+		entry, ok := parseKnownHostLine("known_hosts", 1, keys[0].Line)
+		if !ok || entry.Hashed != hash {
+			t.Fatalf("line %q: hashed = %v, want %v", keys[0].Line, entry.Hashed, hash)
+		}
+
+		path := filepath.Join(t.TempDir(), "known_hosts")
+		if err := os.WriteFile(path, []byte(keys[0].Line+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		callback, err := knownhosts.New(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := callback("db.internal:2222", &net.TCPAddr{IP: net.IPv4zero, Port: 2222}, key); err != nil {
+			t.Fatalf("ssh would reject the recorded line %q: %v", keys[0].Line, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package services
+
+import (
+	"os"
+	"os/exec"
+)
+
+// detachProcess is a no-op on Windows; child processes are not tied to the console session.
+func detachProcess(cmd *exec.Cmd, detach bool) {}
+
+// terminateProcess kills the process.
+func terminateProcess(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
+
+// processAlive cannot cheaply probe processes on Windows, so adopted tunnels are not tracked.
+func processAlive(pid int) bool {
+	return false
+}
+
+func processCommandLine(pid int) string {
+	return ""
+}
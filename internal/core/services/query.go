@@ -0,0 +1,620 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+)
+
+// Query is a parsed search query.
+//
+// The language is a list of terms that must all match. A term is either a plain
+// word, fuzzy-matched against aliases, host, user and tags, or a field filter:
+//
+//	tag:prod  user:root  port:2222  host:10.0.*  alias:web*  pinned:true
+//
+// Field values may be globs and comma-separated alternatives (tag:prod,staging).
+// Without glob characters tag, user and port must match exactly while host and
+// alias match substrings. A term prefixed with ! is negated, "|" or OR separates
+// alternatives, parentheses group terms, and double quotes keep spaces in a value.
+type Query struct {
+	root queryNode
+}
+
+// QueryError reports a syntax error in a search query.
+type QueryError struct {
+	// Pos is the rune offset of the offending token.
+	Pos int
+	Msg string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s (col %d)", e.Msg, e.Pos+1)
+}
+
+// queryFields are the field names accepted before a colon.
+var queryFields = []string{"tag", "user", "port", "host", "alias", "pinned"}
+
+// ParseQuery parses input; an empty input yields a query that matches every server.
+func ParseQuery(input string) (*Query, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return &Query{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &QueryError{Pos: tok.pos, Msg: "unexpected )"}
+	}
+	return &Query{root: root}, nil
+}
+
+// Empty reports whether the query matches every server.
+func (q *Query) Empty() bool {
+	return q.root == nil
+}
+
+// Match reports whether server satisfies the query and how well its plain words matched.
+func (q *Query) Match(server domain.Server) (domain.SearchMatch, bool) {
+	match := domain.SearchMatch{Server: server}
+	if q.root == nil {
+		return match, true
+	}
+	target := newSearchTarget(server)
+	if !q.root.match(target) {
+		return match, false
+	}
+	for _, hit := range target.hits {
+		match.Score += hit.score
+		switch {
+		case strings.HasPrefix(hit.field, "alias:"):
+			// Secondary aliases are not displayed, so there is nothing to highlight.
+		case hit.field == "alias":
+			match.Highlights.Alias = mergeOffsets(match.Highlights.Alias, hit.offsets)
+		case hit.field == "host":
+			match.Highlights.Host = mergeOffsets(match.Highlights.Host, hit.offsets)
+		case hit.field == "user":
+			match.Highlights.User = mergeOffsets(match.Highlights.User, hit.offsets)
+		default:
+			tag := strings.TrimPrefix(hit.field, "tag:")
+			if match.Highlights.Tags == nil {
+				match.Highlights.Tags = make(map[string][]int)
+			}
+			match.Highlights.Tags[tag] = mergeOffsets(match.Highlights.Tags[tag], hit.offsets)
+		}
+	}
+	return match, true
+}
+
+// searchTarget holds the lowercased fields of a server so each is folded once per search.
+type searchTarget struct {
+	aliases []string
+	host    string
+	user    string
+	tags    []string
+	rawTags []string
+	port    string
+	pinned  bool
+
+	// negated counts the enclosing ! operators; plain words under one match as substrings.
+	negated int
+	// hits collects the fuzzy matches of plain words that are part of the current match.
+	hits []searchHit
+}
+
+type searchHit struct {
+	field   string
+	score   int
+	offsets []int
+}
+
+func newSearchTarget(server domain.Server) *searchTarget {
+	t := &searchTarget{
+		host:    strings.ToLower(server.Host),
+		user:    strings.ToLower(server.User),
+		port:    strconv.Itoa(server.Port),
+		pinned:  !server.PinnedAt.IsZero(),
+		rawTags: server.Tags,
+	}
+	aliases := server.Aliases
+	if len(aliases) == 0 {
+		aliases = []string{server.Alias}
+	}
+	for _, alias := range aliases {
+		t.aliases = append(t.aliases, strings.ToLower(alias))
+	}
+	for _, tag := range server.Tags {
+		t.tags = append(t.tags, strings.ToLower(tag))
+	}
+	return t
+}
+
+type queryNode interface {
+	match(t *searchTarget) bool
+}
+
+type andNode []queryNode
+
+func (n andNode) match(t *searchTarget) bool {
+	mark := len(t.hits)
+	for _, child := range n {
+		if !child.match(t) {
+			t.hits = t.hits[:mark]
+			return false
+		}
+	}
+	return true
+}
+
+type orNode []queryNode
+
+// match evaluates every alternative so all matching words are highlighted.
+func (n orNode) match(t *searchTarget) bool {
+	matched := false
+	for _, child := range n {
+		if child.match(t) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+type notNode struct {
+	child queryNode
+}
+
+func (n notNode) match(t *searchTarget) bool {
+	mark := len(t.hits)
+	t.negated++
+	matched := n.child.match(t)
+	t.negated--
+	t.hits = t.hits[:mark]
+	return !matched
+}
+
+type fieldNode struct {
+	field    string
+	patterns []string
+}
+
+func (n fieldNode) match(t *searchTarget) bool {
+	switch n.field {
+	case "tag":
+		return anyMatches(t.tags, n.patterns, false)
+	case "user":
+		return anyMatches([]string{t.user}, n.patterns, false)
+	case "port":
+		return anyMatches([]string{t.port}, n.patterns, false)
+	case "host":
+		host := t.host
+		if host == "" && len(t.aliases) > 0 {
+			host = t.aliases[0]
+		}
+		return anyMatches([]string{host}, n.patterns, true)
+	case "alias":
+		return anyMatches(t.aliases, n.patterns, true)
+	case "pinned":
+		for _, p := range n.patterns {
+			if (p == "true" || p == "yes") == t.pinned {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// anyMatches reports whether a value matches a pattern; patterns without glob
+// characters match exactly, or as substrings when substring is set.
+func anyMatches(values, patterns []string, substring bool) bool {
+	for _, p := range patterns {
+		glob := strings.ContainsAny(p, "*?[")
+		for _, v := range values {
+			switch {
+			case glob:
+				if ok, _ := path.Match(p, v); ok {
+					return true
+				}
+			case substring:
+				if strings.Contains(v, p) {
+					return true
+				}
+			case v == p:
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type wordNode struct {
+	word []rune
+}
+
+func (n wordNode) match(t *searchTarget) bool {
+	if t.negated > 0 {
+		w := string(n.word)
+		for _, field := range t.fields() {
+			if strings.Contains(field.text, w) {
+				return true
+			}
+		}
+		return false
+	}
+
+	best := searchHit{score: -1}
+	for _, field := range t.fields() {
+		if score, offsets, ok := fuzzyMatch([]rune(field.text), n.word); ok && score > best.score {
+			best = searchHit{field: field.name, score: score, offsets: offsets}
+		}
+	}
+	if best.score < 0 {
+		return false
+	}
+	t.hits = append(t.hits, best)
+	return true
+}
+
+type searchField struct {
+	name string
+	text string
+}
+
+// fields lists the values plain words are matched against. Only the primary alias
+// is highlighted, so other aliases are reported under their own names.
+func (t *searchTarget) fields() []searchField {
+	fields := make([]searchField, 0, len(t.aliases)+len(t.tags)+2)
+	for i, alias := range t.aliases {
+		name := "alias"
+		if i > 0 {
+			name = "alias:" + alias
+		}
+		fields = append(fields, searchField{name: name, text: alias})
+	}
+	fields = append(fields, searchField{name: "host", text: t.host}, searchField{name: "user", text: t.user})
+	for i, tag := range t.tags {
+		fields = append(fields, searchField{name: "tag:" + t.rawTags[i], text: tag})
+	}
+	return fields
+}
+
+// Fuzzy scoring in the spirit of fzf: every matched character scores, with
+// bonuses for runs of consecutive characters and for matches at the start of a
+// word, and a small penalty for the characters skipped in between.
+const (
+	scoreMatch        = 16
+	bonusConsecutive  = 8
+	bonusBoundary     = 8
+	bonusFirstChar    = 8
+	penaltyGap        = 1
+	maxPenaltyPerGap  = 8
+	bonusExactPattern = 16
+)
+
+// fuzzyMatch finds pattern as a subsequence of text and scores the shortest window
+// ending at its first complete occurrence. It returns the matched rune offsets.
+func fuzzyMatch(text, pattern []rune) (int, []int, bool) {
+	if len(pattern) == 0 || len(pattern) > len(text) {
+		return 0, nil, false
+	}
+
+	// Forward pass: find where the first complete occurrence ends.
+	end, pi := -1, 0
+	for i, r := range text {
+		if r == pattern[pi] {
+			pi++
+			if pi == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// Backward pass: shrink the window to the latest possible start.
+	start := end
+	pi = len(pattern) - 1
+	for i := end; i >= 0; i-- {
+		if text[i] == pattern[pi] {
+			pi--
+			if pi < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	score := 0
+	offsets := make([]int, 0, len(pattern))
+	pi, prev := 0, -1
+	for i := start; i <= end && pi < len(pattern); i++ {
+		if text[i] != pattern[pi] {
+			continue
+		}
+		score += scoreMatch
+		switch {
+		case i == 0:
+			score += bonusBoundary + bonusFirstChar
+		case isWordBoundary(text[i-1]):
+			score += bonusBoundary
+		}
+		if prev >= 0 {
+			if i == prev+1 {
+				score += bonusConsecutive
+			} else {
+				score -= min((i-prev-1)*penaltyGap, maxPenaltyPerGap)
+			}
+		}
+		offsets = append(offsets, i)
+		prev = i
+		pi++
+	}
+	if len(text) == len(pattern) {
+		score += bonusExactPattern
+	}
+	return score, offsets, true
+}
+
+func isWordBoundary(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// mergeOffsets returns the sorted union of two offset lists.
+func mergeOffsets(a, b []int) []int {
+	if len(a) == 0 {
+		return b
+	}
+	seen := make(map[int]bool, len(a))
+	for _, o := range a {
+		seen[o] = true
+	}
+	merged := append([]int(nil), a...)
+	for _, o := range b {
+		if !seen[o] {
+			merged = append(merged, o)
+		}
+	}
+	sort.Ints(merged)
+	return merged
+}
+
+// Tokenizer and parser.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokNot
+	tokOr
+	tokLParen
+	tokRParen
+)
+
+type queryToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func tokenizeQuery(input string) ([]queryToken, error) {
+	runes := []rune(input)
+	var tokens []queryToken
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokLParen, pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokRParen, pos: i})
+			i++
+		case r == '|':
+			tokens = append(tokens, queryToken{kind: tokOr, pos: i})
+			i++
+		case r == '!':
+			tokens = append(tokens, queryToken{kind: tokNot, pos: i})
+			i++
+		default:
+			start := i
+			var b strings.Builder
+			quoted := false
+			for ; i < len(runes); i++ {
+				c := runes[i]
+				if c == '"' {
+					quoted = !quoted
+					continue
+				}
+				if !quoted && (unicode.IsSpace(c) || c == '(' || c == ')' || c == '|') {
+					break
+				}
+				b.WriteRune(c)
+			}
+			if quoted {
+				return nil, &QueryError{Pos: start, Msg: "unterminated quote"}
+			}
+			if b.String() == "OR" {
+				tokens = append(tokens, queryToken{kind: tokOr, pos: start})
+				continue
+			}
+			tokens = append(tokens, queryToken{kind: tokWord, text: b.String(), pos: start})
+		}
+	}
+	return append(tokens, queryToken{kind: tokEOF, pos: len(runes)}), nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr parses alternatives separated by | or OR.
+func (p *queryParser) parseOr() (queryNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	alternatives := orNode{first}
+	for p.peek().kind == tokOr {
+		p.next()
+		alt, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, alt)
+	}
+	if len(alternatives) == 1 {
+		return first, nil
+	}
+	return alternatives, nil
+}
+
+// parseAnd parses terms up to the next |, ) or the end of the query.
+func (p *queryParser) parseAnd() (queryNode, error) {
+	var terms andNode
+	for {
+		switch tok := p.peek(); tok.kind {
+		case tokEOF, tokOr, tokRParen:
+			if len(terms) == 0 {
+				return nil, &QueryError{Pos: tok.pos, Msg: "expected a search term"}
+			}
+			if len(terms) == 1 {
+				return terms[0], nil
+			}
+			return terms, nil
+		}
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNot:
+		if next := p.peek(); next.kind != tokWord && next.kind != tokLParen && next.kind != tokNot {
+			return nil, &QueryError{Pos: tok.pos, Msg: "expected a term after !"}
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{child: child}, nil
+	case tokLParen:
+		group, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, &QueryError{Pos: tok.pos, Msg: "missing )"}
+		}
+		p.next()
+		return group, nil
+	case tokWord:
+		return parseTerm(tok)
+	default:
+		return nil, &QueryError{Pos: tok.pos, Msg: "expected a search term"}
+	}
+}
+
+// parseTerm turns a word into a field filter or a plain fuzzy word.
+func parseTerm(tok queryToken) (queryNode, error) {
+	name, value, hasColon := strings.Cut(tok.text, ":")
+	if !hasColon || !isFieldName(name) {
+		return wordNode{word: []rune(strings.ToLower(tok.text))}, nil
+	}
+	field := strings.ToLower(name)
+	known := false
+	for _, f := range queryFields {
+		if f == field {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, &QueryError{Pos: tok.pos, Msg: fmt.Sprintf("unknown field %q (want %s)", name, strings.Join(queryFields, ", "))}
+	}
+	if value == "" {
+		return nil, &QueryError{Pos: tok.pos, Msg: field + ": needs a value"}
+	}
+
+	var patterns []string
+	for _, p := range strings.Split(strings.ToLower(value), ",") {
+		if p == "" {
+			return nil, &QueryError{Pos: tok.pos, Msg: field + ": empty alternative"}
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, &QueryError{Pos: tok.pos, Msg: fmt.Sprintf("%s: bad pattern %q", field, p)}
+		}
+		switch field {
+		case "pinned":
+			if p != "true" && p != "false" && p != "yes" && p != "no" {
+				return nil, &QueryError{Pos: tok.pos, Msg: "pinned: want true or false"}
+			}
+		case "port":
+			if _, err := strconv.Atoi(p); err != nil && !strings.ContainsAny(p, "*?[") {
+				return nil, &QueryError{Pos: tok.pos, Msg: fmt.Sprintf("port: %q is not a number", p)}
+			}
+		}
+		patterns = append(patterns, p)
+	}
+	return fieldNode{field: field, patterns: patterns}, nil
+}
+
+// isFieldName reports whether s looks like a field name rather than part of a
+// host such as 10.0.0.5:22 or an IPv6 address.
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
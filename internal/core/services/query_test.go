@@ -0,0 +1,132 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+)
+
+func TestQueryMatch(t *testing.T) {
+	servers := []domain.Server{
+		{Alias: "web1", Aliases: []string{"web1"}, Host: "10.0.0.5", User: "root", Port: 22, Tags: []string{"prod", "web"}, PinnedAt: time.Now()},
+		{Alias: "web2", Aliases: []string{"web2"}, Host: "10.0.1.7", User: "deploy", Port: 2222, Tags: []string{"dev", "web"}},
+		{Alias: "db-main", Aliases: []string{"db-main", "postgres"}, Host: "db.example.com", User: "root", Port: 5432, Tags: []string{"prod"}},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"web1", "web2", "db-main"}},
+		{"tag:prod", []string{"web1", "db-main"}},
+		{"TAG:Prod user:root", []string{"web1", "db-main"}},
+		{"port:2222", []string{"web2"}},
+		{"port:22*", []string{"web1", "web2"}},
+		{"host:10.0.*", []string{"web1", "web2"}},
+		{"host:example", []string{"db-main"}},
+		{"alias:post*", []string{"db-main"}},
+		{"!tag:dev", []string{"web1", "db-main"}},
+		{"tag:dev | user:root port:5432", []string{"web2", "db-main"}},
+		{"tag:dev OR pinned:true", []string{"web1", "web2"}},
+		{"tag:prod,dev !(host:db* | pinned:yes)", []string{"web2"}},
+		{"pinned:false", []string{"web2", "db-main"}},
+		{"wb2", []string{"web2"}},
+		{"web !web1", []string{"web2"}},
+		{"dbmn", []string{"db-main"}},
+		{`user:"root"`, []string{"web1", "db-main"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+			}
+			var got []string
+			for _, server := range servers {
+				if _, ok := q.Match(server); ok {
+					got = append(got, server.Alias)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("matched %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("matched %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryScoreAndHighlights(t *testing.T) {
+	q, err := ParseQuery("web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exact, ok := q.Match(domain.Server{Alias: "web", Host: "10.0.0.1"})
+	if !ok {
+		t.Fatal("exact alias did not match")
+	}
+	scattered, ok := q.Match(domain.Server{Alias: "w-e-b", Host: "10.0.0.2"})
+	if !ok {
+		t.Fatal("scattered alias did not match")
+	}
+	if exact.Score <= scattered.Score {
+		t.Errorf("exact score %d should beat scattered score %d", exact.Score, scattered.Score)
+	}
+	if got := scattered.Highlights.Alias; len(got) != 3 || got[0] != 0 || got[1] != 2 || got[2] != 4 {
+		t.Errorf("Highlights.Alias = %v, want [0 2 4]", got)
+	}
+
+	tagged, _ := q.Match(domain.Server{Alias: "db1", Tags: []string{"Webapp"}})
+	if got := tagged.Highlights.Tags["Webapp"]; len(got) != 3 {
+		t.Errorf("Highlights.Tags[Webapp] = %v, want three offsets", got)
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{"colour:red", 0},
+		{"tag:", 0},
+		{"web port:ssh", 4},
+		{"pinned:maybe", 0},
+		{"(tag:prod", 0},
+		{"tag:prod)", 8},
+		{"tag:prod |", 10},
+		{"!", 0},
+		{`user:"root`, 0},
+		{"host:[10", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQuery(tt.query)
+			var qerr *QueryError
+			if !errors.As(err, &qerr) {
+				t.Fatalf("ParseQuery(%q) error = %v, want a QueryError", tt.query, err)
+			}
+			if qerr.Pos != tt.pos {
+				t.Errorf("ParseQuery(%q) error at %d, want %d (%v)", tt.query, qerr.Pos, tt.pos, err)
+			}
+		})
+	}
+}
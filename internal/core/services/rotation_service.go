@@ -0,0 +1,271 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// rotationConcurrency bounds how many servers are contacted at once.
+const rotationConcurrency = 5
+
+type rotationService struct {
+	logger           *zap.SugaredLogger
+	serverRepository ports.ServerRepository
+	keys             ports.KeyService
+	stateDir         string
+
+	// configMu serializes IdentityFile updates, which all rewrite the same ssh config.
+	configMu sync.Mutex
+}
+
+// NewRotationService creates a key rotation service that keeps its reports in stateDir.
+func NewRotationService(logger *zap.SugaredLogger, sr ports.ServerRepository, keys ports.KeyService, stateDir string) ports.RotationService {
+	return &rotationService{
+		logger:           logger,
+		serverRepository: sr,
+		keys:             keys,
+		stateDir:         stateDir,
+	}
+}
+
+// Pending returns the unfinished rotations, newest first.
+func (s *rotationService) Pending() ([]domain.KeyRotation, error) {
+	entries, err := os.ReadDir(s.stateDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var pending []domain.KeyRotation
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.stateDir, entry.Name())) //nolint:gosec // G304: our own state directory
+		if err != nil {
+			s.logger.Warnw("failed to read rotation", "file", entry.Name(), "error", err)
+			continue
+		}
+		var rotation domain.KeyRotation
+		if err := json.Unmarshal(data, &rotation); err != nil {
+			s.logger.Warnw("failed to parse rotation", "file", entry.Name(), "error", err)
+			continue
+		}
+		if !rotation.Done() {
+			pending = append(pending, rotation)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].StartedAt.After(pending[j].StartedAt) })
+	return pending, nil
+}
+
+// Start generates the new key and plans replacing oldKey on every server that uses it.
+func (s *rotationService) Start(oldKey string, opts domain.KeyGenOptions) (domain.KeyRotation, error) {
+	oldKey = ExpandHome(oldKey)
+	servers, err := s.serverRepository.ListServers()
+	if err != nil {
+		return domain.KeyRotation{}, err
+	}
+
+	var hosts []domain.RotationHost
+	for _, server := range servers {
+		for _, identity := range server.IdentityFiles {
+			if ExpandHome(identity) == oldKey {
+				hosts = append(hosts, domain.RotationHost{Alias: server.Alias})
+				break
+			}
+		}
+	}
+	if len(hosts) == 0 {
+		return domain.KeyRotation{}, fmt.Errorf("no server uses %s", collapseHome(oldKey))
+	}
+
+	newKey, err := s.keys.Generate(opts)
+	if err != nil {
+		return domain.KeyRotation{}, err
+	}
+
+	now := time.Now()
+	rotation := domain.KeyRotation{
+		ID:        now.Format("20060102-150405"),
+		OldKey:    oldKey,
+		NewKey:    newKey.Path,
+		StartedAt: now,
+		Hosts:     hosts,
+	}
+	if err := s.save(rotation); err != nil {
+		return domain.KeyRotation{}, err
+	}
+	s.logger.Infow("key rotation started", "id", rotation.ID, "old", oldKey, "new", newKey.Path, "hosts", len(hosts))
+	return rotation, nil
+}
+
+// Run performs the steps still missing on each server and saves the report after every step.
+// Old keys are only removed once every server has the new key installed and configured, so
+// accounts shared by several aliases are never locked out halfway; until then the rotation
+// stays pending.
+func (s *rotationService) Run(ctx context.Context, rotation domain.KeyRotation, onUpdate func(domain.KeyRotation)) (domain.KeyRotation, error) {
+	var (
+		mu      sync.Mutex
+		saveErr error
+	)
+	update := func(i int, step func(*domain.RotationHost) error) {
+		mu.Lock()
+		host := rotation.Hosts[i]
+		mu.Unlock()
+
+		err := step(&host)
+		host.Error = ""
+		if err != nil {
+			host.Error = err.Error()
+		}
+		host.UpdatedAt = time.Now()
+
+		mu.Lock()
+		rotation.Hosts[i] = host
+		if err := s.save(rotation); err != nil && saveErr == nil {
+			saveErr = err
+		}
+		snapshot := rotation
+		snapshot.Hosts = append([]domain.RotationHost(nil), rotation.Hosts...)
+		mu.Unlock()
+
+		if onUpdate != nil {
+			onUpdate(snapshot)
+		}
+	}
+
+	phases := []struct {
+		// ready, when set, must hold for every host before the phase starts.
+		ready  func(domain.RotationHost) bool
+		needed func(domain.RotationHost) bool
+		step   func(*domain.RotationHost) error
+	}{
+		{
+			needed: func(h domain.RotationHost) bool { return !h.Installed },
+			step: func(h *domain.RotationHost) error {
+				// InstallKey verifies a login offering only the new key.
+				result := s.keys.InstallKey(ctx, h.Alias, rotation.NewKey)
+				if !result.Verified {
+					return errors.New(result.Error)
+				}
+				h.Installed = true
+				return nil
+			},
+		},
+		{
+			needed: func(h domain.RotationHost) bool { return h.Installed && !h.ConfigUpdated },
+			step: func(h *domain.RotationHost) error {
+				s.configMu.Lock()
+				defer s.configMu.Unlock()
+				if err := s.keys.ReplaceKey(h.Alias, rotation.OldKey, rotation.NewKey); err != nil {
+					return fmt.Errorf("update IdentityFile: %w", err)
+				}
+				h.ConfigUpdated = true
+				return nil
+			},
+		},
+		{
+			ready:  func(h domain.RotationHost) bool { return h.Installed && h.ConfigUpdated },
+			needed: func(h domain.RotationHost) bool { return h.ConfigUpdated && !h.OldKeyRemoved },
+			step: func(h *domain.RotationHost) error {
+				// Never remove the old key without proof that the new one still works.
+				// VerifyKey offers the new key alone, so the old key, which may still be
+				// configured for other aliases of the account, cannot pass the check.
+				if err := s.keys.VerifyKey(ctx, h.Alias, rotation.NewKey); err != nil {
+					return fmt.Errorf("login with new key failed, old key kept: %w", err)
+				}
+				if _, err := s.keys.RemoveKey(ctx, h.Alias, rotation.OldKey); err != nil {
+					return fmt.Errorf("remove old key: %w", err)
+				}
+				h.OldKeyRemoved = true
+				return nil
+			},
+		},
+	}
+
+	for _, phase := range phases {
+		if phase.ready != nil && slices.ContainsFunc(rotation.Hosts, func(h domain.RotationHost) bool { return !phase.ready(h) }) {
+			break
+		}
+		sem := make(chan struct{}, rotationConcurrency)
+		var wg sync.WaitGroup
+		for i := range rotation.Hosts {
+			mu.Lock()
+			needed := phase.needed(rotation.Hosts[i])
+			mu.Unlock()
+			if !needed || ctx.Err() != nil {
+				continue
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+				update(i, phase.step)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	if rotation.Done() {
+		rotation.FinishedAt = time.Now()
+		if err := s.save(rotation); err != nil && saveErr == nil {
+			saveErr = err
+		}
+		s.logger.Infow("key rotation finished", "id", rotation.ID)
+	}
+	if err := ctx.Err(); err != nil {
+		return rotation, err
+	}
+	return rotation, saveErr
+}
+
+// ReportPath returns the file holding the per-host report of rotation id.
+func (s *rotationService) ReportPath(id string) string {
+	return filepath.Join(s.stateDir, "rotation-"+id+".json")
+}
+
+func (s *rotationService) save(rotation domain.KeyRotation) error {
+	if err := os.MkdirAll(s.stateDir, 0o700); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", s.stateDir, err)
+	}
+	data, err := json.MarshalIndent(rotation, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal rotation: %w", err)
+	}
+	p := s.ReportPath(rotation.ID)
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, p)
+}
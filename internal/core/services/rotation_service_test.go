@@ -0,0 +1,288 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// fakeKeys records the key operations of a rotation and fails them on request.
+type fakeKeys struct {
+	ports.KeyService
+	mu            sync.Mutex
+	calls         []string
+	failInstall   map[string]bool
+	failVerify    map[string]bool
+	afterInstalls func()
+	installs      int
+}
+
+func (k *fakeKeys) record(call string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.calls = append(k.calls, call)
+}
+
+func (k *fakeKeys) Generate(opts domain.KeyGenOptions) (domain.SSHKey, error) {
+	k.record("generate")
+	return domain.SSHKey{Path: opts.Path}, nil
+}
+
+func (k *fakeKeys) InstallKey(_ context.Context, alias, keyPath string) domain.KeyInstallResult {
+	k.record("install " + alias)
+	defer func() {
+		k.mu.Lock()
+		k.installs++
+		done := k.afterInstalls != nil && k.installs == 1
+		k.mu.Unlock()
+		if done {
+			k.afterInstalls()
+		}
+	}()
+	if k.failInstall[alias] {
+		return domain.KeyInstallResult{Alias: alias, Error: "permission denied"}
+	}
+	return domain.KeyInstallResult{Alias: alias, Added: true, Verified: true}
+}
+
+func (k *fakeKeys) VerifyKey(_ context.Context, alias, _ string) error {
+	k.record("verify " + alias)
+	if k.failVerify[alias] {
+		return errors.New("Permission denied (publickey)")
+	}
+	return nil
+}
+
+func (k *fakeKeys) ReplaceKey(alias, _, _ string) error {
+	k.record("replace " + alias)
+	return nil
+}
+
+func (k *fakeKeys) RemoveKey(_ context.Context, alias, _ string) (bool, error) {
+	k.record("remove " + alias)
+	return true, nil
+}
+
+func (k *fakeKeys) callsFor(prefix string) []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	var calls []string
+	for _, call := range k.calls {
+		if strings.HasPrefix(call, prefix) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func newRotationFixture(t *testing.T, keys *fakeKeys) ports.RotationService {
+	t.Helper()
+	repo := &serversRepo{servers: []domain.Server{
+		{Alias: "web1", IdentityFiles: []string{"/keys/old"}},
+		{Alias: "web2", IdentityFiles: []string{"/keys/other", "/keys/old"}},
+		{Alias: "db1", IdentityFiles: []string{"/keys/other"}},
+	}}
+	return NewRotationService(zap.NewNop().Sugar(), repo, keys, t.TempDir())
+}
+
+func TestRotationStart(t *testing.T) {
+	keys := &fakeKeys{}
+	s := newRotationFixture(t, keys)
+
+	rotation, err := s.Start("/keys/old", domain.KeyGenOptions{Type: "ed25519", Path: "/keys/new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var aliases []string
+	for _, host := range rotation.Hosts {
+		aliases = append(aliases, host.Alias)
+	}
+	if !slices.Equal(aliases, []string{"web1", "web2"}) || rotation.NewKey != "/keys/new" || rotation.OldKey != "/keys/old" {
+		t.Fatalf("rotation = %+v", rotation)
+	}
+	pending, err := s.Pending()
+	if err != nil || len(pending) != 1 || pending[0].ID != rotation.ID {
+		t.Fatalf("Pending = %+v, %v", pending, err)
+	}
+
+	if _, err := s.Start("/keys/unused", domain.KeyGenOptions{Path: "/keys/x"}); err == nil {
+		t.Fatal("rotating a key no server uses succeeded")
+	}
+	if generated := keys.callsFor("generate"); len(generated) != 1 {
+		t.Fatalf("keys generated: %d", len(generated))
+	}
+}
+
+func TestRotationRunPhases(t *testing.T) {
+	keys := &fakeKeys{}
+	s := newRotationFixture(t, keys)
+	rotation, err := s.Start("/keys/old", domain.KeyGenOptions{Path: "/keys/new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var updates atomic.Int32
+	rotation, err = s.Run(context.Background(), rotation, func(domain.KeyRotation) { updates.Add(1) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rotation.Done() || rotation.FinishedAt.IsZero() || updates.Load() != 6 {
+		t.Fatalf("rotation = %+v after %d updates", rotation, updates.Load())
+	}
+
+	// Every install precedes every config change, which precede every removal, and
+	// each removal follows a successful verification of the same host.
+	last := func(prefix string) int {
+		i := -1
+		for j, call := range keys.calls {
+			if strings.HasPrefix(call, prefix) {
+				i = j
+			}
+		}
+		return i
+	}
+	first := func(prefix string) int {
+		return slices.IndexFunc(keys.calls, func(call string) bool { return strings.HasPrefix(call, prefix) })
+	}
+	if last("install") > first("replace") || last("replace") > first("verify") || last("verify") > last("remove") {
+		t.Fatalf("steps out of order: %v", keys.calls)
+	}
+	for _, alias := range []string{"web1", "web2"} {
+		if first("verify "+alias) > first("remove "+alias) {
+			t.Fatalf("%s: old key removed before verification: %v", alias, keys.calls)
+		}
+	}
+	if pending, _ := s.Pending(); len(pending) != 0 {
+		t.Fatalf("finished rotation still pending: %+v", pending)
+	}
+}
+
+func TestRotationStopsOnFailures(t *testing.T) {
+	keys := &fakeKeys{failVerify: map[string]bool{"web2": true}}
+	s := newRotationFixture(t, keys)
+	rotation, err := s.Start("/keys/old", domain.KeyGenOptions{Path: "/keys/new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotation, err = s.Run(context.Background(), rotation, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	web1, web2 := rotation.Hosts[0], rotation.Hosts[1]
+	if !web1.OldKeyRemoved || web1.Error != "" {
+		t.Errorf("web1 = %+v", web1)
+	}
+	if !web2.Installed || !web2.ConfigUpdated || web2.OldKeyRemoved || !strings.Contains(web2.Error, "old key kept") {
+		t.Errorf("web2 = %+v", web2)
+	}
+	if removed := keys.callsFor("remove"); !slices.Equal(removed, []string{"remove web1"}) {
+		t.Errorf("old keys removed: %v", removed)
+	}
+	if rotation.Done() || !rotation.FinishedAt.IsZero() {
+		t.Errorf("failed rotation marked finished: %+v", rotation)
+	}
+}
+
+func TestRotationResume(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Interrupt the rotation once the first install is done.
+	keys := &fakeKeys{afterInstalls: cancel}
+	s := newRotationFixture(t, keys)
+	rotation, err := s.Start("/keys/old", domain.KeyGenOptions{Path: "/keys/new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Run(ctx, rotation, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted Run error = %v", err)
+	}
+	if removed := keys.callsFor("remove"); len(removed) != 0 {
+		t.Fatalf("old keys removed by an interrupted rotation: %v", removed)
+	}
+
+	// A later run picks the saved report up and only does the missing steps.
+	pending, err := s.Pending()
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("Pending = %+v, %v", pending, err)
+	}
+	installed := map[string]bool{}
+	for _, host := range pending[0].Hosts {
+		installed[host.Alias] = host.Installed
+	}
+	installsBefore := len(keys.callsFor("install"))
+
+	resumed, err := s.Run(context.Background(), pending[0], nil)
+	if err != nil || !resumed.Done() {
+		t.Fatalf("resumed rotation = %+v, %v", resumed, err)
+	}
+	for _, call := range keys.callsFor("install")[installsBefore:] {
+		if installed[strings.TrimPrefix(call, "install ")] {
+			t.Errorf("resume repeated a finished step: %s", call)
+		}
+	}
+	if pending, _ := s.Pending(); len(pending) != 0 {
+		t.Fatalf("resumed rotation still pending: %+v", pending)
+	}
+}
+
+func TestRotationKeepsOldKeysUntilAllInstalled(t *testing.T) {
+	keys := &fakeKeys{failInstall: map[string]bool{"web1": true}}
+	s := newRotationFixture(t, keys)
+	rotation, err := s.Start("/keys/old", domain.KeyGenOptions{Path: "/keys/new"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotation, err = s.Run(context.Background(), rotation, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// web2 is ready, but web1 may share its account and still needs the old key.
+	web1, web2 := rotation.Hosts[0], rotation.Hosts[1]
+	if web1.Installed || web1.Error != "permission denied" {
+		t.Errorf("web1 = %+v", web1)
+	}
+	if !web2.Installed || !web2.ConfigUpdated || web2.OldKeyRemoved {
+		t.Errorf("web2 = %+v", web2)
+	}
+	if removed := keys.callsFor("remove"); len(removed) != 0 {
+		t.Errorf("old keys removed while web1 lacks the new key: %v", removed)
+	}
+	if pending, _ := s.Pending(); len(pending) != 1 {
+		t.Fatalf("rotation not left pending: %+v", pending)
+	}
+
+	// Once web1 installs, a resumed run finishes every host.
+	keys.failInstall = nil
+	rotation, err = s.Run(context.Background(), rotation, nil)
+	if err != nil || !rotation.Done() {
+		t.Fatalf("resumed rotation = %+v, %v", rotation, err)
+	}
+	if removed := keys.callsFor("remove"); len(removed) != 2 {
+		t.Fatalf("old keys removed: %v", removed)
+	}
+}
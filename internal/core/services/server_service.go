@@ -21,7 +21,6 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -44,28 +43,42 @@ func NewServerService(logger *zap.SugaredLogger, sr ports.ServerRepository) port
 	}
 }
 
-// ListServers returns a list of servers sorted with pinned on top.
+// ListServers returns the servers matching query, sorted with pinned on top.
+// See Query for the search syntax.
 func (s *serverService) ListServers(query string) ([]domain.Server, error) {
-	servers, err := s.serverRepository.ListServers(query)
+	matches, err := s.SearchServers(query)
+	if err != nil {
+		return nil, err
+	}
+	servers := make([]domain.Server, 0, len(matches))
+	for _, match := range matches {
+		servers = append(servers, match.Server)
+	}
+	return servers, nil
+}
+
+// SearchServers returns the servers matching query with their fuzzy scores and
+// highlighted characters, sorted with pinned on top.
+func (s *serverService) SearchServers(query string) ([]domain.SearchMatch, error) {
+	q, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := s.serverRepository.ListServers()
 	if err != nil {
 		s.logger.Errorw("failed to list servers", "error", err)
 		return nil, err
 	}
 
-	// Sort: pinned first (PinnedAt non-zero), then by PinnedAt desc, then by Alias asc.
-	sort.SliceStable(servers, func(i, j int) bool {
-		pi := !servers[i].PinnedAt.IsZero()
-		pj := !servers[j].PinnedAt.IsZero()
-		if pi != pj {
-			return pi
-		}
-		if pi && pj {
-			return servers[i].PinnedAt.After(servers[j].PinnedAt)
-		}
-		return servers[i].Alias < servers[j].Alias
-	})
+	domain.SortServers(servers, domain.DefaultSortOrder(domain.SortFieldAlias), nil)
 
-	return servers, nil
+	matches := make([]domain.SearchMatch, 0, len(servers))
+	for _, server := range servers {
+		if match, ok := q.Match(server); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
 }
 
 // validateServer performs core validation of server fields.
@@ -117,6 +130,21 @@ func (s *serverService) UpdateServer(server domain.Server, newServer domain.Serv
 	return err
 }
 
+// UpdateServers applies several edits as one write of the SSH config.
+func (s *serverService) UpdateServers(updates []domain.ServerUpdate) error {
+	for _, update := range updates {
+		if err := validateServer(update.New); err != nil {
+			s.logger.Warnw("validation failed on batch update", "error", err, "server", update.New)
+			return fmt.Errorf("%s: %w", update.Old.Alias, err)
+		}
+	}
+	err := s.serverRepository.UpdateServers(updates)
+	if err != nil {
+		s.logger.Errorw("failed to update servers", "error", err, "count", len(updates))
+	}
+	return err
+}
+
 // AddServer adds a new server to the repository.
 func (s *serverService) AddServer(server domain.Server) error {
 	if err := validateServer(server); err != nil {
@@ -139,6 +167,15 @@ func (s *serverService) DeleteServer(server domain.Server) error {
 	return err
 }
 
+// DeleteServers removes several servers as one write of the SSH config.
+func (s *serverService) DeleteServers(servers []domain.Server) error {
+	err := s.serverRepository.DeleteServers(servers)
+	if err != nil {
+		s.logger.Errorw("failed to delete servers", "error", err, "count", len(servers))
+	}
+	return err
+}
+
 // SetPinned sets or clears a pin timestamp for the server alias.
 func (s *serverService) SetPinned(alias string, pinned bool) error {
 	err := s.serverRepository.SetPinned(alias, pinned)
@@ -148,6 +185,52 @@ func (s *serverService) SetPinned(alias string, pinned bool) error {
 	return err
 }
 
+// SetPinnedMany sets or clears the pin timestamp of several servers at once.
+func (s *serverService) SetPinnedMany(aliases []string, pinned bool) error {
+	err := s.serverRepository.SetPinnedMany(aliases, pinned)
+	if err != nil {
+		s.logger.Errorw("failed to set pin state", "error", err, "aliases", aliases, "pinned", pinned)
+	}
+	return err
+}
+
+// ListBackups returns the backups of the SSH config, newest first.
+func (s *serverService) ListBackups() ([]domain.ConfigBackup, error) {
+	backups, err := s.serverRepository.ListBackups()
+	if err != nil {
+		s.logger.Errorw("failed to list backups", "error", err)
+	}
+	return backups, err
+}
+
+// RestoreBackup replaces the SSH config with a backup after backing up the current file.
+func (s *serverService) RestoreBackup(path string) error {
+	err := s.serverRepository.RestoreBackup(path)
+	if err != nil {
+		s.logger.Errorw("failed to restore backup", "error", err, "path", path)
+	}
+	return err
+}
+
+// SetTunnels validates and stores the tunnel definitions for a server.
+func (s *serverService) SetTunnels(alias string, tunnels []domain.Tunnel) error {
+	names := make(map[string]bool, len(tunnels))
+	for _, t := range tunnels {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("tunnel %q: %w", t.Name, err)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("duplicate tunnel name %q", t.Name)
+		}
+		names[t.Name] = true
+	}
+	err := s.serverRepository.SetTunnels(alias, tunnels)
+	if err != nil {
+		s.logger.Errorw("failed to save tunnels", "error", err, "alias", alias)
+	}
+	return err
+}
+
 // SSH starts an interactive SSH session to the given alias using the system's ssh client.
 // If a password is stored for the server, it will be used for authentication.
 func (s *serverService) SSH(alias string) error {
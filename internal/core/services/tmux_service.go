@@ -0,0 +1,161 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// tmuxAliasOption is a pane user option used to find panes opened by dogssh.
+const tmuxAliasOption = "@dogssh_alias"
+
+type tmuxService struct {
+	logger     *zap.SugaredLogger
+	executable string
+	// tmux runs a tmux command and returns its output.
+	tmux func(args ...string) (string, error)
+}
+
+// NewTmuxService creates a tmux service that launches `<executable> connect <alias>` in new panes.
+func NewTmuxService(logger *zap.SugaredLogger, executable string) ports.TmuxService {
+	return &tmuxService{
+		logger:     logger,
+		executable: executable,
+		tmux:       runTmux,
+	}
+}
+
+// Available reports whether the process runs inside tmux and the tmux binary is present.
+func (s *tmuxService) Available() bool {
+	if os.Getenv("TMUX") == "" {
+		return false
+	}
+	_, err := exec.LookPath("tmux")
+	return err == nil
+}
+
+// Open starts a session for alias in a new window or split, or focuses the existing one.
+func (s *tmuxService) Open(alias string, mode domain.TmuxMode) error {
+	if !s.Available() {
+		return fmt.Errorf("not running inside tmux")
+	}
+
+	if paneID, ok := s.findPane(alias); ok {
+		s.logger.Infow("tmux reuse pane", "alias", alias, "pane", paneID)
+		if err := s.run("select-window", "-t", paneID); err != nil {
+			return err
+		}
+		return s.run("select-pane", "-t", paneID)
+	}
+
+	var args []string
+	switch mode {
+	case domain.TmuxModeWindow:
+		args = []string{"new-window", "-n", alias}
+	case domain.TmuxModeHSplit:
+		args = []string{"split-window", "-h"}
+	case domain.TmuxModeVSplit:
+		args = []string{"split-window", "-v"}
+	default:
+		return fmt.Errorf("unsupported tmux mode %q", mode)
+	}
+	args = append(args, "-P", "-F", "#{pane_id}", s.executable, "connect", alias)
+
+	out, err := s.output(args...)
+	if err != nil {
+		return err
+	}
+	paneID := strings.TrimSpace(out)
+	s.logger.Infow("tmux open", "alias", alias, "mode", mode, "pane", paneID)
+
+	s.tagPane(paneID, alias)
+	return nil
+}
+
+// OpenCluster opens a tiled window with one synchronized pane per alias.
+func (s *tmuxService) OpenCluster(name string, aliases []string) error {
+	if !s.Available() {
+		return fmt.Errorf("not running inside tmux")
+	}
+	if len(aliases) == 0 {
+		return fmt.Errorf("no servers selected")
+	}
+
+	// Cluster panes are not tagged with the alias: single-server connects
+	// should never land in a synchronized window.
+	out, err := s.output("new-window", "-n", name, "-P", "-F", "#{window_id}", s.executable, "connect", aliases[0])
+	if err != nil {
+		return err
+	}
+	windowID := strings.TrimSpace(out)
+
+	for _, alias := range aliases[1:] {
+		if err := s.run("split-window", "-t", windowID, s.executable, "connect", alias); err != nil {
+			return err
+		}
+		// Re-tile after every split so tmux always has room for the next pane.
+		if err := s.run("select-layout", "-t", windowID, "tiled"); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Infow("tmux cluster open", "name", name, "aliases", aliases, "window", windowID)
+	return s.run("set-window-option", "-t", windowID, "synchronize-panes", "on")
+}
+
+func (s *tmuxService) tagPane(paneID, alias string) {
+	if err := s.run("set-option", "-p", "-t", paneID, tmuxAliasOption, alias); err != nil {
+		s.logger.Warnw("failed to tag tmux pane", "alias", alias, "pane", paneID, "error", err)
+	}
+}
+
+// findPane returns the id of a pane previously opened for alias in the current session.
+func (s *tmuxService) findPane(alias string) (string, bool) {
+	out, err := s.output("list-panes", "-s", "-F", "#{pane_id}\t#{"+tmuxAliasOption+"}")
+	if err != nil {
+		return "", false
+	}
+	for _, line := range strings.Split(out, "\n") {
+		id, name, ok := strings.Cut(line, "\t")
+		if ok && name == alias {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func (s *tmuxService) run(args ...string) error {
+	_, err := s.output(args...)
+	return err
+}
+
+func (s *tmuxService) output(args ...string) (string, error) {
+	return s.tmux(args...)
+}
+
+func runTmux(args ...string) (string, error) {
+	out, err := exec.Command("tmux", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("tmux %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
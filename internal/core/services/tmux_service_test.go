@@ -0,0 +1,147 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// fakeTmux records tmux invocations and answers them from canned output.
+type fakeTmux struct {
+	calls  [][]string
+	panes  string
+	output map[string]string
+}
+
+func (f *fakeTmux) run(args ...string) (string, error) {
+	f.calls = append(f.calls, args)
+	if args[0] == "list-panes" {
+		return f.panes, nil
+	}
+	return f.output[args[0]], nil
+}
+
+func newTmuxFixture(t *testing.T, tmux *fakeTmux) *tmuxService {
+	t.Helper()
+	// Available needs $TMUX and a tmux binary on PATH.
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "tmux"), []byte("#!/bin/sh\nexit 1\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	s := NewTmuxService(zap.NewNop().Sugar(), "/usr/bin/dogssh").(*tmuxService)
+	s.tmux = tmux.run
+	return s
+}
+
+func TestTmuxOpen(t *testing.T) {
+	listPanes := []string{"list-panes", "-s", "-F", "#{pane_id}\t#{@dogssh_alias}"}
+	tag := []string{"set-option", "-p", "-t", "%7", "@dogssh_alias", "web1"}
+	tests := []struct {
+		mode domain.TmuxMode
+		open []string
+	}{
+		{domain.TmuxModeWindow, []string{"new-window", "-n", "web1", "-P", "-F", "#{pane_id}", "/usr/bin/dogssh", "connect", "web1"}},
+		{domain.TmuxModeHSplit, []string{"split-window", "-h", "-P", "-F", "#{pane_id}", "/usr/bin/dogssh", "connect", "web1"}},
+		{domain.TmuxModeVSplit, []string{"split-window", "-v", "-P", "-F", "#{pane_id}", "/usr/bin/dogssh", "connect", "web1"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			tmux := &fakeTmux{
+				panes:  "%1\t\n%2\tdb1\n",
+				output: map[string]string{"new-window": "%7\n", "split-window": "%7\n"},
+			}
+			s := newTmuxFixture(t, tmux)
+			if err := s.Open("web1", tt.mode); err != nil {
+				t.Fatal(err)
+			}
+			if want := [][]string{listPanes, tt.open, tag}; !reflect.DeepEqual(tmux.calls, want) {
+				t.Fatalf("tmux calls = %q\nwant %q", tmux.calls, want)
+			}
+		})
+	}
+
+	s := newTmuxFixture(t, &fakeTmux{})
+	if err := s.Open("web1", domain.TmuxModeOff); err == nil {
+		t.Fatal("Open with tmux off succeeded")
+	}
+}
+
+func TestTmuxOpenReusesTaggedPane(t *testing.T) {
+	tmux := &fakeTmux{panes: "%1\t\n%4\tweb1\n%5\tweb10\n"}
+	s := newTmuxFixture(t, tmux)
+	if err := s.Open("web1", domain.TmuxModeWindow); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"list-panes", "-s", "-F", "#{pane_id}\t#{@dogssh_alias}"},
+		{"select-window", "-t", "%4"},
+		{"select-pane", "-t", "%4"},
+	}
+	if !reflect.DeepEqual(tmux.calls, want) {
+		t.Fatalf("tmux calls = %q\nwant %q", tmux.calls, want)
+	}
+}
+
+func TestTmuxOpenCluster(t *testing.T) {
+	tmux := &fakeTmux{output: map[string]string{"new-window": "@3\n"}}
+	s := newTmuxFixture(t, tmux)
+	if err := s.OpenCluster("cssh:web", []string{"web1", "web2", "web3"}); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"new-window", "-n", "cssh:web", "-P", "-F", "#{window_id}", "/usr/bin/dogssh", "connect", "web1"},
+		{"split-window", "-t", "@3", "/usr/bin/dogssh", "connect", "web2"},
+		{"select-layout", "-t", "@3", "tiled"},
+		{"split-window", "-t", "@3", "/usr/bin/dogssh", "connect", "web3"},
+		{"select-layout", "-t", "@3", "tiled"},
+		{"set-window-option", "-t", "@3", "synchronize-panes", "on"},
+	}
+	if !reflect.DeepEqual(tmux.calls, want) {
+		t.Fatalf("tmux calls = %q\nwant %q", tmux.calls, want)
+	}
+	for _, call := range tmux.calls {
+		if strings.Contains(strings.Join(call, " "), "@dogssh_alias") {
+			t.Fatalf("cluster pane tagged for reuse: %q", call)
+		}
+	}
+
+	if err := s.OpenCluster("empty", nil); err == nil {
+		t.Fatal("OpenCluster without servers succeeded")
+	}
+}
+
+func TestTmuxUnavailable(t *testing.T) {
+	tmux := &fakeTmux{}
+	s := newTmuxFixture(t, tmux)
+	t.Setenv("TMUX", "")
+	if err := s.Open("web1", domain.TmuxModeWindow); err == nil {
+		t.Fatal("Open outside tmux succeeded")
+	}
+	if err := s.OpenCluster("cssh", []string{"web1"}); err == nil {
+		t.Fatal("OpenCluster outside tmux succeeded")
+	}
+	if len(tmux.calls) != 0 {
+		t.Fatalf("tmux ran outside tmux: %q", tmux.calls)
+	}
+}
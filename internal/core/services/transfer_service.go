@@ -0,0 +1,237 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultTransferConcurrency is used when TransferOptions.Concurrency is not set.
+	defaultTransferConcurrency = 4
+	// transferRetryDelay grows linearly with each attempt.
+	transferRetryDelay = 2 * time.Second
+)
+
+type transferService struct {
+	logger *zap.SugaredLogger
+	files  ports.FileService
+}
+
+// NewTransferService creates a transfer queue on top of the file service.
+func NewTransferService(logger *zap.SugaredLogger, fs ports.FileService) ports.TransferService {
+	return &transferService{
+		logger: logger,
+		files:  fs,
+	}
+}
+
+// Run executes jobs with bounded concurrency. The returned jobs list failures
+// first, then successes, each ordered by alias.
+func (s *transferService) Run(ctx context.Context, jobs []domain.TransferJob, opts domain.TransferOptions, onUpdate func(domain.TransferJob)) []domain.TransferJob {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultTransferConcurrency
+	}
+	s.logger.Infow("transfer start", "jobs", len(jobs), "concurrency", concurrency, "retries", opts.Retries, "verify", opts.Verify)
+
+	results := make([]domain.TransferJob, len(jobs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	update := func(job domain.TransferJob) {
+		if onUpdate != nil {
+			mu.Lock()
+			onUpdate(job)
+			mu.Unlock()
+		}
+	}
+
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, job domain.TransferJob) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = s.runJob(ctx, job, opts, update)
+		}(i, job)
+	}
+	wg.Wait()
+
+	SortTransferJobs(results)
+	s.logger.Infow("transfer end", "jobs", len(jobs), "failed", countFailedJobs(results))
+	return results
+}
+
+// runJob copies one job, retrying on failure until the attempts are used up.
+func (s *transferService) runJob(ctx context.Context, job domain.TransferJob, opts domain.TransferOptions, update func(domain.TransferJob)) domain.TransferJob {
+	start := time.Now()
+	for {
+		job.Attempts++
+		job.State = domain.TransferRunning
+		job.Error = ""
+		job.Progress = domain.TransferProgress{}
+		update(job)
+
+		err := s.copyOnce(ctx, &job, opts.Verify, update)
+		job.Duration = time.Since(start)
+		if err == nil {
+			job.State = domain.TransferDone
+			update(job)
+			return job
+		}
+
+		job.Error = err.Error()
+		s.logger.Warnw("transfer attempt failed", "alias", job.Alias, "direction", job.Direction, "source", job.Source, "attempt", job.Attempts, "error", err)
+		if job.Attempts > opts.Retries || ctx.Err() != nil {
+			job.State = domain.TransferFailed
+			update(job)
+			return job
+		}
+
+		job.State = domain.TransferQueued
+		update(job)
+		select {
+		case <-ctx.Done():
+			job.State = domain.TransferFailed
+			job.Error = ctx.Err().Error()
+			update(job)
+			return job
+		case <-time.After(time.Duration(job.Attempts) * transferRetryDelay):
+		}
+	}
+}
+
+// copyOnce performs a single attempt of job and verifies checksums when asked.
+func (s *transferService) copyOnce(ctx context.Context, job *domain.TransferJob, verify bool, update func(domain.TransferJob)) error {
+	progress := func(p domain.TransferProgress) {
+		job.Progress = p
+		job.Bytes = p.Done
+		update(*job)
+	}
+
+	if job.Direction == domain.TransferPush {
+		source := ExpandHome(job.Source)
+		if err := s.files.Upload(ctx, job.Alias, source, job.Dest, progress); err != nil {
+			return err
+		}
+		if !verify {
+			return nil
+		}
+		want, err := localChecksums(source)
+		if err != nil {
+			return err
+		}
+		got, err := s.files.Checksums(ctx, job.Alias, path.Join(job.Dest, filepath.Base(source)))
+		return s.compareChecksums(job, want, got, err)
+	}
+
+	dest := ExpandHome(job.Dest)
+	if !verify {
+		return s.files.Download(ctx, job.Alias, job.Source, dest, progress)
+	}
+	// Pulls are verified against the archive as it was sent, so files that change on the
+	// server during the transfer, such as active logs, do not fail the check.
+	files, err := s.files.DownloadVerified(ctx, job.Alias, job.Source, dest, progress)
+	if err != nil {
+		return err
+	}
+	job.Files = files
+	return nil
+}
+
+// compareChecksums checks that every file in want arrived with the same content.
+// A server without sha256sum leaves the job unverified rather than failed.
+func (s *transferService) compareChecksums(job *domain.TransferJob, want, got map[string]string, remoteErr error) error {
+	if errors.Is(remoteErr, domain.ErrChecksumUnsupported) {
+		s.logger.Warnw("transfer not verified", "alias", job.Alias, "error", remoteErr)
+		job.Files = -1
+		return nil
+	}
+	if remoteErr != nil {
+		return remoteErr
+	}
+	for name, sum := range want {
+		if got[name] != sum {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+	}
+	job.Files = len(want)
+	return nil
+}
+
+// localChecksums returns the SHA-256 of every regular file under root, keyed
+// by the slash-separated path relative to root's parent.
+func localChecksums(root string) (map[string]string, error) {
+	parent := filepath.Dir(root)
+	sums := make(map[string]string)
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(parent, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p) //nolint:gosec // G304: the user picked this path
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		sums[filepath.ToSlash(rel)] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	return sums, err
+}
+
+// SortTransferJobs orders jobs with failures first, then by alias.
+func SortTransferJobs(jobs []domain.TransferJob) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		fi, fj := jobs[i].State == domain.TransferFailed, jobs[j].State == domain.TransferFailed
+		if fi != fj {
+			return fi
+		}
+		return jobs[i].Alias < jobs[j].Alias
+	})
+}
+
+func countFailedJobs(jobs []domain.TransferJob) int {
+	failed := 0
+	for _, job := range jobs {
+		if job.State == domain.TransferFailed {
+			failed++
+		}
+	}
+	return failed
+}
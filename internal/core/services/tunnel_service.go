@@ -0,0 +1,541 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+const (
+	tunnelRestartMinDelay = 2 * time.Second
+	tunnelRestartMaxDelay = 30 * time.Second
+	// tunnelStderrLimit caps the ssh output kept in memory for a tunnel.
+	tunnelStderrLimit = 4096
+)
+
+// tunnelProc tracks one tunnel; adopted tunnels were started by an earlier dogssh run.
+type tunnelProc struct {
+	status  domain.TunnelStatus
+	cancel  context.CancelFunc
+	adopted bool
+	// args are the ssh arguments of the running process, used to recognize it later.
+	args []string
+}
+
+// persistedTunnel is written to the state file for tunnels that outlive dogssh.
+type persistedTunnel struct {
+	Alias     string    `json:"alias"`
+	Name      string    `json:"name"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+	// Args are the ssh arguments; a process with another command line is not the tunnel.
+	Args []string `json:"args"`
+}
+
+type tunnelService struct {
+	logger           *zap.SugaredLogger
+	serverRepository ports.ServerRepository
+	statePath        string
+
+	mu       sync.Mutex
+	procs    map[string]*tunnelProc
+	onChange func()
+}
+
+// NewTunnelService creates a tunnel manager. Persistent tunnels are recorded in
+// statePath so a later run can show and stop them.
+func NewTunnelService(logger *zap.SugaredLogger, sr ports.ServerRepository, statePath string) ports.TunnelService {
+	s := &tunnelService{
+		logger:           logger,
+		serverRepository: sr,
+		statePath:        statePath,
+		procs:            make(map[string]*tunnelProc),
+	}
+	s.adoptPersisted()
+	return s
+}
+
+func tunnelKey(alias, name string) string {
+	return alias + "/" + name
+}
+
+// Start launches the tunnel and supervises it until stopped.
+func (s *tunnelService) Start(alias string, tunnel domain.Tunnel) error {
+	if err := tunnel.Validate(); err != nil {
+		return err
+	}
+
+	key := tunnelKey(alias, tunnel.Name)
+	s.mu.Lock()
+	if p, ok := s.procs[key]; ok && p.status.State != domain.TunnelStopped && p.status.State != domain.TunnelFailed {
+		s.mu.Unlock()
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.procs[key] = &tunnelProc{
+		status: domain.TunnelStatus{Alias: alias, Tunnel: tunnel, State: domain.TunnelRestarting},
+		cancel: cancel,
+	}
+	s.mu.Unlock()
+
+	started := make(chan error, 1)
+	go s.supervise(ctx, key, alias, tunnel, started)
+	return <-started
+}
+
+// supervise runs ssh -N for the tunnel, restarting it on drop when requested.
+// The first start result is reported on started.
+func (s *tunnelService) supervise(ctx context.Context, key, alias string, tunnel domain.Tunnel, started chan<- error) {
+	delay := tunnelRestartMinDelay
+	first := true
+
+	for {
+		args := []string{
+			"-N",
+			"-o", "ExitOnForwardFailure=yes",
+			"-o", "ServerAliveInterval=15",
+			"-o", "ServerAliveCountMax=3",
+		}
+		args = append(args, tunnel.ForwardArgs()...)
+		args = append(args, alias)
+
+		// The tunnel lifetime is managed via Stop, not the context, so persistent
+		// tunnels survive when dogssh exits.
+		cmd := batchSSH(context.Background(), s.serverRepository, alias, args...)
+		detachProcess(cmd, tunnel.Persistent)
+
+		// A persistent tunnel outlives dogssh, so its output goes to a file rather than
+		// a pipe that breaks when dogssh exits.
+		stderr := &tailBuffer{limit: tunnelStderrLimit}
+		var (
+			logFile   *os.File
+			logOffset int64
+			err       error
+		)
+		if tunnel.Persistent {
+			logFile, logOffset, err = s.openTunnelLog(alias, tunnel.Name)
+			cmd.Stderr = logFile
+		} else {
+			cmd.Stderr = stderr
+		}
+		if err == nil {
+			err = cmd.Start()
+		}
+		if logFile != nil {
+			// The child has its own copy of the descriptor.
+			_ = logFile.Close()
+		}
+		if err != nil {
+			s.setStatus(key, func(st *domain.TunnelStatus) {
+				st.State = domain.TunnelFailed
+				st.LastError = err.Error()
+			})
+			if first {
+				started <- fmt.Errorf("start tunnel %s: %w", tunnel.Name, err)
+			}
+			return
+		}
+
+		pid := cmd.Process.Pid
+		s.logger.Infow("tunnel started", "alias", alias, "tunnel", tunnel.Name, "pid", pid, "forward", tunnel.String())
+		s.mu.Lock()
+		if p, ok := s.procs[key]; ok {
+			p.args = args
+		}
+		s.mu.Unlock()
+		s.setStatus(key, func(st *domain.TunnelStatus) {
+			st.State = domain.TunnelRunning
+			st.PID = pid
+			st.StartedAt = time.Now()
+			st.LastError = ""
+		})
+		if tunnel.Persistent {
+			s.savePersisted()
+		}
+		if first {
+			started <- nil
+			first = false
+		}
+
+		exited := make(chan error, 1)
+		go func() { exited <- cmd.Wait() }()
+
+		var waitErr error
+		select {
+		case <-ctx.Done():
+			_ = terminateProcess(pid)
+			<-exited
+			s.setStatus(key, func(st *domain.TunnelStatus) {
+				st.State = domain.TunnelStopped
+				st.PID = 0
+			})
+			s.savePersisted()
+			s.logger.Infow("tunnel stopped", "alias", alias, "tunnel", tunnel.Name)
+			return
+		case waitErr = <-exited:
+		}
+
+		output := stderr.String()
+		if tunnel.Persistent {
+			output = readTail(s.tunnelLogPath(alias, tunnel.Name), logOffset, tunnelStderrLimit)
+		}
+		msg := lastLine(output)
+		if msg == "" && waitErr != nil {
+			msg = waitErr.Error()
+		}
+		s.logger.Warnw("tunnel exited", "alias", alias, "tunnel", tunnel.Name, "error", msg)
+
+		if !tunnel.AutoRestart {
+			s.setStatus(key, func(st *domain.TunnelStatus) {
+				st.State = domain.TunnelFailed
+				st.PID = 0
+				st.LastError = msg
+			})
+			s.savePersisted()
+			return
+		}
+
+		s.setStatus(key, func(st *domain.TunnelStatus) {
+			st.State = domain.TunnelRestarting
+			st.PID = 0
+			st.LastError = msg
+			st.Restarts++
+		})
+
+		select {
+		case <-ctx.Done():
+			s.setStatus(key, func(st *domain.TunnelStatus) { st.State = domain.TunnelStopped })
+			s.savePersisted()
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, tunnelRestartMaxDelay)
+	}
+}
+
+// Stop terminates the tunnel and disables auto-restart for it.
+func (s *tunnelService) Stop(alias, name string) error {
+	key := tunnelKey(alias, name)
+	s.mu.Lock()
+	p, ok := s.procs[key]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	if p.adopted {
+		// The process may have exited and its PID been reused since it was adopted.
+		if isTunnelProcess(p.status.PID, p.args) {
+			if err := terminateProcess(p.status.PID); err != nil {
+				return fmt.Errorf("stop tunnel %s: %w", name, err)
+			}
+		}
+		s.setStatus(key, func(st *domain.TunnelStatus) {
+			st.State = domain.TunnelStopped
+			st.PID = 0
+		})
+		s.savePersisted()
+		s.logger.Infow("tunnel stopped", "alias", alias, "tunnel", name)
+		return nil
+	}
+
+	p.cancel()
+	return nil
+}
+
+// Status returns the live status of a tunnel.
+func (s *tunnelService) Status(alias string, tunnel domain.Tunnel) domain.TunnelStatus {
+	s.mu.Lock()
+	p, ok := s.procs[tunnelKey(alias, tunnel.Name)]
+	s.mu.Unlock()
+	if !ok {
+		return domain.TunnelStatus{Alias: alias, Tunnel: tunnel, State: domain.TunnelStopped, Connections: -1}
+	}
+	return s.snapshot(p)
+}
+
+// Active returns every running or restarting tunnel ordered by alias and name.
+func (s *tunnelService) Active() []domain.TunnelStatus {
+	s.mu.Lock()
+	procs := make([]*tunnelProc, 0, len(s.procs))
+	for _, p := range s.procs {
+		procs = append(procs, p)
+	}
+	s.mu.Unlock()
+
+	active := make([]domain.TunnelStatus, 0, len(procs))
+	for _, p := range procs {
+		st := s.snapshot(p)
+		if st.State == domain.TunnelRunning || st.State == domain.TunnelRestarting {
+			active = append(active, st)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return tunnelKey(active[i].Alias, active[i].Tunnel.Name) < tunnelKey(active[j].Alias, active[j].Tunnel.Name)
+	})
+	return active
+}
+
+func (s *tunnelService) OnChange(fn func()) {
+	s.mu.Lock()
+	s.onChange = fn
+	s.mu.Unlock()
+}
+
+// Shutdown stops every tunnel that is not marked persistent.
+func (s *tunnelService) Shutdown() {
+	s.mu.Lock()
+	procs := make([]*tunnelProc, 0, len(s.procs))
+	for _, p := range s.procs {
+		if !p.status.Tunnel.Persistent && !p.adopted {
+			procs = append(procs, p)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, p := range procs {
+		p.cancel()
+	}
+	// Give ssh a moment to exit so no orphaned forwards survive.
+	deadline := time.Now().Add(2 * time.Second)
+	for _, p := range procs {
+		for time.Now().Before(deadline) {
+			s.mu.Lock()
+			state := p.status.State
+			s.mu.Unlock()
+			if state == domain.TunnelStopped || state == domain.TunnelFailed {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+}
+
+// snapshot copies a status, refreshing liveness of adopted tunnels and the connection count.
+func (s *tunnelService) snapshot(p *tunnelProc) domain.TunnelStatus {
+	s.mu.Lock()
+	if p.adopted && p.status.State == domain.TunnelRunning && !isTunnelProcess(p.status.PID, p.args) {
+		p.status.State = domain.TunnelStopped
+		p.status.PID = 0
+	}
+	st := p.status
+	s.mu.Unlock()
+
+	st.Connections = -1
+	if st.State == domain.TunnelRunning && st.Tunnel.Type != domain.TunnelRemote {
+		st.Connections = countEstablished(st.Tunnel.ListenPort)
+	}
+	return st
+}
+
+func (s *tunnelService) setStatus(key string, update func(*domain.TunnelStatus)) {
+	s.mu.Lock()
+	if p, ok := s.procs[key]; ok {
+		update(&p.status)
+	}
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// adoptPersisted picks up persistent tunnels left running by a previous run.
+func (s *tunnelService) adoptPersisted() {
+	data, err := os.ReadFile(s.statePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Warnw("failed to read tunnel state", "path", s.statePath, "error", err)
+		}
+		return
+	}
+	var persisted []persistedTunnel
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		s.logger.Warnw("failed to parse tunnel state", "path", s.statePath, "error", err)
+		return
+	}
+
+	tunnels := s.definedTunnels()
+	for _, pt := range persisted {
+		tunnel, ok := tunnels[tunnelKey(pt.Alias, pt.Name)]
+		if !ok || !isTunnelProcess(pt.PID, pt.Args) {
+			continue
+		}
+		s.procs[tunnelKey(pt.Alias, pt.Name)] = &tunnelProc{
+			adopted: true,
+			cancel:  func() {},
+			args:    pt.Args,
+			status: domain.TunnelStatus{
+				Alias:     pt.Alias,
+				Tunnel:    tunnel,
+				State:     domain.TunnelRunning,
+				PID:       pt.PID,
+				StartedAt: pt.StartedAt,
+			},
+		}
+		s.logger.Infow("adopted persistent tunnel", "alias", pt.Alias, "tunnel", pt.Name, "pid", pt.PID)
+	}
+}
+
+// definedTunnels indexes the tunnel definitions of all servers by alias/name.
+func (s *tunnelService) definedTunnels() map[string]domain.Tunnel {
+	tunnels := make(map[string]domain.Tunnel)
+	servers, err := s.serverRepository.ListServers()
+	if err != nil {
+		return tunnels
+	}
+	for _, server := range servers {
+		for _, t := range server.Tunnels {
+			tunnels[tunnelKey(server.Alias, t.Name)] = t
+		}
+	}
+	return tunnels
+}
+
+// savePersisted records running persistent tunnels in the state file.
+func (s *tunnelService) savePersisted() {
+	s.mu.Lock()
+	persisted := make([]persistedTunnel, 0)
+	for _, p := range s.procs {
+		if p.status.Tunnel.Persistent && p.status.State == domain.TunnelRunning && p.status.PID > 0 {
+			persisted = append(persisted, persistedTunnel{
+				Alias:     p.status.Alias,
+				Name:      p.status.Tunnel.Name,
+				PID:       p.status.PID,
+				StartedAt: p.status.StartedAt,
+				Args:      p.args,
+			})
+		}
+	}
+	s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.statePath), 0o750); err != nil {
+		s.logger.Warnw("failed to create tunnel state directory", "path", s.statePath, "error", err)
+		return
+	}
+	data, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		s.logger.Warnw("failed to marshal tunnel state", "error", err)
+		return
+	}
+	if err := os.WriteFile(s.statePath, data, 0o600); err != nil {
+		s.logger.Warnw("failed to write tunnel state", "path", s.statePath, "error", err)
+	}
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// isTunnelProcess reports whether pid is alive and still runs ssh with args. A state file
+// may outlive its processes, e.g. across a reboot, and the PID may now belong to another
+// program.
+func isTunnelProcess(pid int, args []string) bool {
+	if len(args) == 0 || !processAlive(pid) {
+		return false
+	}
+	return strings.HasSuffix(processCommandLine(pid), strings.Join(args, " "))
+}
+
+// tunnelLogPath is the file receiving the ssh output of a persistent tunnel.
+func (s *tunnelService) tunnelLogPath(alias, name string) string {
+	return filepath.Join(filepath.Dir(s.statePath), "tunnels", safeFileName(alias)+"-"+safeFileName(name)+".log")
+}
+
+// openTunnelLog opens the log of a persistent tunnel for appending and returns its
+// current size, where the output of the new process starts.
+func (s *tunnelService) openTunnelLog(alias, name string) (*os.File, int64, error) {
+	path := s.tunnelLogPath(alias, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, 0, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, err
+	}
+	return f, info.Size(), nil
+}
+
+// readTail returns at most limit bytes from the end of path, starting no earlier than offset.
+func readTail(path string, offset int64, limit int64) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return ""
+	}
+	start := max(offset, info.Size()-limit)
+	data := make([]byte, max(info.Size()-start, 0))
+	n, _ := f.ReadAt(data, start)
+	return string(data[:n])
+}
+
+// safeFileName replaces characters that are unsafe in file names.
+func safeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// tailBuffer keeps the last limit bytes written to it.
+type tailBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) >= b.limit {
+		b.buf.Reset()
+		p = p[len(p)-b.limit:]
+	} else if over := b.buf.Len() + len(p) - b.limit; over > 0 {
+		b.buf.Next(over)
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+func (b *tailBuffer) String() string {
+	return b.buf.String()
+}
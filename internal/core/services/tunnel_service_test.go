@@ -0,0 +1,67 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{limit: 8}
+	for _, s := range []string{"abc", "defg", "hij"} {
+		if n, err := b.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	if got := b.String(); got != "cdefghij" {
+		t.Fatalf("after small writes got %q", got)
+	}
+	_, _ = b.Write([]byte(strings.Repeat("x", 20) + "tail"))
+	if got := b.String(); got != "xxxxtail" {
+		t.Fatalf("after a large write got %q", got)
+	}
+}
+
+func TestIsTunnelProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process command lines are not read on Windows")
+	}
+	pid := os.Getpid()
+	if len(os.Args) > 1 && !isTunnelProcess(pid, os.Args[1:]) {
+		t.Fatalf("the test process was not recognized by its arguments %v", os.Args[1:])
+	}
+	if isTunnelProcess(pid, []string{"-N", "-L", "8080:localhost:80", "web1"}) {
+		t.Fatal("a process with other arguments was taken for the tunnel")
+	}
+	if isTunnelProcess(pid, nil) {
+		t.Fatal("a tunnel without recorded arguments must not match")
+	}
+}
+
+func TestReadTail(t *testing.T) {
+	path := t.TempDir() + "/tunnel.log"
+	if err := os.WriteFile(path, []byte("old run\nnew line 1\nnew line 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTail(path, 8, 100); got != "new line 1\nnew line 2\n" {
+		t.Fatalf("from offset got %q", got)
+	}
+	if got := readTail(path, 0, 11); got != "new line 2\n" {
+		t.Fatalf("limited got %q", got)
+	}
+}
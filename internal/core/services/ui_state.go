@@ -0,0 +1,76 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+type uiStateService struct {
+	logger   *zap.SugaredLogger
+	filePath string
+}
+
+// NewUIStateService creates a service for the UI state stored as JSON in filePath.
+func NewUIStateService(logger *zap.SugaredLogger, filePath string) ports.UIStateService {
+	return &uiStateService{logger: logger, filePath: filePath}
+}
+
+// Load returns the state saved by the last run. A missing file yields the zero state,
+// and a sort field this version does not know is dropped.
+func (s *uiStateService) Load() (domain.UIState, error) {
+	var state domain.UIState
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, fmt.Errorf("read UI state '%s': %w", s.filePath, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return domain.UIState{}, fmt.Errorf("parse UI state '%s': %w", s.filePath, err)
+	}
+	if !state.Sort.Field.Valid() {
+		state.Sort = domain.SortOrder{}
+	}
+	return state, nil
+}
+
+// Save replaces the stored state.
+func (s *uiStateService) Save(state domain.UIState) error {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0o700); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", filepath.Dir(s.filePath), err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal UI state: %w", err)
+	}
+	tmp := s.filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, s.filePath); err != nil {
+		return err
+	}
+	s.logger.Debugw("saved UI state", "path", s.filePath, "selected", state.Selected)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestUIStateService(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s := NewUIStateService(zap.NewNop().Sugar(), path)
+
+	state, err := s.Load()
+	if err != nil || state != (domain.UIState{}) {
+		t.Fatalf("Load() without a file = %+v, %v", state, err)
+	}
+
+	want := domain.UIState{
+		Selected: "web1",
+		Sort:     domain.SortOrder{Field: domain.SortFieldLastSeen, Descending: true},
+		Search:   "tag:prod",
+	}
+	if err := s.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := NewUIStateService(zap.NewNop().Sugar(), path).Load(); err != nil || got != want {
+		t.Fatalf("Load() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"selected":"db","sort":{"field":"size"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Load(); got.Selected != "db" || got.Sort != (domain.SortOrder{}) {
+		t.Fatalf("Load() with an unknown sort field = %+v", got)
+	}
+}
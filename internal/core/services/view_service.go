@@ -0,0 +1,158 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"github.com/ChengzeHsiao/dogssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+type viewService struct {
+	logger   *zap.SugaredLogger
+	filePath string
+	mu       sync.Mutex
+}
+
+// NewViewService creates a service for the saved views stored in filePath.
+func NewViewService(logger *zap.SugaredLogger, filePath string) ports.ViewService {
+	return &viewService{logger: logger, filePath: filePath}
+}
+
+// List returns the saved views in the order they were created.
+func (s *viewService) List() ([]domain.SavedView, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Save stores view, replacing the view called previous (or view.Name when previous is empty).
+func (s *viewService) Save(previous string, view domain.SavedView) error {
+	view.Name = strings.TrimSpace(view.Name)
+	view.Query = strings.TrimSpace(view.Query)
+	if view.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := ParseQuery(view.Query); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	if previous == "" {
+		previous = view.Name
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	views, err := s.load()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i, existing := range views {
+		switch {
+		case strings.EqualFold(existing.Name, previous):
+			views[i] = view
+			replaced = true
+		case strings.EqualFold(existing.Name, view.Name):
+			return fmt.Errorf("a view named '%s' already exists", view.Name)
+		}
+	}
+	if !replaced {
+		views = append(views, view)
+	}
+	return s.write(views)
+}
+
+// Delete removes the view called name.
+func (s *viewService) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	views, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := views[:0]
+	for _, view := range views {
+		if !strings.EqualFold(view.Name, name) {
+			kept = append(kept, view)
+		}
+	}
+	if len(kept) == len(views) {
+		return fmt.Errorf("view '%s' not found", name)
+	}
+	return s.write(kept)
+}
+
+// Counts returns how many of servers each view matches, in the order of views.
+// A view whose query no longer parses counts -1.
+func (s *viewService) Counts(views []domain.SavedView, servers []domain.Server) []int {
+	counts := make([]int, len(views))
+	for i, view := range views {
+		q, err := ParseQuery(view.Query)
+		if err != nil {
+			counts[i] = -1
+			continue
+		}
+		for _, server := range servers {
+			if _, ok := q.Match(server); ok {
+				counts[i]++
+			}
+		}
+	}
+	return counts
+}
+
+func (s *viewService) load() ([]domain.SavedView, error) {
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read views '%s': %w", s.filePath, err)
+	}
+	var views []domain.SavedView
+	if err := json.Unmarshal(data, &views); err != nil {
+		return nil, fmt.Errorf("parse views '%s': %w", s.filePath, err)
+	}
+	return views, nil
+}
+
+func (s *viewService) write(views []domain.SavedView) error {
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0o700); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", filepath.Dir(s.filePath), err)
+	}
+	if views == nil {
+		views = []domain.SavedView{}
+	}
+	data, err := json.MarshalIndent(views, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal views: %w", err)
+	}
+	tmp := s.filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, s.filePath); err != nil {
+		return err
+	}
+	s.logger.Infow("saved views updated", "path", s.filePath, "count", len(views))
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ChengzeHsiao/dogssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestViewServiceSaveAndDelete(t *testing.T) {
+	s := NewViewService(zap.NewNop().Sugar(), filepath.Join(t.TempDir(), "views.json"))
+
+	if err := s.Save("", domain.SavedView{Name: "prod", Query: "tag:prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("", domain.SavedView{Name: "db", Query: "tag:db"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("", domain.SavedView{Name: "bad", Query: "nope:x"}); err == nil {
+		t.Fatal("expected an invalid query to be rejected")
+	}
+	if err := s.Save("db", domain.SavedView{Name: "PROD", Query: "x"}); err == nil {
+		t.Fatal("expected a duplicate name to be rejected")
+	}
+	if err := s.Save("db", domain.SavedView{Name: "prod-db", Query: "tag:prod tag:db"}); err != nil {
+		t.Fatal(err)
+	}
+
+	views, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 2 || views[0].Name != "prod" || views[1].Name != "prod-db" {
+		t.Fatalf("views = %+v", views)
+	}
+
+	if err := s.Delete("Prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("prod"); err == nil {
+		t.Fatal("expected deleting a missing view to fail")
+	}
+	views, _ = s.List()
+	if len(views) != 1 || views[0].Name != "prod-db" {
+		t.Fatalf("views after delete = %+v", views)
+	}
+}
+
+func TestViewServiceCounts(t *testing.T) {
+	s := NewViewService(zap.NewNop().Sugar(), filepath.Join(t.TempDir(), "views.json"))
+	servers := []domain.Server{
+		{Alias: "web1", Tags: []string{"prod", "web"}},
+		{Alias: "db1", Tags: []string{"prod", "db"}},
+		{Alias: "dev1", Tags: []string{"dev"}},
+	}
+	views := []domain.SavedView{
+		{Name: "prod", Query: "tag:prod"},
+		{Name: "prod-db", Query: "tag:prod tag:db"},
+		{Name: "broken", Query: "(tag:prod"},
+	}
+	got := s.Counts(views, servers)
+	want := []int{2, 1, -1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("counts = %v, want %v", got, want)
+		}
+	}
+}
@@ -23,12 +23,19 @@ import (
 )
 
 // New constructs a Sugared Logger that writes to a file and
-// provides human-readable timestamps.
-func New(service string, outputPaths ...string) (*zap.SugaredLogger, error) {
+// provides human-readable timestamps. An empty level logs everything from debug up.
+func New(service, level string, outputPaths ...string) (*zap.SugaredLogger, error) {
 	config := zap.NewProductionConfig()
 
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	if level != "" {
+		l, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		config.Level = zap.NewAtomicLevelAt(l)
+	}
 
 	config.DisableStacktrace = true
 	config.InitialFields = map[string]any{
@@ -46,6 +53,14 @@ func New(service string, outputPaths ...string) (*zap.SugaredLogger, error) {
 	config.OutputPaths = []string{filepath.Join(logDir, "dogssh.log")}
 
 	if outputPaths != nil {
+		for _, p := range outputPaths {
+			if p == "stdout" || p == "stderr" {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+				return nil, err
+			}
+		}
 		config.OutputPaths = outputPaths
 	}
 